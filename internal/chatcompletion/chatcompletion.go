@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
 	"time"
 
 	"github.com/Armatorix/GoRetro/internal/models"
@@ -71,13 +72,13 @@ type AutoMergeResponse struct {
 }
 
 // SuggestMerges uses AI to suggest which tickets should be merged together
-func (s *Service) SuggestMerges(tickets map[string]*models.Ticket) (*AutoMergeResponse, error) {
+func (s *Service) SuggestMerges(tickets map[string]*models.Ticket, columns []models.Column) (*AutoMergeResponse, error) {
 	if !s.IsConfigured() {
 		return nil, fmt.Errorf("chat completion service not configured")
 	}
 
 	// Build the prompt with ticket information
-	prompt := s.buildMergePrompt(tickets)
+	prompt := s.buildMergePrompt(tickets, columns)
 
 	// Create the chat completion request
 	reqBody := ChatCompletionRequest{
@@ -140,19 +141,22 @@ func (s *Service) SuggestMerges(tickets map[string]*models.Ticket) (*AutoMergeRe
 }
 
 // buildMergePrompt creates a prompt for the AI to analyze tickets
-func (s *Service) buildMergePrompt(tickets map[string]*models.Ticket) string {
-	prompt := "Here are the retrospective tickets that need to be analyzed for potential merging:\n\n"
-
-	for id, ticket := range tickets {
-		// Skip tickets that are already merged (have a parent)
-		if ticket.DeduplicationTicketID != nil {
-			continue
+func (s *Service) buildMergePrompt(tickets map[string]*models.Ticket, columns []models.Column) string {
+	prompt := "Here are the retrospective tickets that need to be analyzed for potential merging, grouped by board column:\n\n"
+
+	for _, group := range groupByColumn(tickets, columns) {
+		prompt += group.header("Column")
+		for _, ticket := range group.tickets {
+			// Skip tickets that are already merged (have a parent)
+			if ticket.DeduplicationTicketID != nil {
+				continue
+			}
+			prompt += fmt.Sprintf("Ticket ID: %s\nContent: %s\n\n", ticket.ID, ticket.Content)
 		}
-		prompt += fmt.Sprintf("Ticket ID: %s\nContent: %s\n\n", id, ticket.Content)
 	}
 
 	prompt += `Please analyze these tickets and suggest which ones should be merged together based on content similarity. 
-Group tickets that discuss the same topic or issue. For each group:
+Group tickets that discuss the same topic or issue. Only merge tickets from the same column. For each group:
 1. Select the most representative ticket as the parent_ticket_id
 2. List other similar tickets as child_ticket_ids
 3. Provide a brief reason for the grouping
@@ -186,13 +190,13 @@ type AutoProposeActionsResponse struct {
 }
 
 // ProposeActions uses AI to suggest action items based on tickets
-func (s *Service) ProposeActions(tickets map[string]*models.Ticket, teamContext, language string, sarcastic bool) (*AutoProposeActionsResponse, error) {
+func (s *Service) ProposeActions(tickets map[string]*models.Ticket, columns []models.Column, teamContext, language string, sarcastic bool) (*AutoProposeActionsResponse, error) {
 	if !s.IsConfigured() {
 		return nil, fmt.Errorf("chat completion service not configured")
 	}
 
 	// Build the prompt with ticket information
-	prompt := s.buildActionProposalPrompt(tickets, teamContext, language, sarcastic)
+	prompt := s.buildActionProposalPrompt(tickets, columns, teamContext, language, sarcastic)
 	systemPrompt := s.buildActionProposalSystemPrompt(language, sarcastic)
 
 	// Create the chat completion request
@@ -281,20 +285,25 @@ func (s *Service) buildActionProposalSystemPrompt(language string, sarcastic boo
 }
 
 // buildActionProposalPrompt creates a prompt for the AI to suggest action items
-func (s *Service) buildActionProposalPrompt(tickets map[string]*models.Ticket, teamContext, language string, sarcastic bool) string {
+func (s *Service) buildActionProposalPrompt(tickets map[string]*models.Ticket, columns []models.Column, teamContext, language string, sarcastic bool) string {
 	// Localize the header
-	headerText := "Here are the retrospective tickets from the team:\n\n"
+	headerText := "Here are the retrospective tickets from the team, grouped by board column:\n\n"
+	columnLabel := "Column"
 	if language == "pl" {
-		headerText = "Oto zgłoszenia retrospektywne od zespołu:\n\n"
+		headerText = "Oto zgłoszenia retrospektywne od zespołu, pogrupowane według kolumn tablicy:\n\n"
+		columnLabel = "Kolumna"
 	}
 	prompt := headerText
 
-	for id, ticket := range tickets {
-		// Skip child tickets (already merged)
-		if ticket.DeduplicationTicketID != nil {
-			continue
+	for _, group := range groupByColumn(tickets, columns) {
+		prompt += group.header(columnLabel)
+		for _, ticket := range group.tickets {
+			// Skip child tickets (already merged)
+			if ticket.DeduplicationTicketID != nil {
+				continue
+			}
+			prompt += fmt.Sprintf("Ticket ID: %s\nContent: %s\nVotes: %d\nCovered: %v\n\n", ticket.ID, ticket.Content, ticket.Votes, ticket.Covered)
 		}
-		prompt += fmt.Sprintf("Ticket ID: %s\nContent: %s\nVotes: %d\nCovered: %v\n\n", id, ticket.Content, ticket.Votes, ticket.Covered)
 	}
 
 	if teamContext != "" {
@@ -351,3 +360,52 @@ Skup się na najważniejszych problemach (szczególnie tych z większą liczbą
 
 	return prompt
 }
+
+// ticketGroup is the tickets of one board column, in the order they were written
+type ticketGroup struct {
+	title   string
+	tickets []*models.Ticket
+}
+
+// header introduces the group in a prompt. Tickets without a column get no header.
+func (g ticketGroup) header(label string) string {
+	if g.title == "" {
+		return ""
+	}
+	return fmt.Sprintf("## %s: %s\n\n", label, g.title)
+}
+
+// groupByColumn groups tickets by column in the room's column order. Tickets
+// without a known column come last, in a group without a title. Empty groups are left out.
+func groupByColumn(tickets map[string]*models.Ticket, columns []models.Column) []ticketGroup {
+	groups := make([]ticketGroup, len(columns)+1)
+	index := make(map[string]int, len(columns))
+	for i, column := range columns {
+		groups[i].title = column.Title
+		index[column.ID] = i
+	}
+
+	sorted := make([]*models.Ticket, 0, len(tickets))
+	for _, ticket := range tickets {
+		sorted = append(sorted, ticket)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+
+	for _, ticket := range sorted {
+		i, ok := index[ticket.ColumnID]
+		if !ok {
+			i = len(columns)
+		}
+		groups[i].tickets = append(groups[i].tickets, ticket)
+	}
+
+	nonEmpty := groups[:0]
+	for _, group := range groups {
+		if len(group.tickets) > 0 {
+			nonEmpty = append(nonEmpty, group)
+		}
+	}
+	return nonEmpty
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/Armatorix/GoRetro/internal/models"
+)
+
+// GetAnalytics returns trends across retrospectives: per-room figures,
+// totals, the vote distribution and recurring phrases. Without a team, it
+// covers the rooms the user owns; a team's rooms are only open to its
+// members. The from and to parameters are dates (2006-01-02), both inclusive.
+func (h *Handler) GetAnalytics(c echo.Context) error {
+	filter, status, msg := h.analyticsFilter(c)
+	if status != 0 {
+		return c.JSON(status, map[string]string{"error": msg})
+	}
+
+	analytics, err := h.store.Analytics(filter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to compute analytics"})
+	}
+	return c.JSON(http.StatusOK, analytics)
+}
+
+// GetHealthHistory compares the results of health check rooms over time,
+// selected with the same parameters as GetAnalytics
+func (h *Handler) GetHealthHistory(c echo.Context) error {
+	filter, status, msg := h.analyticsFilter(c)
+	if status != 0 {
+		return c.JSON(status, map[string]string{"error": msg})
+	}
+
+	history, err := h.store.HealthHistory(filter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to load health checks"})
+	}
+	return c.JSON(http.StatusOK, history)
+}
+
+// analyticsFilter reads the rooms to analyze from the owner, team, from and
+// to query parameters, or returns the status and message to respond with
+// when they are invalid or not open to the user
+func (h *Handler) analyticsFilter(c echo.Context) (models.AnalyticsFilter, int, string) {
+	user := getUserFromRequest(c)
+
+	filter := models.AnalyticsFilter{
+		OwnerID: c.QueryParam("owner"),
+		TeamID:  c.QueryParam("team"),
+	}
+	if filter.TeamID != "" {
+		team, ok := h.store.GetTeam(filter.TeamID)
+		if !ok {
+			return filter, http.StatusNotFound, "Team not found"
+		}
+		if !team.IsMember(user.ID) {
+			return filter, http.StatusForbidden, "Only team members can view the team's analytics"
+		}
+	} else {
+		if filter.OwnerID != "" && filter.OwnerID != user.ID {
+			return filter, http.StatusForbidden, "Only your own rooms or your team's rooms can be analyzed"
+		}
+		filter.OwnerID = user.ID
+	}
+
+	if from := c.QueryParam("from"); from != "" {
+		date, err := time.Parse(models.DueDateLayout, from)
+		if err != nil {
+			return filter, http.StatusBadRequest, "Invalid from parameter"
+		}
+		filter.From = date
+	}
+	if to := c.QueryParam("to"); to != "" {
+		date, err := time.Parse(models.DueDateLayout, to)
+		if err != nil {
+			return filter, http.StatusBadRequest, "Invalid to parameter"
+		}
+		filter.To = date.AddDate(0, 0, 1)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return filter, http.StatusBadRequest, "from must not be after to"
+	}
+	return filter, 0, ""
+}
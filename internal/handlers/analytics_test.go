@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Armatorix/GoRetro/internal/models"
+)
+
+func TestGetAnalytics_Filter(t *testing.T) {
+	h, store := newTestHandler()
+	createTestTeam(t, store, "alice", "bob")
+
+	rooms := []struct {
+		id, ownerID, teamID, created string
+	}{
+		{id: "alice-march", ownerID: "alice", created: "2026-03-10"},
+		{id: "bob-team", ownerID: "bob", teamID: "team-1", created: "2026-03-20"},
+		{id: "alice-team", ownerID: "alice", teamID: "team-1", created: "2026-04-05"},
+		{id: "carol", ownerID: "carol", created: "2026-03-15"},
+	}
+	for _, r := range rooms {
+		room := models.NewRoom(r.id, r.id, r.ownerID, 3)
+		room.TeamID = r.teamID
+		created, _ := time.Parse(models.DueDateLayout, r.created)
+		room.CreatedAt = created.Add(12 * time.Hour)
+		room.AddParticipant(models.User{ID: r.ownerID, Name: r.ownerID}, models.RoleOwner, models.StatusApproved)
+		if err := store.Create(room); err != nil {
+			t.Fatalf("Failed to create room: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		userID    string
+		query     string
+		want      int
+		wantRooms []string
+	}{
+		{name: "own rooms by default", userID: "alice", want: http.StatusOK, wantRooms: []string{"alice-march", "alice-team"}},
+		{name: "owner is the caller", userID: "alice", query: "owner=alice", want: http.StatusOK, wantRooms: []string{"alice-march", "alice-team"}},
+		{name: "owner is someone else", userID: "alice", query: "owner=bob", want: http.StatusForbidden},
+		{name: "team member", userID: "bob", query: "team=team-1", want: http.StatusOK, wantRooms: []string{"bob-team", "alice-team"}},
+		{name: "team and owner", userID: "bob", query: "team=team-1&owner=alice", want: http.StatusOK, wantRooms: []string{"alice-team"}},
+		{name: "not a team member", userID: "carol", query: "team=team-1", want: http.StatusForbidden},
+		{name: "unknown team", userID: "alice", query: "team=missing", want: http.StatusNotFound},
+		{name: "date range", userID: "alice", query: "from=2026-03-01&to=2026-03-31", want: http.StatusOK, wantRooms: []string{"alice-march"}},
+		{name: "single day", userID: "alice", query: "from=2026-04-05&to=2026-04-05", want: http.StatusOK, wantRooms: []string{"alice-team"}},
+		{name: "reversed date range", userID: "alice", query: "from=2026-04-01&to=2026-03-01", want: http.StatusBadRequest},
+		{name: "invalid from", userID: "alice", query: "from=03/01/2026", want: http.StatusBadRequest},
+		{name: "invalid to", userID: "alice", query: "to=2026-13-01", want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, h.GetAnalytics, http.MethodGet, "/api/analytics?"+tt.query, tt.userID, nil)
+			if rec.Code != tt.want {
+				t.Fatalf("Expected status %d, got %d: %s", tt.want, rec.Code, rec.Body.String())
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+
+			var analytics models.Analytics
+			decode(t, rec, &analytics)
+			var got []string
+			for _, stats := range analytics.Rooms {
+				got = append(got, stats.RoomID)
+			}
+			if !slices.Equal(got, tt.wantRooms) {
+				t.Errorf("Expected rooms %v, got %v", tt.wantRooms, got)
+			}
+		})
+	}
+}
+
+func TestGetHealthHistory_TeamMembersOnly(t *testing.T) {
+	h, store := newTestHandler()
+	createTestTeam(t, store, "alice", "bob")
+
+	rec := serve(t, h.GetHealthHistory, http.MethodGet, "/api/health-checks?team=team-1", "carol", nil)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Expected status %d, got %d", http.StatusForbidden, rec.Code)
+	}
+	rec = serve(t, h.GetHealthHistory, http.MethodGet, "/api/health-checks?team=team-1", "bob", nil)
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+}
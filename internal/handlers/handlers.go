@@ -1,7 +1,11 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -25,14 +29,14 @@ var upgrader = gorillaWS.Upgrader{
 
 // Handler contains all HTTP handlers
 type Handler struct {
-	store        *models.RoomStore
+	store        models.Store
 	hub          *websocket.Hub
 	chatEndpoint string
 	chatAPIKey   string
 }
 
 // NewHandler creates a new handler
-func NewHandler(store *models.RoomStore, hub *websocket.Hub, chatEndpoint, chatAPIKey string) *Handler {
+func NewHandler(store models.Store, hub *websocket.Hub, chatEndpoint, chatAPIKey string) *Handler {
 	return &Handler{
 		store:        store,
 		hub:          hub,
@@ -79,8 +83,16 @@ func getUserFromRequest(c echo.Context) models.User {
 
 // CreateRoomRequest is the request body for creating a room
 type CreateRoomRequest struct {
-	Name         string `json:"name" form:"name"`
-	VotesPerUser int    `json:"votes_per_user" form:"votes_per_user"`
+	Name              string         `json:"name" form:"name"`
+	VotesPerUser      int            `json:"votes_per_user" form:"votes_per_user"`
+	MaxVotesPerTicket int            `json:"max_votes_per_ticket" form:"max_votes_per_ticket"`
+	HiddenVotes       bool           `json:"hidden_votes" form:"hidden_votes"`
+	AnonymousTickets  bool           `json:"anonymous_tickets" form:"anonymous_tickets"`
+	BlindTickets      bool           `json:"blind_tickets" form:"blind_tickets"`
+	TemplateID        string         `json:"template_id" form:"template_id"`
+	Phases            []models.Phase `json:"phases" form:"phases"`
+	PreviousRoomID    string         `json:"previous_room_id" form:"previous_room_id"`
+	TeamID            string         `json:"team_id" form:"team_id"`
 }
 
 // RoomResponse is the response for room endpoints
@@ -90,16 +102,40 @@ type RoomResponse struct {
 	Phase        models.Phase `json:"phase"`
 	VotesPerUser int          `json:"votes_per_user"`
 	OwnerID      string       `json:"owner_id"`
+	TeamID       string       `json:"team_id,omitempty"`
 	CreatedAt    time.Time    `json:"created_at"`
 }
 
+// newRoomResponse describes a room for the room endpoints
+func newRoomResponse(room *models.Room) RoomResponse {
+	return RoomResponse{
+		ID:           room.ID,
+		Name:         room.Name,
+		Phase:        room.Phase,
+		VotesPerUser: room.VotesPerUser,
+		OwnerID:      room.OwnerID,
+		TeamID:       room.TeamID,
+		CreatedAt:    room.CreatedAt,
+	}
+}
+
 // Index renders the home page
 func (h *Handler) Index(c echo.Context) error {
 	user := getUserFromRequest(c)
 	rooms := h.store.ListByParticipant(user.ID)
+	templates, err := h.listTemplates(user.ID)
+	if err != nil {
+		templates = models.BuiltinTemplates()
+	}
+	teams, err := h.store.ListTeamsByMember(user.ID)
+	if err != nil {
+		teams = []*models.Team{}
+	}
 	return c.Render(http.StatusOK, "index.html", map[string]any{
-		"User":  user,
-		"Rooms": rooms,
+		"User":      user,
+		"Rooms":     rooms,
+		"Templates": templates,
+		"Teams":     teams,
 	})
 }
 
@@ -112,15 +148,76 @@ func (h *Handler) CreateRoom(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
 	}
 
+	// Members of the room's team are approved without asking
+	if req.TeamID != "" {
+		team, ok := h.store.GetTeam(req.TeamID)
+		if !ok {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Team not found"})
+		}
+		if !team.IsMember(user.ID) {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "Only team members can create rooms for the team"})
+		}
+	}
+
+	// A template provides the columns and the default voting limits
+	var template *models.Template
+	if req.TemplateID != "" {
+		var ok bool
+		if template, ok = h.findTemplate(req.TemplateID, user.ID); !ok {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Template not found"})
+		}
+	}
+
 	if req.Name == "" {
 		req.Name = "Retrospective"
 	}
 	if req.VotesPerUser <= 0 {
 		req.VotesPerUser = 3
+		if template != nil {
+			req.VotesPerUser = template.VotesPerUser
+		}
+	}
+	if req.MaxVotesPerTicket <= 0 {
+		req.MaxVotesPerTicket = 1
+		if template != nil {
+			req.MaxVotesPerTicket = template.MaxVotesPerTicket
+		}
 	}
+	req.MaxVotesPerTicket = min(req.MaxVotesPerTicket, req.VotesPerUser)
 
 	roomID := uuid.New().String()
 	room := models.NewRoom(roomID, req.Name, user.ID, req.VotesPerUser)
+	room.MaxVotesPerTicket = req.MaxVotesPerTicket
+	if template != nil {
+		if err := template.ApplyTo(room); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid template: " + err.Error()})
+		}
+	}
+	if len(req.Phases) > 0 {
+		if err := room.ConfigurePhases(req.Phases); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid phases: " + err.Error()})
+		}
+	}
+	room.HiddenVotes = req.HiddenVotes
+	room.AnonymousTickets = req.AnonymousTickets
+	room.BlindTickets = req.BlindTickets
+	room.TeamID = req.TeamID
+
+	// Unfinished actions of the previous room are carried over for review
+	if req.PreviousRoomID != "" {
+		previous, ok := h.store.Get(req.PreviousRoomID)
+		if !ok {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Previous room not found"})
+		}
+		if _, ok := previous.GetParticipant(user.ID); !ok {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "Only participants of the previous room can carry over its actions"})
+		}
+		previous, err := h.markCarriedOver(previous, room.ID)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to carry over actions"})
+		}
+		room.CarryOverActions(previous, func() string { return uuid.New().String() })
+	}
 	room.AddParticipant(user, models.RoleOwner, models.StatusApproved)
 
 	if err := h.store.Create(room); err != nil {
@@ -129,14 +226,7 @@ func (h *Handler) CreateRoom(c echo.Context) error {
 
 	// Check if it's an AJAX request or form submission
 	if c.Request().Header.Get("Accept") == "application/json" {
-		return c.JSON(http.StatusCreated, RoomResponse{
-			ID:           room.ID,
-			Name:         room.Name,
-			Phase:        room.Phase,
-			VotesPerUser: room.VotesPerUser,
-			OwnerID:      room.OwnerID,
-			CreatedAt:    room.CreatedAt,
-		})
+		return c.JSON(http.StatusCreated, newRoomResponse(room))
 	}
 
 	return c.Redirect(http.StatusSeeOther, "/rooms/"+room.ID)
@@ -149,14 +239,7 @@ func (h *Handler) ListRooms(c echo.Context) error {
 
 	response := make([]RoomResponse, 0, len(rooms))
 	for _, room := range rooms {
-		response = append(response, RoomResponse{
-			ID:           room.ID,
-			Name:         room.Name,
-			Phase:        room.Phase,
-			VotesPerUser: room.VotesPerUser,
-			OwnerID:      room.OwnerID,
-			CreatedAt:    room.CreatedAt,
-		})
+		response = append(response, newRoomResponse(room))
 	}
 
 	return c.JSON(http.StatusOK, response)
@@ -173,19 +256,9 @@ func (h *Handler) GetRoom(c echo.Context) error {
 	}
 
 	// Add user as pending participant if not already a participant or pending
-	if _, exists := room.GetParticipant(user.ID); !exists {
-		if _, pendingExists := room.GetPendingParticipant(user.ID); !pendingExists {
-			// Auto-approve if the setting is enabled
-			if room.AutoApprove {
-				room.AddParticipant(user, models.RoleParticipant, models.StatusApproved)
-			} else {
-				room.AddParticipant(user, models.RoleParticipant, models.StatusPending)
-			}
-			// Update room in store
-			if err := h.store.Update(room); err != nil {
-				return c.String(http.StatusInternalServerError, "Failed to update room")
-			}
-		}
+	room, err := h.joinRoom(room, user)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to update room")
 	}
 
 	return c.Render(http.StatusOK, "room.html", map[string]any{
@@ -203,13 +276,215 @@ func (h *Handler) GetRoomAPI(c echo.Context) error {
 		return c.JSON(http.StatusNotFound, map[string]string{"error": "Room not found"})
 	}
 
-	return c.JSON(http.StatusOK, RoomResponse{
-		ID:           room.ID,
-		Name:         room.Name,
-		Phase:        room.Phase,
-		VotesPerUser: room.VotesPerUser,
-		OwnerID:      room.OwnerID,
-		CreatedAt:    room.CreatedAt,
+	return c.JSON(http.StatusOK, newRoomResponse(room))
+}
+
+// Paging limits for the room event log
+const (
+	defaultEventsLimit = 100
+	maxEventsLimit     = 500
+)
+
+// RoomEventsResponse is a page of a room's event log. NextAfter is set when
+// more events may follow and is passed as the after parameter to fetch them.
+type RoomEventsResponse struct {
+	Events    []*models.RoomEvent `json:"events"`
+	NextAfter *int64              `json:"next_after,omitempty"`
+}
+
+// GetRoomEvents returns the room's event log, oldest first, for moderators and the owner
+func (h *Handler) GetRoomEvents(c echo.Context) error {
+	roomID := c.Param("id")
+	user := getUserFromRequest(c)
+
+	room, ok := h.store.Get(roomID)
+	if !ok {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Room not found"})
+	}
+
+	if !room.IsModeratorOrOwner(user.ID) {
+		return c.JSON(http.StatusForbidden, map[string]string{"error": "Only moderators can view the room timeline"})
+	}
+
+	var afterID int64
+	if after := c.QueryParam("after"); after != "" {
+		id, err := strconv.ParseInt(after, 10, 64)
+		if err != nil || id < 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid after parameter"})
+		}
+		afterID = id
+	}
+
+	limit := defaultEventsLimit
+	if l := c.QueryParam("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 1 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid limit parameter"})
+		}
+		limit = min(n, maxEventsLimit)
+	}
+
+	events, err := h.store.ListEvents(roomID, afterID, limit)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to load events"})
+	}
+
+	// Anonymous authors, hidden votes and blind tickets stay hidden in the timeline too
+	visible := make([]*models.RoomEvent, 0, len(events))
+	room.RLock()
+	for _, event := range events {
+		if view := room.EventView(event, user.ID); view != nil {
+			visible = append(visible, view)
+		}
+	}
+	room.RUnlock()
+
+	// Paging goes by the events read, hidden ones included
+	response := RoomEventsResponse{Events: visible}
+	if len(events) == limit {
+		next := events[len(events)-1].ID
+		response.NextAfter = &next
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// ExportTicket is a ticket in a room export. Author fields are empty when
+// the author is hidden from the exporting user.
+type ExportTicket struct {
+	ID             string          `json:"id"`
+	Content        string          `json:"content"`
+	AuthorID       string          `json:"author_id,omitempty"`
+	AuthorName     string          `json:"author_name,omitempty"`
+	ParentTicketID *string         `json:"parent_ticket_id,omitempty"`
+	Votes          int             `json:"votes"`
+	Covered        bool            `json:"covered"`
+	CreatedAt      time.Time       `json:"created_at"`
+	Comments       []ExportComment `json:"comments"`
+}
+
+// ExportComment is a comment on a ticket in a room export. Author fields are
+// empty when the author is hidden from the exporting user.
+type ExportComment struct {
+	AuthorID   string     `json:"author_id,omitempty"`
+	AuthorName string     `json:"author_name,omitempty"`
+	Content    string     `json:"content"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+}
+
+// ExportColumn is a board column with its tickets in a room export
+type ExportColumn struct {
+	models.Column
+	Tickets []ExportTicket `json:"tickets"`
+}
+
+// RoomExport is the downloadable result of a retrospective. Tickets holds
+// the tickets that are not in any column.
+type RoomExport struct {
+	ID         string                 `json:"id"`
+	Name       string                 `json:"name"`
+	Phase      models.Phase           `json:"phase"`
+	CreatedAt  time.Time              `json:"created_at"`
+	ExportedAt time.Time              `json:"exported_at"`
+	Columns    []ExportColumn         `json:"columns"`
+	Tickets    []ExportTicket         `json:"tickets"`
+	Actions    []*models.ActionTicket `json:"actions"`
+	Polls      []models.PollView      `json:"polls"`
+	// HealthCheck is only set for health check rooms
+	HealthCheck *models.HealthCheckView `json:"health_check,omitempty"`
+}
+
+// ExportRoom returns the room's tickets, grouped by column, actions and polls
+// as a JSON download for approved participants. Tickets and polls are exported
+// as the user sees them in the room.
+func (h *Handler) ExportRoom(c echo.Context) error {
+	roomID := c.Param("id")
+	user := getUserFromRequest(c)
+
+	room, ok := h.store.Get(roomID)
+	if !ok {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Room not found"})
+	}
+
+	if _, ok := room.GetParticipant(user.ID); !ok {
+		return c.JSON(http.StatusForbidden, map[string]string{"error": "Only participants can export the room"})
+	}
+
+	room.RLock()
+	export := RoomExport{
+		ID:          room.ID,
+		Name:        room.Name,
+		Phase:       room.Phase,
+		CreatedAt:   room.CreatedAt,
+		ExportedAt:  time.Now(),
+		Columns:     make([]ExportColumn, 0, len(room.Columns)),
+		Tickets:     []ExportTicket{},
+		Actions:     make([]*models.ActionTicket, 0, len(room.ActionTickets)),
+		Polls:       room.PollViews(user.ID),
+		HealthCheck: room.HealthCheckView(user.ID),
+	}
+	columnIndex := make(map[string]int, len(room.Columns))
+	for i, column := range room.Columns {
+		export.Columns = append(export.Columns, ExportColumn{Column: column, Tickets: []ExportTicket{}})
+		columnIndex[column.ID] = i
+	}
+	for _, ticket := range room.TicketViews(user.ID) {
+		exported := ExportTicket{
+			ID:             ticket.ID,
+			Content:        ticket.Content,
+			AuthorID:       ticket.AuthorID,
+			ParentTicketID: ticket.DeduplicationTicketID,
+			Votes:          ticket.Votes,
+			Covered:        ticket.Covered,
+			CreatedAt:      ticket.CreatedAt,
+			Comments:       make([]ExportComment, 0, len(ticket.Comments)),
+		}
+		if author, ok := room.Participants[ticket.AuthorID]; ok {
+			exported.AuthorName = author.User.Name
+		}
+		for _, comment := range ticket.Comments {
+			exportedComment := ExportComment{
+				AuthorID:  comment.AuthorID,
+				Content:   comment.Content,
+				CreatedAt: comment.CreatedAt,
+				UpdatedAt: comment.UpdatedAt,
+			}
+			if author, ok := room.Participants[comment.AuthorID]; ok {
+				exportedComment.AuthorName = author.User.Name
+			}
+			exported.Comments = append(exported.Comments, exportedComment)
+		}
+		if i, ok := columnIndex[ticket.ColumnID]; ok {
+			export.Columns[i].Tickets = append(export.Columns[i].Tickets, exported)
+		} else {
+			export.Tickets = append(export.Tickets, exported)
+		}
+	}
+	for _, action := range room.ActionTickets {
+		export.Actions = append(export.Actions, action)
+	}
+	room.RUnlock()
+
+	sortExportTickets(export.Tickets)
+	for _, column := range export.Columns {
+		sortExportTickets(column.Tickets)
+	}
+	sort.Slice(export.Actions, func(i, j int) bool {
+		return export.Actions[i].CreatedAt.Before(export.Actions[j].CreatedAt)
+	})
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="retro-%s.json"`, room.ID))
+	return c.JSON(http.StatusOK, export)
+}
+
+// sortExportTickets orders tickets by votes, most voted first, then by age
+func sortExportTickets(tickets []ExportTicket) {
+	sort.Slice(tickets, func(i, j int) bool {
+		a, b := tickets[i], tickets[j]
+		if a.Votes != b.Votes {
+			return a.Votes > b.Votes
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
 	})
 }
 
@@ -235,6 +510,85 @@ func (h *Handler) DeleteRoom(c echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]string{"message": "Room deleted"})
 }
 
+// maxJoinAttempts is how many times joining a room, or marking the actions
+// of a previous room as carried over, is attempted before giving up on
+// version conflicts
+const maxJoinAttempts = 3
+
+// joinRoom adds the user to the room unless they are already a participant or
+// pending approval; they are approved straight away if the room auto-approves
+// or they are a member of the room's team. Team members still pending from
+// before they joined the team are approved as well. The returned room is
+// reloaded if someone else changed it in the meantime.
+func (h *Handler) joinRoom(room *models.Room, user models.User) (*models.Room, error) {
+	inTeam := h.inRoomTeam(room, user.ID)
+	for attempt := 1; ; attempt++ {
+		if _, exists := room.GetParticipant(user.ID); exists {
+			return room, nil
+		}
+
+		var participant *models.Participant
+		eventType, payload := models.EventParticipantJoined, map[string]any{"user_id": user.ID}
+		if _, pendingExists := room.GetPendingParticipant(user.ID); pendingExists {
+			if !inTeam {
+				return room, nil
+			}
+			room.ApproveParticipant(user.ID)
+			participant, _ = room.GetParticipant(user.ID)
+			eventType = models.EventParticipantApproved
+			payload["team_id"] = room.TeamID
+		} else {
+			status := models.StatusPending
+			if room.AutoApprove || inTeam {
+				status = models.StatusApproved
+			}
+			room.AddParticipant(user, models.RoleParticipant, status)
+			var ok bool
+			if participant, ok = room.GetParticipant(user.ID); !ok {
+				participant, _ = room.GetPendingParticipant(user.ID)
+			}
+			payload["status"] = status
+		}
+
+		err := h.store.UpsertParticipant(room, participant)
+		if err == nil {
+			h.hub.RecordEvent(room.ID, eventType, user.ID, payload)
+		}
+		if !errors.Is(err, models.ErrVersionConflict) || attempt == maxJoinAttempts {
+			return room, err
+		}
+
+		fresh, ok := h.store.Get(room.ID)
+		if !ok {
+			return nil, models.ErrRoomNotFound
+		}
+		room = fresh
+	}
+}
+
+// markCarriedOver marks the open actions of the previous room that were not
+// carried over yet as carried over to the room, so that they are only
+// carried over once, and returns the previous room with the marks. It
+// retries on version conflicts like joinRoom.
+func (h *Handler) markCarriedOver(previous *models.Room, roomID string) (*models.Room, error) {
+	for attempt := 1; ; attempt++ {
+		ids := previous.MarkCarriedOver(roomID)
+		if len(ids) == 0 {
+			return previous, nil
+		}
+		err := h.store.MarkCarriedOver(previous, ids, roomID)
+		if !errors.Is(err, models.ErrVersionConflict) || attempt == maxJoinAttempts {
+			return previous, err
+		}
+
+		fresh, ok := h.store.Get(previous.ID)
+		if !ok {
+			return nil, models.ErrRoomNotFound
+		}
+		previous = fresh
+	}
+}
+
 // WebSocket handles WebSocket connections
 func (h *Handler) WebSocket(c echo.Context) error {
 	roomID := c.Param("id")
@@ -245,6 +599,12 @@ func (h *Handler) WebSocket(c echo.Context) error {
 		return c.String(http.StatusNotFound, "Room not found")
 	}
 
+	// Add user as approved if auto-approve is enabled, otherwise pending
+	room, err := h.joinRoom(room, user)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to update room")
+	}
+
 	conn, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
 	if err != nil {
 		return err
@@ -262,24 +622,6 @@ func (h *Handler) WebSocket(c echo.Context) error {
 		// User is pending - send limited room state and notify about pending status
 		h.hub.SendPendingRoomState(client, room)
 		h.hub.NotifyParticipantPending(room, pendingParticipant)
-	} else {
-		// User is not yet added - add as approved if auto-approve is enabled, otherwise pending
-		if room.AutoApprove {
-			room.AddParticipant(user, models.RoleParticipant, models.StatusApproved)
-			if err := h.store.Update(room); err != nil {
-				return c.String(http.StatusInternalServerError, "Failed to update room")
-			}
-			h.hub.NotifyUserJoined(room, user)
-			h.hub.SendRoomState(client, room)
-		} else {
-			room.AddParticipant(user, models.RoleParticipant, models.StatusPending)
-			if err := h.store.Update(room); err != nil {
-				return c.String(http.StatusInternalServerError, "Failed to update room")
-			}
-			pendingParticipant, _ := room.GetPendingParticipant(user.ID)
-			h.hub.SendPendingRoomState(client, room)
-			h.hub.NotifyParticipantPending(room, pendingParticipant)
-		}
 	}
 
 	// Start goroutines for reading and writing
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/Armatorix/GoRetro/internal/models"
+	"github.com/Armatorix/GoRetro/internal/websocket"
+)
+
+// newTestHandler returns a handler backed by a memory store
+func newTestHandler() (*Handler, models.Store) {
+	store := models.NewMemoryStore()
+	return NewHandler(store, websocket.NewHub(store), "", ""), store
+}
+
+// serve calls the handler with a JSON request made by the user. params are
+// the route's path parameters as name and value pairs; body is encoded as
+// JSON unless it is nil.
+func serve(t *testing.T, handler echo.HandlerFunc, method, target, userID string, body any, params ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	var reader io.Reader = http.NoBody
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("Failed to encode request body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req := httptest.NewRequest(method, target, reader)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Forwarded-User", userID)
+	req.Header.Set("X-Forwarded-Email", userID+"@example.com")
+	rec := httptest.NewRecorder()
+
+	c := echo.New().NewContext(req, rec)
+	var names, values []string
+	for i := 0; i+1 < len(params); i += 2 {
+		names = append(names, params[i])
+		values = append(values, params[i+1])
+	}
+	c.SetParamNames(names...)
+	c.SetParamValues(values...)
+	if err := handler(c); err != nil {
+		t.Fatalf("Handler returned error: %v", err)
+	}
+	return rec
+}
+
+// decode unmarshals the recorded JSON response into v
+func decode(t *testing.T, rec *httptest.ResponseRecorder, v any) {
+	t.Helper()
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("Failed to decode response %q: %v", rec.Body.String(), err)
+	}
+}
+
+// createTestTeam stores a team administered by adminID with the other users as members
+func createTestTeam(t *testing.T, store models.Store, adminID string, memberIDs ...string) *models.Team {
+	t.Helper()
+	team := models.NewTeam("team-1", "Team", models.User{ID: adminID, Name: adminID})
+	for _, id := range memberIDs {
+		team.Members[id] = &models.TeamMember{User: models.User{ID: id, Name: id}, Role: models.TeamRoleMember}
+	}
+	if err := store.CreateTeam(team); err != nil {
+		t.Fatalf("Failed to create team: %v", err)
+	}
+	return team
+}
+
+func TestCreateRoom_Team(t *testing.T) {
+	h, store := newTestHandler()
+	createTestTeam(t, store, "admin", "member")
+
+	tests := []struct {
+		name   string
+		userID string
+		teamID string
+		want   int
+	}{
+		{name: "admin", userID: "admin", teamID: "team-1", want: http.StatusCreated},
+		{name: "member", userID: "member", teamID: "team-1", want: http.StatusCreated},
+		{name: "non-member", userID: "outsider", teamID: "team-1", want: http.StatusForbidden},
+		{name: "unknown team", userID: "admin", teamID: "missing", want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, h.CreateRoom, http.MethodPost, "/rooms", tt.userID, CreateRoomRequest{Name: "Retro", TeamID: tt.teamID})
+			if rec.Code != tt.want {
+				t.Fatalf("Expected status %d, got %d: %s", tt.want, rec.Code, rec.Body.String())
+			}
+			if tt.want != http.StatusCreated {
+				return
+			}
+			var resp RoomResponse
+			decode(t, rec, &resp)
+			room, ok := store.Get(resp.ID)
+			if !ok {
+				t.Fatalf("Room %s was not stored", resp.ID)
+			}
+			if room.TeamID != tt.teamID {
+				t.Errorf("Expected team %q, got %q", tt.teamID, room.TeamID)
+			}
+		})
+	}
+}
+
+func TestJoinRoom_TeamMembersApproved(t *testing.T) {
+	h, store := newTestHandler()
+	createTestTeam(t, store, "admin", "member")
+
+	room := models.NewRoom("room-1", "Retro", "admin", 3)
+	room.TeamID = "team-1"
+	room.AddParticipant(models.User{ID: "admin", Name: "admin"}, models.RoleOwner, models.StatusApproved)
+	// A member who asked to join before being added to the team is approved on their next visit
+	room.AddParticipant(models.User{ID: "late", Name: "late"}, models.RoleParticipant, models.StatusPending)
+	if err := store.Create(room); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+	if err := store.UpsertTeamMember("team-1", &models.TeamMember{User: models.User{ID: "late", Name: "late"}, Role: models.TeamRoleMember}); err != nil {
+		t.Fatalf("Failed to add team member: %v", err)
+	}
+
+	for _, userID := range []string{"member", "late", "outsider"} {
+		stored, _ := store.Get("room-1")
+		if _, err := h.joinRoom(stored, models.User{ID: userID, Name: userID}); err != nil {
+			t.Fatalf("Failed to join as %s: %v", userID, err)
+		}
+	}
+
+	stored, _ := store.Get("room-1")
+	for _, userID := range []string{"member", "late"} {
+		if _, ok := stored.GetParticipant(userID); !ok {
+			t.Errorf("Expected team member %s to be approved", userID)
+		}
+	}
+	if _, ok := stored.GetParticipant("outsider"); ok {
+		t.Error("Expected non-member to wait for approval")
+	}
+	if _, ok := stored.GetPendingParticipant("outsider"); !ok {
+		t.Error("Expected non-member to be pending")
+	}
+}
+
+func TestCreateRoom_CarriesActionsOverOnce(t *testing.T) {
+	h, store := newTestHandler()
+
+	previous := models.NewRoom("room-1", "Sprint 1", "alice", 3)
+	previous.AddParticipant(models.User{ID: "alice", Name: "alice"}, models.RoleOwner, models.StatusApproved)
+	previous.AddActionTicket(&models.ActionTicket{ID: "action-1", Content: "Fix CI", Status: models.ActionOpen, CreatedAt: time.Now()})
+	if err := store.Create(previous); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+
+	var rooms []RoomResponse
+	for range 2 {
+		rec := serve(t, h.CreateRoom, http.MethodPost, "/rooms", "alice", CreateRoomRequest{Name: "Sprint 2", PreviousRoomID: "room-1"})
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+		}
+		var resp RoomResponse
+		decode(t, rec, &resp)
+		rooms = append(rooms, resp)
+	}
+
+	first, _ := store.Get(rooms[0].ID)
+	if len(first.ActionTickets) != 1 {
+		t.Fatalf("Expected the open action to be carried over, got %v", first.ActionTickets)
+	}
+	second, _ := store.Get(rooms[1].ID)
+	if len(second.ActionTickets) != 0 || second.PreviousRoomID != "room-1" {
+		t.Errorf("Expected the second room to be linked without carrying the action again, got %v", second.ActionTickets)
+	}
+	source, _ := store.Get("room-1")
+	if action, _ := source.GetActionTicket("action-1"); action.CarriedTo != first.ID {
+		t.Errorf("Expected the source action to be carried to %s, got %q", first.ID, action.CarriedTo)
+	}
+}
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/Armatorix/GoRetro/internal/models"
+)
+
+// TeamRequest is the request body for creating a team
+type TeamRequest struct {
+	Name string `json:"name" form:"name"`
+}
+
+// TeamMemberRequest is the request body for adding a team member or changing
+// their role. The user is identified by the URL; name and email are what the
+// team sees until the user joins one of its rooms.
+type TeamMemberRequest struct {
+	Name  string          `json:"name"`
+	Email string          `json:"email"`
+	Role  models.TeamRole `json:"role"`
+}
+
+// ListTeams returns the teams the user is a member of
+func (h *Handler) ListTeams(c echo.Context) error {
+	user := getUserFromRequest(c)
+	teams, err := h.store.ListTeamsByMember(user.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to load teams"})
+	}
+	return c.JSON(http.StatusOK, teams)
+}
+
+// CreateTeam creates a team with the current user as its admin
+func (h *Handler) CreateTeam(c echo.Context) error {
+	user := getUserFromRequest(c)
+
+	var req TeamRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Name is required"})
+	}
+
+	team := models.NewTeam(uuid.New().String(), name, user)
+	if err := h.store.CreateTeam(team); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create team"})
+	}
+	return c.JSON(http.StatusCreated, team)
+}
+
+// GetTeam returns a team and its members to the team's members
+func (h *Handler) GetTeam(c echo.Context) error {
+	user := getUserFromRequest(c)
+
+	team, status, msg := h.teamFor(c.Param("id"), user.ID, false)
+	if team == nil {
+		return c.JSON(status, map[string]string{"error": msg})
+	}
+	return c.JSON(http.StatusOK, team)
+}
+
+// DeleteTeam removes a team. Only its admins can delete it; the team's rooms
+// are kept.
+func (h *Handler) DeleteTeam(c echo.Context) error {
+	user := getUserFromRequest(c)
+
+	team, status, msg := h.teamFor(c.Param("id"), user.ID, true)
+	if team == nil {
+		return c.JSON(status, map[string]string{"error": msg})
+	}
+
+	if err := h.store.DeleteTeam(team.ID); err != nil {
+		if errors.Is(err, models.ErrTeamNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Team not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete team"})
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "Team deleted"})
+}
+
+// PutTeamMember adds a user to the team or changes their role. Only the
+// team's admins can manage its members.
+func (h *Handler) PutTeamMember(c echo.Context) error {
+	user := getUserFromRequest(c)
+
+	team, status, msg := h.teamFor(c.Param("id"), user.ID, true)
+	if team == nil {
+		return c.JSON(status, map[string]string{"error": msg})
+	}
+
+	var req TeamMemberRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+	if req.Role == "" {
+		req.Role = models.TeamRoleMember
+	}
+	if err := models.ValidateTeamRole(req.Role); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid role: " + err.Error()})
+	}
+
+	userID := c.Param("user_id")
+	if err := team.CheckMemberChange(userID, req.Role); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid role: " + err.Error()})
+	}
+
+	member, exists := team.Members[userID]
+	if !exists {
+		member = &models.TeamMember{User: models.User{ID: userID, Name: userID}, JoinedAt: time.Now()}
+	}
+	if req.Name != "" {
+		member.User.Name = req.Name
+	}
+	if req.Email != "" {
+		member.User.Email = req.Email
+	}
+	member.Role = req.Role
+
+	if err := h.store.UpsertTeamMember(team.ID, member); err != nil {
+		if errors.Is(err, models.ErrTeamNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Team not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update team member"})
+	}
+	if !exists {
+		return c.JSON(http.StatusCreated, member)
+	}
+	return c.JSON(http.StatusOK, member)
+}
+
+// RemoveTeamMember removes a user from the team. Admins can remove anyone;
+// members can only leave the team themselves.
+func (h *Handler) RemoveTeamMember(c echo.Context) error {
+	user := getUserFromRequest(c)
+	userID := c.Param("user_id")
+
+	team, status, msg := h.teamFor(c.Param("id"), user.ID, userID != user.ID)
+	if team == nil {
+		return c.JSON(status, map[string]string{"error": msg})
+	}
+	if !team.IsMember(userID) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Team member not found"})
+	}
+	if err := team.CheckMemberChange(userID, ""); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Can't remove the team member: " + err.Error()})
+	}
+
+	if err := h.store.RemoveTeamMember(team.ID, userID); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to remove team member"})
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "Team member removed"})
+}
+
+// ListTeamRooms returns the rooms belonging to a team to the team's members
+func (h *Handler) ListTeamRooms(c echo.Context) error {
+	user := getUserFromRequest(c)
+
+	team, status, msg := h.teamFor(c.Param("id"), user.ID, false)
+	if team == nil {
+		return c.JSON(status, map[string]string{"error": msg})
+	}
+
+	rooms := h.store.ListByTeam(team.ID)
+	response := make([]RoomResponse, 0, len(rooms))
+	for _, room := range rooms {
+		response = append(response, newRoomResponse(room))
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// teamFor returns the team with the given ID if the user is a member, or an
+// admin when admin is set, or the status and message to respond with otherwise
+func (h *Handler) teamFor(id, userID string, admin bool) (*models.Team, int, string) {
+	team, ok := h.store.GetTeam(id)
+	if !ok {
+		return nil, http.StatusNotFound, "Team not found"
+	}
+	if admin && !team.IsAdmin(userID) {
+		return nil, http.StatusForbidden, "Only team admins can manage the team"
+	}
+	if !team.IsMember(userID) {
+		return nil, http.StatusForbidden, "Only team members can view the team"
+	}
+	return team, 0, ""
+}
+
+// inRoomTeam reports whether the user is a member of the team the room
+// belongs to
+func (h *Handler) inRoomTeam(room *models.Room, userID string) bool {
+	room.RLock()
+	teamID := room.TeamID
+	room.RUnlock()
+	if teamID == "" {
+		return false
+	}
+	team, ok := h.store.GetTeam(teamID)
+	return ok && team.IsMember(userID)
+}
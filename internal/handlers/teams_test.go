@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Armatorix/GoRetro/internal/models"
+)
+
+func TestCreateTeam(t *testing.T) {
+	h, store := newTestHandler()
+
+	rec := serve(t, h.CreateTeam, http.MethodPost, "/teams", "alice", TeamRequest{Name: "  "})
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d for a blank name, got %d", http.StatusBadRequest, rec.Code)
+	}
+
+	rec = serve(t, h.CreateTeam, http.MethodPost, "/teams", "alice", TeamRequest{Name: "Platform"})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+	var created models.Team
+	decode(t, rec, &created)
+
+	team, ok := store.GetTeam(created.ID)
+	if !ok {
+		t.Fatalf("Team %s was not stored", created.ID)
+	}
+	if team.Name != "Platform" {
+		t.Errorf("Expected name %q, got %q", "Platform", team.Name)
+	}
+	if !team.IsAdmin("alice") {
+		t.Error("Expected the creator to be the team's admin")
+	}
+}
+
+func TestGetTeam(t *testing.T) {
+	h, store := newTestHandler()
+	createTestTeam(t, store, "admin", "member")
+
+	tests := []struct {
+		name   string
+		userID string
+		teamID string
+		want   int
+	}{
+		{name: "admin", userID: "admin", teamID: "team-1", want: http.StatusOK},
+		{name: "member", userID: "member", teamID: "team-1", want: http.StatusOK},
+		{name: "non-member", userID: "outsider", teamID: "team-1", want: http.StatusForbidden},
+		{name: "unknown team", userID: "admin", teamID: "missing", want: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, h.GetTeam, http.MethodGet, "/teams/"+tt.teamID, tt.userID, nil, "id", tt.teamID)
+			if rec.Code != tt.want {
+				t.Errorf("Expected status %d, got %d: %s", tt.want, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestDeleteTeam(t *testing.T) {
+	tests := []struct {
+		name   string
+		userID string
+		teamID string
+		want   int
+	}{
+		{name: "member", userID: "member", teamID: "team-1", want: http.StatusForbidden},
+		{name: "non-member", userID: "outsider", teamID: "team-1", want: http.StatusForbidden},
+		{name: "unknown team", userID: "admin", teamID: "missing", want: http.StatusNotFound},
+		{name: "admin", userID: "admin", teamID: "team-1", want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, store := newTestHandler()
+			createTestTeam(t, store, "admin", "member")
+
+			rec := serve(t, h.DeleteTeam, http.MethodDelete, "/teams/"+tt.teamID, tt.userID, nil, "id", tt.teamID)
+			if rec.Code != tt.want {
+				t.Fatalf("Expected status %d, got %d: %s", tt.want, rec.Code, rec.Body.String())
+			}
+			_, exists := store.GetTeam("team-1")
+			if deleted := tt.want == http.StatusOK; exists == deleted {
+				t.Errorf("Expected team deleted to be %v", deleted)
+			}
+		})
+	}
+}
+
+func TestPutTeamMember(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   string
+		teamID   string
+		memberID string
+		req      TeamMemberRequest
+		want     int
+		wantRole models.TeamRole
+	}{
+		{name: "admin adds member", userID: "admin", teamID: "team-1", memberID: "new", req: TeamMemberRequest{Name: "New"}, want: http.StatusCreated, wantRole: models.TeamRoleMember},
+		{name: "admin promotes member", userID: "admin", teamID: "team-1", memberID: "member", req: TeamMemberRequest{Role: models.TeamRoleAdmin}, want: http.StatusOK, wantRole: models.TeamRoleAdmin},
+		{name: "member can't add", userID: "member", teamID: "team-1", memberID: "new", want: http.StatusForbidden},
+		{name: "member can't promote themselves", userID: "member", teamID: "team-1", memberID: "member", req: TeamMemberRequest{Role: models.TeamRoleAdmin}, want: http.StatusForbidden, wantRole: models.TeamRoleMember},
+		{name: "non-member can't add", userID: "outsider", teamID: "team-1", memberID: "outsider", want: http.StatusForbidden},
+		{name: "unknown team", userID: "admin", teamID: "missing", memberID: "new", want: http.StatusNotFound},
+		{name: "invalid role", userID: "admin", teamID: "team-1", memberID: "new", req: TeamMemberRequest{Role: "owner"}, want: http.StatusBadRequest},
+		{name: "last admin demoted", userID: "admin", teamID: "team-1", memberID: "admin", req: TeamMemberRequest{Role: models.TeamRoleMember}, want: http.StatusBadRequest, wantRole: models.TeamRoleAdmin},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, store := newTestHandler()
+			createTestTeam(t, store, "admin", "member")
+
+			target := "/teams/" + tt.teamID + "/members/" + tt.memberID
+			rec := serve(t, h.PutTeamMember, http.MethodPut, target, tt.userID, tt.req, "id", tt.teamID, "user_id", tt.memberID)
+			if rec.Code != tt.want {
+				t.Fatalf("Expected status %d, got %d: %s", tt.want, rec.Code, rec.Body.String())
+			}
+
+			team, _ := store.GetTeam("team-1")
+			member, ok := team.Members[tt.memberID]
+			if tt.wantRole == "" {
+				if ok {
+					t.Errorf("Expected %s not to be a team member", tt.memberID)
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("Expected %s to be a team member", tt.memberID)
+			}
+			if member.Role != tt.wantRole {
+				t.Errorf("Expected role %q, got %q", tt.wantRole, member.Role)
+			}
+		})
+	}
+}
+
+func TestRemoveTeamMember(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		teamID     string
+		memberID   string
+		want       int
+		wantMember bool
+	}{
+		{name: "admin removes member", userID: "admin", teamID: "team-1", memberID: "member", want: http.StatusOK},
+		{name: "member leaves", userID: "member", teamID: "team-1", memberID: "member", want: http.StatusOK},
+		{name: "member can't remove others", userID: "member", teamID: "team-1", memberID: "other", want: http.StatusForbidden, wantMember: true},
+		{name: "non-member can't remove", userID: "outsider", teamID: "team-1", memberID: "member", want: http.StatusForbidden, wantMember: true},
+		{name: "unknown team", userID: "admin", teamID: "missing", memberID: "member", want: http.StatusNotFound, wantMember: true},
+		{name: "unknown member", userID: "admin", teamID: "team-1", memberID: "outsider", want: http.StatusNotFound},
+		{name: "last admin", userID: "admin", teamID: "team-1", memberID: "admin", want: http.StatusBadRequest, wantMember: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, store := newTestHandler()
+			createTestTeam(t, store, "admin", "member", "other")
+
+			target := "/teams/" + tt.teamID + "/members/" + tt.memberID
+			rec := serve(t, h.RemoveTeamMember, http.MethodDelete, target, tt.userID, nil, "id", tt.teamID, "user_id", tt.memberID)
+			if rec.Code != tt.want {
+				t.Fatalf("Expected status %d, got %d: %s", tt.want, rec.Code, rec.Body.String())
+			}
+
+			team, _ := store.GetTeam("team-1")
+			if team.IsMember(tt.memberID) != tt.wantMember {
+				t.Errorf("Expected %s member to be %v", tt.memberID, tt.wantMember)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/Armatorix/GoRetro/internal/models"
+)
+
+// TemplateRequest is the request body for creating or updating a custom template
+type TemplateRequest struct {
+	Name              string          `json:"name"`
+	Description       string          `json:"description"`
+	Columns           []models.Column `json:"columns"`
+	VotesPerUser      int             `json:"votes_per_user"`
+	MaxVotesPerTicket int             `json:"max_votes_per_ticket"`
+	Phases            []models.Phase  `json:"phases"`
+	// HealthDimensions make rooms created from the template health checks
+	HealthDimensions []models.HealthDimension `json:"health_dimensions"`
+	// TeamID shares the template with the members of a team
+	TeamID string `json:"team_id"`
+}
+
+// template turns the request into a template, giving new columns an ID
+func (req *TemplateRequest) template(id string) *models.Template {
+	columns := make([]models.Column, 0, len(req.Columns))
+	for _, column := range req.Columns {
+		if column.ID == "" {
+			column.ID = uuid.New().String()
+		}
+		columns = append(columns, column)
+	}
+	return &models.Template{
+		ID:                id,
+		Name:              req.Name,
+		Description:       req.Description,
+		Columns:           columns,
+		VotesPerUser:      req.VotesPerUser,
+		MaxVotesPerTicket: req.MaxVotesPerTicket,
+		Phases:            req.Phases,
+		HealthDimensions:  req.HealthDimensions,
+		TeamID:            req.TeamID,
+	}
+}
+
+// listTemplates returns the built-in templates followed by the custom ones
+// the user can see: their own and those shared with their teams
+func (h *Handler) listTemplates(userID string) ([]*models.Template, error) {
+	teams, err := h.store.ListTeamsByMember(userID)
+	if err != nil {
+		return nil, err
+	}
+	teamIDs := make([]string, 0, len(teams))
+	for _, team := range teams {
+		teamIDs = append(teamIDs, team.ID)
+	}
+	custom, err := h.store.ListTemplates(userID, teamIDs)
+	if err != nil {
+		return nil, err
+	}
+	return append(models.BuiltinTemplates(), custom...), nil
+}
+
+// findTemplate looks a template up among the built-in templates and the
+// custom ones the user can see
+func (h *Handler) findTemplate(id, userID string) (*models.Template, bool) {
+	if template, ok := models.BuiltinTemplate(id); ok {
+		return template, true
+	}
+	template, ok := h.store.GetTemplate(id)
+	if !ok || !h.canSeeTemplate(template, userID) {
+		return nil, false
+	}
+	return template, true
+}
+
+// canSeeTemplate reports whether the user created the custom template or is
+// a member of the team it is shared with
+func (h *Handler) canSeeTemplate(template *models.Template, userID string) bool {
+	if template.CreatedBy == userID {
+		return true
+	}
+	if template.TeamID == "" {
+		return false
+	}
+	team, ok := h.store.GetTeam(template.TeamID)
+	return ok && team.IsMember(userID)
+}
+
+// ListTemplates returns all templates the user can create rooms from
+func (h *Handler) ListTemplates(c echo.Context) error {
+	user := getUserFromRequest(c)
+
+	templates, err := h.listTemplates(user.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to load templates"})
+	}
+	return c.JSON(http.StatusOK, templates)
+}
+
+// GetTemplate returns a single template
+func (h *Handler) GetTemplate(c echo.Context) error {
+	user := getUserFromRequest(c)
+
+	template, ok := h.findTemplate(c.Param("id"), user.ID)
+	if !ok {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Template not found"})
+	}
+	return c.JSON(http.StatusOK, template)
+}
+
+// CreateTemplate stores a new custom template owned by the current user
+func (h *Handler) CreateTemplate(c echo.Context) error {
+	user := getUserFromRequest(c)
+
+	var req TemplateRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+	if status, msg := h.checkTemplateTeam(req.TeamID, user.ID); status != 0 {
+		return c.JSON(status, map[string]string{"error": msg})
+	}
+
+	template := req.template(uuid.New().String())
+	if err := template.Validate(); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid template: " + err.Error()})
+	}
+	template.CreatedBy = user.ID
+	template.CreatedAt = time.Now()
+
+	if err := h.store.CreateTemplate(template); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create template"})
+	}
+	return c.JSON(http.StatusCreated, template)
+}
+
+// UpdateTemplate replaces a custom template. Only its creator and the admins
+// of the team it is shared with can change it.
+func (h *Handler) UpdateTemplate(c echo.Context) error {
+	user := getUserFromRequest(c)
+
+	existing, status, msg := h.editableTemplate(c.Param("id"), user.ID)
+	if existing == nil {
+		return c.JSON(status, map[string]string{"error": msg})
+	}
+
+	var req TemplateRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+	if req.TeamID != existing.TeamID {
+		if status, msg := h.checkTemplateTeam(req.TeamID, user.ID); status != 0 {
+			return c.JSON(status, map[string]string{"error": msg})
+		}
+	}
+
+	template := req.template(existing.ID)
+	if err := template.Validate(); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid template: " + err.Error()})
+	}
+	template.CreatedBy = existing.CreatedBy
+	template.CreatedAt = existing.CreatedAt
+
+	if err := h.store.UpdateTemplate(template); err != nil {
+		if errors.Is(err, models.ErrTemplateNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Template not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update template"})
+	}
+	return c.JSON(http.StatusOK, template)
+}
+
+// DeleteTemplate removes a custom template. Only its creator and the admins
+// of the team it is shared with can delete it; rooms created from it keep
+// their columns.
+func (h *Handler) DeleteTemplate(c echo.Context) error {
+	user := getUserFromRequest(c)
+
+	existing, status, msg := h.editableTemplate(c.Param("id"), user.ID)
+	if existing == nil {
+		return c.JSON(status, map[string]string{"error": msg})
+	}
+
+	if err := h.store.DeleteTemplate(existing.ID); err != nil {
+		if errors.Is(err, models.ErrTemplateNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Template not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete template"})
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "Template deleted"})
+}
+
+// editableTemplate returns the custom template with the given ID if the user
+// may change it, or the status and message to respond with otherwise
+func (h *Handler) editableTemplate(id, userID string) (*models.Template, int, string) {
+	if _, ok := models.BuiltinTemplate(id); ok {
+		return nil, http.StatusForbidden, "Built-in templates can't be changed"
+	}
+	template, ok := h.store.GetTemplate(id)
+	if !ok || !h.canSeeTemplate(template, userID) {
+		return nil, http.StatusNotFound, "Template not found"
+	}
+	if template.CreatedBy == userID {
+		return template, 0, ""
+	}
+	if team, ok := h.store.GetTeam(template.TeamID); ok && team.IsAdmin(userID) {
+		return template, 0, ""
+	}
+	return nil, http.StatusForbidden, "Only the template's creator or its team's admins can change it"
+}
+
+// checkTemplateTeam checks that the user can share a template with the team,
+// returning the status and message to respond with otherwise. Templates
+// without a team are always fine.
+func (h *Handler) checkTemplateTeam(teamID, userID string) (int, string) {
+	if teamID == "" {
+		return 0, ""
+	}
+	team, ok := h.store.GetTeam(teamID)
+	if !ok {
+		return http.StatusBadRequest, "Team not found"
+	}
+	if !team.IsMember(userID) {
+		return http.StatusForbidden, "Only team members can share templates with the team"
+	}
+	return 0, ""
+}
@@ -0,0 +1,204 @@
+package models
+
+import (
+	"errors"
+	"slices"
+	"sort"
+	"time"
+)
+
+// ActionStatus is where an action item is in its lifecycle
+type ActionStatus string
+
+const (
+	ActionOpen       ActionStatus = "open"
+	ActionInProgress ActionStatus = "in_progress"
+	ActionDone       ActionStatus = "done"
+	ActionDropped    ActionStatus = "dropped"
+)
+
+// DueDateLayout is the format of action due dates in requests
+const DueDateLayout = "2006-01-02"
+
+// ErrInvalidActionStatus is returned for statuses other than the ActionStatus constants
+var ErrInvalidActionStatus = errors.New("status must be open, in_progress, done or dropped")
+
+// ValidateActionStatus checks that a status is one of the known statuses
+func ValidateActionStatus(status ActionStatus) error {
+	switch status {
+	case ActionOpen, ActionInProgress, ActionDone, ActionDropped:
+		return nil
+	}
+	return ErrInvalidActionStatus
+}
+
+// ActionStatusChange records an action item moving from one status to another
+type ActionStatusChange struct {
+	From      ActionStatus `json:"from"`
+	To        ActionStatus `json:"to"`
+	ChangedBy string       `json:"changed_by"`
+	ChangedAt time.Time    `json:"changed_at"`
+}
+
+// Closed reports whether the action is done or dropped
+func (a *ActionTicket) Closed() bool {
+	return a.Status == ActionDone || a.Status == ActionDropped
+}
+
+// SetStatus moves the action to a status and records the change in its
+// history. Completing the action sets CompletedAt; reopening clears it. It
+// returns false if the action already has the status.
+func (a *ActionTicket) SetStatus(status ActionStatus, userID string, now time.Time) bool {
+	if a.Status == status {
+		return false
+	}
+	a.StatusHistory = append(a.StatusHistory, ActionStatusChange{
+		From:      a.Status,
+		To:        status,
+		ChangedBy: userID,
+		ChangedAt: now,
+	})
+	a.Status = status
+	if status == ActionDone {
+		a.CompletedAt = &now
+	} else {
+		a.CompletedAt = nil
+	}
+	return true
+}
+
+// IsAssigned reports whether the user owns the action or is one of its assignees
+func (a *ActionTicket) IsAssigned(userID string) bool {
+	if a.OwnerID == userID {
+		return true
+	}
+	for _, id := range a.AssigneeIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateActionTicket replaces an existing action item. It returns false if
+// the action doesn't exist.
+func (r *Room) UpdateActionTicket(action *ActionTicket) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.ActionTickets[action.ID]; !ok {
+		return false
+	}
+	r.ActionTickets[action.ID] = action
+	return true
+}
+
+// ReviewOutcome is the result of reviewing a carried over action
+type ReviewOutcome string
+
+const (
+	ReviewDone     ReviewOutcome = "done"
+	ReviewRelevant ReviewOutcome = "relevant"
+	ReviewDropped  ReviewOutcome = "dropped"
+)
+
+// ErrInvalidReviewOutcome is returned for outcomes other than the ReviewOutcome constants
+var ErrInvalidReviewOutcome = errors.New("outcome must be done, relevant or dropped")
+
+// ValidateReviewOutcome checks that an outcome is one of the known outcomes
+func ValidateReviewOutcome(outcome ReviewOutcome) error {
+	switch outcome {
+	case ReviewDone, ReviewRelevant, ReviewDropped:
+		return nil
+	}
+	return ErrInvalidReviewOutcome
+}
+
+// Review records the outcome of reviewing the action. Done and dropped
+// actions are closed; relevant ones stay open.
+func (a *ActionTicket) Review(outcome ReviewOutcome, userID string, now time.Time) {
+	a.ReviewOutcome = outcome
+	switch outcome {
+	case ReviewDone:
+		a.SetStatus(ActionDone, userID, now)
+	case ReviewDropped:
+		a.SetStatus(ActionDropped, userID, now)
+	}
+}
+
+// OpenActions returns copies of the room's actions that are neither done nor
+// dropped, oldest first
+func (r *Room) OpenActions() []*ActionTicket {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	actions := make([]*ActionTicket, 0, len(r.ActionTickets))
+	for _, action := range r.ActionTickets {
+		if !action.Closed() {
+			actions = append(actions, action.Clone())
+		}
+	}
+	sort.Slice(actions, func(i, j int) bool {
+		return actions[i].CreatedAt.Before(actions[j].CreatedAt)
+	})
+	return actions
+}
+
+// MarkCarriedOver marks the room's open actions that were not carried over
+// yet as carried over to the room with the given ID and returns their IDs
+func (r *Room) MarkCarriedOver(roomID string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var ids []string
+	for _, action := range r.ActionTickets {
+		if action.Closed() || action.CarriedTo != "" {
+			continue
+		}
+		marked := action.Clone()
+		marked.CarriedTo = roomID
+		r.ActionTickets[action.ID] = marked
+		ids = append(ids, action.ID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// CarryOverActions links a room that has not started yet to the previous
+// room and imports the previous room's open actions, each under a new ID from
+// newID, to be reviewed. Actions already carried over to another room are
+// left out. When there are actions to review, a REVIEW phase is added at the
+// start of the pipeline, after the icebreaker if there is one.
+func (r *Room) CarryOverActions(previous *Room, newID func() string) {
+	actions := previous.OpenActions()
+
+	r.mu.Lock()
+	r.PreviousRoomID = previous.ID
+	actions = slices.DeleteFunc(actions, func(action *ActionTicket) bool {
+		return action.CarriedTo != "" && action.CarriedTo != r.ID
+	})
+	for _, action := range actions {
+		carried := &ActionTicket{
+			ID:             newID(),
+			Content:        action.Content,
+			AssigneeIDs:    action.AssigneeIDs,
+			Status:         action.Status,
+			OwnerID:        action.OwnerID,
+			DueDate:        action.DueDate,
+			CreatedAt:      action.CreatedAt,
+			SourceRoomID:   previous.ID,
+			SourceActionID: action.ID,
+		}
+		r.ActionTickets[carried.ID] = carried
+	}
+	if len(actions) == 0 || r.hasPhase(PhaseReview) {
+		r.mu.Unlock()
+		return
+	}
+	at := 0
+	if r.Phases[0] == PhaseIcebreaker {
+		at = 1
+	}
+	phases := append(append(append([]Phase{}, r.Phases[:at]...), PhaseReview), r.Phases[at:]...)
+	r.mu.Unlock()
+
+	// The new pipeline is valid: REVIEW was not in it before
+	_ = r.ConfigurePhases(phases)
+}
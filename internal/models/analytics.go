@@ -0,0 +1,379 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// AnalyticsFilter selects the rooms analytics are computed over. Empty
+// fields don't filter; rooms created at From and up to, but not including,
+// To are included.
+type AnalyticsFilter struct {
+	OwnerID string
+	TeamID  string
+	From    time.Time
+	To      time.Time
+}
+
+// matches reports whether the room passes the filter. The caller must hold
+// the room's read lock.
+func (f AnalyticsFilter) matches(room *Room) bool {
+	return (f.OwnerID == "" || room.OwnerID == f.OwnerID) &&
+		(f.TeamID == "" || room.TeamID == f.TeamID) &&
+		(f.From.IsZero() || !room.CreatedAt.Before(f.From)) &&
+		(f.To.IsZero() || room.CreatedAt.Before(f.To))
+}
+
+// conditions returns the SQL conditions on the rooms table, aliased r, that
+// apply the filter along with their arguments
+func (f AnalyticsFilter) conditions() ([]string, []any) {
+	var conditions []string
+	var args []any
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if f.OwnerID != "" {
+		add("r.owner_id = $%d", f.OwnerID)
+	}
+	if f.TeamID != "" {
+		add("r.team_id = $%d", f.TeamID)
+	}
+	if !f.From.IsZero() {
+		add("r.created_at >= $%d", f.From)
+	}
+	if !f.To.IsZero() {
+		add("r.created_at < $%d", f.To)
+	}
+	return conditions, args
+}
+
+// RoomStats are the figures of a single retrospective. Contributors are the
+// approved participants who wrote a ticket or voted. Actions carried over
+// from an earlier room are only counted in that room.
+type RoomStats struct {
+	RoomID            string    `json:"room_id"`
+	Name              string    `json:"name"`
+	CreatedAt         time.Time `json:"created_at"`
+	Tickets           int       `json:"tickets"`
+	Participants      int       `json:"participants"`
+	Contributors      int       `json:"contributors"`
+	ParticipationRate float64   `json:"participation_rate"`
+	Votes             int       `json:"votes"`
+	Actions           int       `json:"actions"`
+	ActionsDone       int       `json:"actions_done"`
+	ActionsDropped    int       `json:"actions_dropped"`
+	// Mood and ROTI are the average scores of the room's closed polls, left
+	// out when nobody answered
+	Mood float64 `json:"mood,omitempty"`
+	ROTI float64 `json:"roti,omitempty"`
+}
+
+// AnalyticsTotals sums up the figures of all selected rooms. Rates are
+// between 0 and 1; the time to close only covers actions that were done.
+type AnalyticsTotals struct {
+	Rooms                int     `json:"rooms"`
+	Tickets              int     `json:"tickets"`
+	TicketsPerRoom       float64 `json:"tickets_per_room"`
+	ParticipationRate    float64 `json:"participation_rate"`
+	Votes                int     `json:"votes"`
+	Actions              int     `json:"actions"`
+	ActionsDone          int     `json:"actions_done"`
+	ActionCompletionRate float64 `json:"action_completion_rate"`
+	AverageHoursToClose  float64 `json:"average_hours_to_close"`
+	MedianHoursToClose   float64 `json:"median_hours_to_close"`
+}
+
+// VoteBucket counts the tickets that got a given number of votes
+type VoteBucket struct {
+	Votes   int `json:"votes"`
+	Tickets int `json:"tickets"`
+}
+
+// PhraseCount is a word or two-word phrase that came up in tickets of
+// several rooms
+type PhraseCount struct {
+	Phrase  string `json:"phrase"`
+	Rooms   int    `json:"rooms"`
+	Tickets int    `json:"tickets"`
+}
+
+// PollSummary sums up the answers to a kind of poll across rooms;
+// Histogram[i] counts the answers with score i+1
+type PollSummary struct {
+	Histogram [MaxPollScore]int `json:"histogram"`
+	Responses int               `json:"responses"`
+	Average   float64           `json:"average"`
+}
+
+// Analytics are trends across retrospectives. Rooms are listed oldest first;
+// the vote distribution only covers tickets that were not merged into another
+// and polls only count once closed.
+type Analytics struct {
+	Totals           AnalyticsTotals          `json:"totals"`
+	Rooms            []RoomStats              `json:"rooms"`
+	VoteDistribution []VoteBucket             `json:"vote_distribution"`
+	RecurringPhrases []PhraseCount            `json:"recurring_phrases"`
+	Polls            map[PollKind]PollSummary `json:"polls"`
+}
+
+// analyticsData is what a store collects to compute analytics from
+type analyticsData struct {
+	rooms []RoomStats
+	// ticketsByVotes maps a number of votes to how many tickets got it
+	ticketsByVotes map[int]int
+	hoursToClose   []float64
+	// contents holds the ticket contents of each room by room ID
+	contents map[string][]string
+	// polls holds the closed polls of each room by room ID
+	polls map[string][]*Poll
+}
+
+func newAnalyticsData() *analyticsData {
+	return &analyticsData{
+		rooms:          []RoomStats{},
+		ticketsByVotes: make(map[int]int),
+		hoursToClose:   []float64{},
+		contents:       make(map[string][]string),
+		polls:          make(map[string][]*Poll),
+	}
+}
+
+// addRoom collects the figures of a room loaded with its votes. The caller
+// must hold the room's read lock.
+func (d *analyticsData) addRoom(room *Room) {
+	stats := RoomStats{
+		RoomID:       room.ID,
+		Name:         room.Name,
+		CreatedAt:    room.CreatedAt,
+		Tickets:      len(room.Tickets),
+		Participants: len(room.Participants),
+	}
+
+	contributors := make(map[string]bool)
+	for _, ticket := range room.Tickets {
+		contributors[ticket.AuthorID] = true
+		for _, voterID := range ticket.VoterIDs {
+			contributors[voterID] = true
+		}
+		stats.Votes += ticket.Votes
+		if ticket.DeduplicationTicketID == nil {
+			d.ticketsByVotes[ticket.Votes]++
+		}
+		if !room.ticketsHidden() {
+			d.contents[room.ID] = append(d.contents[room.ID], ticket.Content)
+		}
+	}
+	for userID := range room.Participants {
+		if contributors[userID] {
+			stats.Contributors++
+		}
+	}
+
+	for _, action := range room.ActionTickets {
+		if action.SourceActionID != "" {
+			continue
+		}
+		stats.Actions++
+		switch action.Status {
+		case ActionDone:
+			stats.ActionsDone++
+			if action.CompletedAt != nil {
+				d.hoursToClose = append(d.hoursToClose, action.CompletedAt.Sub(action.CreatedAt).Hours())
+			}
+		case ActionDropped:
+			stats.ActionsDropped++
+		}
+	}
+
+	for _, poll := range room.Polls {
+		if poll.ClosedAt != nil {
+			d.polls[room.ID] = append(d.polls[room.ID], poll)
+		}
+	}
+	d.rooms = append(d.rooms, stats)
+}
+
+// build computes the analytics from the collected figures
+func (d *analyticsData) build() *Analytics {
+	analytics := &Analytics{
+		Rooms:            d.rooms,
+		VoteDistribution: make([]VoteBucket, 0, len(d.ticketsByVotes)),
+		RecurringPhrases: recurringPhrases(d.contents),
+		Polls:            make(map[PollKind]PollSummary, len(PollKinds)),
+	}
+
+	totalPolls := make(map[PollKind]*Poll, len(PollKinds))
+	for _, kind := range PollKinds {
+		totalPolls[kind] = &Poll{Kind: kind}
+	}
+
+	totals := &analytics.Totals
+	participants, contributors := 0, 0
+	for i := range analytics.Rooms {
+		stats := &analytics.Rooms[i]
+		stats.ParticipationRate = ratio(stats.Contributors, stats.Participants)
+		totals.Rooms++
+		totals.Tickets += stats.Tickets
+		totals.Votes += stats.Votes
+		totals.Actions += stats.Actions
+		totals.ActionsDone += stats.ActionsDone
+		participants += stats.Participants
+		contributors += stats.Contributors
+
+		for _, poll := range d.polls[stats.RoomID] {
+			switch poll.Kind {
+			case PollMood:
+				stats.Mood = poll.Average()
+			case PollROTI:
+				stats.ROTI = poll.Average()
+			}
+			if total, ok := totalPolls[poll.Kind]; ok {
+				for i, n := range poll.Histogram {
+					total.Histogram[i] += n
+				}
+			}
+		}
+	}
+	for kind, total := range totalPolls {
+		summary := PollSummary{Histogram: total.Histogram, Average: total.Average()}
+		for _, n := range total.Histogram {
+			summary.Responses += n
+		}
+		analytics.Polls[kind] = summary
+	}
+	totals.TicketsPerRoom = ratio(totals.Tickets, totals.Rooms)
+	totals.ParticipationRate = ratio(contributors, participants)
+	totals.ActionCompletionRate = ratio(totals.ActionsDone, totals.Actions)
+
+	if n := len(d.hoursToClose); n > 0 {
+		hours := append([]float64{}, d.hoursToClose...)
+		sort.Float64s(hours)
+		sum := 0.0
+		for _, h := range hours {
+			sum += h
+		}
+		totals.AverageHoursToClose = sum / float64(n)
+		totals.MedianHoursToClose = hours[n/2]
+		if n%2 == 0 {
+			totals.MedianHoursToClose = (hours[n/2-1] + hours[n/2]) / 2
+		}
+	}
+
+	for votes, tickets := range d.ticketsByVotes {
+		analytics.VoteDistribution = append(analytics.VoteDistribution, VoteBucket{Votes: votes, Tickets: tickets})
+	}
+	sort.Slice(analytics.VoteDistribution, func(i, j int) bool {
+		return analytics.VoteDistribution[i].Votes < analytics.VoteDistribution[j].Votes
+	})
+	return analytics
+}
+
+// ratio returns n / total, or 0 when total is 0
+func ratio(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
+
+// maxRecurringPhrases caps how many recurring phrases analytics list
+const maxRecurringPhrases = 20
+
+// minPhraseWordLength is how many letters a word needs to count towards phrases
+const minPhraseWordLength = 3
+
+// phraseStopWords are common English and Polish words that say nothing about
+// a ticket's topic
+var phraseStopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "but": true, "not": true, "you": true,
+	"all": true, "can": true, "was": true, "were": true, "has": true, "have": true, "had": true,
+	"this": true, "that": true, "these": true, "those": true, "with": true, "from": true,
+	"they": true, "them": true, "their": true, "there": true, "what": true, "when": true,
+	"which": true, "who": true, "will": true, "would": true, "should": true, "could": true,
+	"our": true, "out": true, "too": true, "more": true, "less": true, "very": true, "into": true,
+	"about": true, "been": true, "being": true, "than": true, "then": true, "also": true,
+	"just": true, "some": true, "any": true, "its": true, "it's": true, "did": true, "does": true,
+	"jest": true, "nie": true, "się": true, "jak": true, "ale": true, "był": true, "była": true,
+	"było": true, "żeby": true, "tak": true, "dla": true, "czy": true, "już": true, "oraz": true,
+	"tym": true, "też": true, "bardzo": true, "mamy": true, "tego": true, "przy": true,
+}
+
+// ticketPhrases returns the distinct words and two-word phrases of a ticket,
+// leaving out short words and stop words
+func ticketPhrases(content string) []string {
+	words := strings.FieldsFunc(strings.ToLower(content), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+
+	seen := make(map[string]bool)
+	phrases := make([]string, 0, len(words)*2)
+	add := func(phrase string) {
+		if !seen[phrase] {
+			seen[phrase] = true
+			phrases = append(phrases, phrase)
+		}
+	}
+	previous := ""
+	for _, word := range words {
+		word = strings.Trim(word, "'")
+		if utf8.RuneCountInString(word) < minPhraseWordLength || phraseStopWords[word] {
+			previous = ""
+			continue
+		}
+		add(word)
+		if previous != "" {
+			add(previous + " " + word)
+		}
+		previous = word
+	}
+	return phrases
+}
+
+// recurringPhrases returns the phrases found in tickets of at least two
+// rooms, most widespread first
+func recurringPhrases(contents map[string][]string) []PhraseCount {
+	counts := make(map[string]*PhraseCount)
+	for _, tickets := range contents {
+		inRoom := make(map[string]bool)
+		for _, content := range tickets {
+			for _, phrase := range ticketPhrases(content) {
+				count, ok := counts[phrase]
+				if !ok {
+					count = &PhraseCount{Phrase: phrase}
+					counts[phrase] = count
+				}
+				count.Tickets++
+				if !inRoom[phrase] {
+					inRoom[phrase] = true
+					count.Rooms++
+				}
+			}
+		}
+	}
+
+	phrases := make([]PhraseCount, 0)
+	for _, count := range counts {
+		if count.Rooms >= 2 {
+			phrases = append(phrases, *count)
+		}
+	}
+	sort.Slice(phrases, func(i, j int) bool {
+		a, b := phrases[i], phrases[j]
+		if a.Rooms != b.Rooms {
+			return a.Rooms > b.Rooms
+		}
+		if a.Tickets != b.Tickets {
+			return a.Tickets > b.Tickets
+		}
+		return a.Phrase < b.Phrase
+	})
+	if len(phrases) > maxRecurringPhrases {
+		phrases = phrases[:maxRecurringPhrases]
+	}
+	return phrases
+}
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+)
+
+// Column is one of the ordered board columns tickets are sorted into
+type Column struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Color  string `json:"color"`
+	Prompt string `json:"prompt,omitempty"`
+}
+
+// columnColor matches the #rrggbb colors columns are drawn with
+var columnColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// columnID matches the IDs columns can be given
+var columnID = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// DefaultColumns returns the columns new rooms start with
+func DefaultColumns() []Column {
+	return []Column{
+		{ID: "went-well", Title: "Went well", Color: "#22c55e"},
+		{ID: "to-improve", Title: "To improve", Color: "#ef4444"},
+		{ID: "ideas", Title: "Ideas", Color: "#3b82f6"},
+	}
+}
+
+// GetColumn returns a column by ID
+func (r *Room) GetColumn(columnID string) (Column, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, column := range r.Columns {
+		if column.ID == columnID {
+			return column, true
+		}
+	}
+	return Column{}, false
+}
+
+// ResolveColumn returns the column a new ticket goes into. An empty ID picks
+// the first column; rooms without columns only accept an empty ID.
+func (r *Room) ResolveColumn(columnID string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if columnID == "" {
+		if len(r.Columns) == 0 {
+			return "", true
+		}
+		return r.Columns[0].ID, true
+	}
+	for _, column := range r.Columns {
+		if column.ID == columnID {
+			return columnID, true
+		}
+	}
+	return "", false
+}
+
+// ValidateColumns checks that column IDs are unique and made of letters,
+// digits, dashes and underscores, and that every column has a title and a
+// #rrggbb color
+func ValidateColumns(columns []Column) error {
+	ids := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		if !columnID.MatchString(column.ID) {
+			return errors.New("column ids may only contain letters, digits, dashes and underscores")
+		}
+		if column.Title == "" {
+			return errors.New("every column needs a title")
+		}
+		if !columnColor.MatchString(column.Color) {
+			return errors.New("column colors must look like #rrggbb")
+		}
+		if ids[column.ID] {
+			return errors.New("column ids must be unique")
+		}
+		ids[column.ID] = true
+	}
+	return nil
+}
+
+// SetColumns replaces the room's columns after checking them with
+// ValidateColumns. Columns that still hold tickets can't be removed.
+func (r *Room) SetColumns(columns []Column) error {
+	if err := ValidateColumns(columns); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ids := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		ids[column.ID] = true
+	}
+	for _, ticket := range r.Tickets {
+		if ticket.ColumnID != "" && !ids[ticket.ColumnID] {
+			return errors.New("can't remove a column that still has tickets")
+		}
+	}
+
+	r.Columns = append([]Column{}, columns...)
+	return nil
+}
+
+// TicketIDsByColumn groups the room's ticket IDs by column, oldest first.
+// Tickets without a column are left out. The caller must hold the room's read lock.
+func (r *Room) TicketIDsByColumn() map[string][]string {
+	tickets := make([]*Ticket, 0, len(r.Tickets))
+	for _, ticket := range r.Tickets {
+		tickets = append(tickets, ticket)
+	}
+	sort.Slice(tickets, func(i, j int) bool {
+		return tickets[i].CreatedAt.Before(tickets[j].CreatedAt)
+	})
+
+	grouped := make(map[string][]string, len(r.Columns))
+	for _, column := range r.Columns {
+		grouped[column.ID] = []string{}
+	}
+	for _, ticket := range tickets {
+		if _, ok := grouped[ticket.ColumnID]; ok {
+			grouped[ticket.ColumnID] = append(grouped[ticket.ColumnID], ticket.ID)
+		}
+	}
+	return grouped
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxCommentLength caps how many characters a comment can have
+const MaxCommentLength = 2000
+
+// ErrInvalidComment is returned for comments that are empty or too long
+var ErrInvalidComment = errors.New("comment must be between 1 and 2000 characters")
+
+// Comment is a note left on a ticket, for example during discussion
+type Comment struct {
+	ID        string     `json:"id"`
+	TicketID  string     `json:"ticket_id"`
+	AuthorID  string     `json:"author_id"`
+	Content   string     `json:"content"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// Clone returns a copy of the comment
+func (c *Comment) Clone() *Comment {
+	clone := *c
+	if c.UpdatedAt != nil {
+		updatedAt := *c.UpdatedAt
+		clone.UpdatedAt = &updatedAt
+	}
+	return &clone
+}
+
+// NormalizeComment trims a comment's content and checks its length
+func NormalizeComment(content string) (string, error) {
+	content = strings.TrimSpace(content)
+	if content == "" || utf8.RuneCountInString(content) > MaxCommentLength {
+		return "", ErrInvalidComment
+	}
+	return content, nil
+}
+
+// AddComment appends a comment to the thread of the ticket it belongs to. It
+// returns false if the ticket doesn't exist.
+func (r *Room) AddComment(comment *Comment) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ticket, ok := r.Tickets[comment.TicketID]
+	if !ok {
+		return false
+	}
+	ticket.Comments = append(ticket.Comments, comment)
+	return true
+}
+
+// GetComment returns a copy of a comment on a ticket
+func (r *Room) GetComment(ticketID, commentID string) (*Comment, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	comment, _ := r.findComment(ticketID, commentID)
+	if comment == nil {
+		return nil, false
+	}
+	return comment.Clone(), true
+}
+
+// EditComment replaces a comment's content. It returns the edited comment, or
+// false if the comment doesn't exist.
+func (r *Room) EditComment(ticketID, commentID, content string, now time.Time) (*Comment, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	comment, _ := r.findComment(ticketID, commentID)
+	if comment == nil {
+		return nil, false
+	}
+	comment.Content = content
+	comment.UpdatedAt = &now
+	return comment.Clone(), true
+}
+
+// RemoveComment deletes a comment from a ticket's thread. It returns the
+// removed comment, or false if the comment doesn't exist.
+func (r *Room) RemoveComment(ticketID, commentID string) (*Comment, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	comment, i := r.findComment(ticketID, commentID)
+	if comment == nil {
+		return nil, false
+	}
+	ticket := r.Tickets[ticketID]
+	ticket.Comments = append(ticket.Comments[:i:i], ticket.Comments[i+1:]...)
+	return comment, true
+}
+
+// findComment returns a comment on a ticket and its position in the thread.
+// The caller must hold the room's read lock.
+func (r *Room) findComment(ticketID, commentID string) (*Comment, int) {
+	ticket, ok := r.Tickets[ticketID]
+	if !ok {
+		return nil, -1
+	}
+	for i, comment := range ticket.Comments {
+		if comment.ID == commentID {
+			return comment, i
+		}
+	}
+	return nil, -1
+}
+
+// CommentView returns the comment as the viewer is allowed to see it: in
+// anonymous rooms, like ticket authors, comment authors are only shown to
+// themselves. The caller must hold the room's read lock.
+func (r *Room) CommentView(comment *Comment, viewerID string) *Comment {
+	if !r.AnonymousTickets || comment.AuthorID == viewerID {
+		return comment
+	}
+	view := comment.Clone()
+	view.AuthorID = ""
+	return view
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EventType identifies the kind of change recorded in a room's event log
+type EventType string
+
+const (
+	EventParticipantJoined        EventType = "participant_joined"
+	EventParticipantApproved      EventType = "participant_approved"
+	EventParticipantRejected      EventType = "participant_rejected"
+	EventParticipantRemoved       EventType = "participant_removed"
+	EventRoleChanged              EventType = "role_changed"
+	EventTicketAdded              EventType = "ticket_added"
+	EventTicketEdited             EventType = "ticket_edited"
+	EventTicketDeleted            EventType = "ticket_deleted"
+	EventTicketMerged             EventType = "ticket_merged"
+	EventTicketUnmerged           EventType = "ticket_unmerged"
+	EventTicketCovered            EventType = "ticket_covered"
+	EventVoteAdded                EventType = "vote_added"
+	EventVoteRemoved              EventType = "vote_removed"
+	EventReactionAdded            EventType = "reaction_added"
+	EventReactionRemoved          EventType = "reaction_removed"
+	EventCommentAdded             EventType = "comment_added"
+	EventCommentEdited            EventType = "comment_edited"
+	EventCommentDeleted           EventType = "comment_deleted"
+	EventActionAdded              EventType = "action_added"
+	EventActionUpdated            EventType = "action_updated"
+	EventActionReviewed           EventType = "action_reviewed"
+	EventActionDeleted            EventType = "action_deleted"
+	EventPhaseChanged             EventType = "phase_changed"
+	EventPhasesChanged            EventType = "phases_changed"
+	EventTimerChanged             EventType = "timer_changed"
+	EventAutoApproveChanged       EventType = "auto_approve_changed"
+	EventMaxVotesPerTicketChanged EventType = "max_votes_per_ticket_changed"
+	EventHiddenVotesChanged       EventType = "hidden_votes_changed"
+	EventVotesRevealed            EventType = "votes_revealed"
+	EventAnonymousTicketsChanged  EventType = "anonymous_tickets_changed"
+	EventBlindTicketsChanged      EventType = "blind_tickets_changed"
+	EventTicketsRevealed          EventType = "tickets_revealed"
+	EventColumnsChanged           EventType = "columns_changed"
+	EventTicketMoved              EventType = "ticket_moved"
+	EventPollAnswered             EventType = "poll_answered"
+	EventPollClosed               EventType = "poll_closed"
+	EventHealthRated              EventType = "health_rated"
+	EventUndo                     EventType = "undo"
+)
+
+// RoomEvent is a single entry in a room's append-only event log
+type RoomEvent struct {
+	ID        int64           `json:"id"`
+	RoomID    string          `json:"room_id"`
+	Type      EventType       `json:"type"`
+	ActorID   string          `json:"actor_id"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// NewRoomEvent creates an event with its payload encoded as JSON
+func NewRoomEvent(roomID string, eventType EventType, actorID string, payload any) (*RoomEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &RoomEvent{
+		RoomID:    roomID,
+		Type:      eventType,
+		ActorID:   actorID,
+		Payload:   data,
+		CreatedAt: time.Now(),
+	}, nil
+}
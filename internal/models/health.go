@@ -0,0 +1,286 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// HealthDimension is one aspect of a team's health rated in a health check,
+// like "Speed" or "Fun". The description says what a healthy team looks like.
+type HealthDimension struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+// maxHealthDimensions caps how many dimensions a health check can rate
+const maxHealthDimensions = 16
+
+// HealthColor is how healthy a participant thinks a dimension is
+type HealthColor string
+
+const (
+	HealthGreen  HealthColor = "green"
+	HealthYellow HealthColor = "yellow"
+	HealthRed    HealthColor = "red"
+)
+
+// HealthTrend is whether a dimension is getting better or worse
+type HealthTrend string
+
+const (
+	TrendUp     HealthTrend = "up"
+	TrendStable HealthTrend = "stable"
+	TrendDown   HealthTrend = "down"
+)
+
+var (
+	// ErrInvalidHealthColor is returned for colors other than the HealthColor constants
+	ErrInvalidHealthColor = errors.New("color must be green, yellow or red")
+	// ErrInvalidHealthTrend is returned for trends other than the HealthTrend constants
+	ErrInvalidHealthTrend = errors.New("trend must be up, stable or down")
+	// ErrDimensionNotFound is returned when rating a dimension the room doesn't check
+	ErrDimensionNotFound = errors.New("dimension not found")
+)
+
+// HealthRating is a participant's rating of a single dimension
+type HealthRating struct {
+	Color HealthColor `json:"color"`
+	Trend HealthTrend `json:"trend"`
+}
+
+// Validate checks the rating's color and trend
+func (r HealthRating) Validate() error {
+	switch r.Color {
+	case HealthGreen, HealthYellow, HealthRed:
+	default:
+		return ErrInvalidHealthColor
+	}
+	switch r.Trend {
+	case TrendUp, TrendStable, TrendDown:
+	default:
+		return ErrInvalidHealthTrend
+	}
+	return nil
+}
+
+// ValidateHealthDimensions checks that dimension IDs are unique and made of
+// letters, digits, dashes and underscores, and that every dimension has a title
+func ValidateHealthDimensions(dimensions []HealthDimension) error {
+	if len(dimensions) > maxHealthDimensions {
+		return errors.New("a health check can rate at most 16 dimensions")
+	}
+	ids := make(map[string]bool, len(dimensions))
+	for _, dimension := range dimensions {
+		if !columnID.MatchString(dimension.ID) {
+			return errors.New("dimension ids may only contain letters, digits, dashes and underscores")
+		}
+		if dimension.Title == "" {
+			return errors.New("every dimension needs a title")
+		}
+		if ids[dimension.ID] {
+			return errors.New("dimension ids must be unique")
+		}
+		ids[dimension.ID] = true
+	}
+	return nil
+}
+
+// HealthResult aggregates the ratings of a dimension. Score is the average
+// color, from 0 when everyone says red to 1 when everyone says green, and
+// Trend the average trend from -1 (down) to 1 (up); both are 0 without
+// ratings.
+type HealthResult struct {
+	DimensionID string  `json:"dimension_id"`
+	Title       string  `json:"title"`
+	Ratings     int     `json:"ratings"`
+	Green       int     `json:"green"`
+	Yellow      int     `json:"yellow"`
+	Red         int     `json:"red"`
+	Up          int     `json:"up"`
+	Stable      int     `json:"stable"`
+	Down        int     `json:"down"`
+	Score       float64 `json:"score"`
+	Trend       float64 `json:"trend"`
+}
+
+// healthResults aggregates ratings, keyed by user then dimension, per
+// dimension in the dimensions' order
+func healthResults(dimensions []HealthDimension, ratings map[string]map[string]HealthRating) []HealthResult {
+	results := make([]HealthResult, len(dimensions))
+	index := make(map[string]int, len(dimensions))
+	for i, dimension := range dimensions {
+		results[i] = HealthResult{DimensionID: dimension.ID, Title: dimension.Title}
+		index[dimension.ID] = i
+	}
+	for _, byDimension := range ratings {
+		for dimensionID, rating := range byDimension {
+			i, ok := index[dimensionID]
+			if !ok {
+				continue
+			}
+			result := &results[i]
+			result.Ratings++
+			switch rating.Color {
+			case HealthGreen:
+				result.Green++
+			case HealthYellow:
+				result.Yellow++
+			case HealthRed:
+				result.Red++
+			}
+			switch rating.Trend {
+			case TrendUp:
+				result.Up++
+			case TrendStable:
+				result.Stable++
+			case TrendDown:
+				result.Down++
+			}
+		}
+	}
+	for i := range results {
+		result := &results[i]
+		if result.Ratings > 0 {
+			result.Score = (float64(result.Green) + float64(result.Yellow)/2) / float64(result.Ratings)
+			result.Trend = float64(result.Up-result.Down) / float64(result.Ratings)
+		}
+	}
+	return results
+}
+
+// HealthCheckView is a room's health check as a participant sees it: the
+// dimensions, their own ratings and the results of everyone's ratings
+type HealthCheckView struct {
+	Dimensions  []HealthDimension       `json:"dimensions"`
+	Ratings     map[string]HealthRating `json:"ratings"`
+	Respondents int                     `json:"respondents"`
+	Results     []HealthResult          `json:"results"`
+}
+
+// IsHealthCheck reports whether the room rates health dimensions
+func (r *Room) IsHealthCheck() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.HealthDimensions) > 0
+}
+
+// RateHealth records or replaces a user's rating of a dimension
+func (r *Room) RateHealth(userID, dimensionID string, rating HealthRating) error {
+	if err := rating.Validate(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	found := false
+	for _, dimension := range r.HealthDimensions {
+		if dimension.ID == dimensionID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return ErrDimensionNotFound
+	}
+	if r.HealthRatings == nil {
+		r.HealthRatings = make(map[string]map[string]HealthRating)
+	}
+	if r.HealthRatings[userID] == nil {
+		r.HealthRatings[userID] = make(map[string]HealthRating)
+	}
+	r.HealthRatings[userID][dimensionID] = rating
+	return nil
+}
+
+// HealthCheckView returns the room's health check as the viewer sees it, or
+// nil when the room doesn't rate health dimensions. The caller must hold the
+// room's read lock.
+func (r *Room) HealthCheckView(viewerID string) *HealthCheckView {
+	if len(r.HealthDimensions) == 0 {
+		return nil
+	}
+	own := make(map[string]HealthRating, len(r.HealthRatings[viewerID]))
+	for dimensionID, rating := range r.HealthRatings[viewerID] {
+		own[dimensionID] = rating
+	}
+	return &HealthCheckView{
+		Dimensions:  append([]HealthDimension{}, r.HealthDimensions...),
+		Ratings:     own,
+		Respondents: len(r.HealthRatings),
+		Results:     healthResults(r.HealthDimensions, r.HealthRatings),
+	}
+}
+
+// HealthCheck is the outcome of a single room's health check
+type HealthCheck struct {
+	RoomID      string         `json:"room_id"`
+	Name        string         `json:"name"`
+	CreatedAt   time.Time      `json:"created_at"`
+	Respondents int            `json:"respondents"`
+	Results     []HealthResult `json:"results"`
+}
+
+// HealthPoint is a dimension's result in one room
+type HealthPoint struct {
+	RoomID    string    `json:"room_id"`
+	CreatedAt time.Time `json:"created_at"`
+	Ratings   int       `json:"ratings"`
+	Score     float64   `json:"score"`
+	Trend     float64   `json:"trend"`
+}
+
+// HealthSeries follows a dimension across health checks. Dimensions are
+// matched by ID, so rooms created from the same template line up.
+type HealthSeries struct {
+	DimensionID string        `json:"dimension_id"`
+	Title       string        `json:"title"`
+	Points      []HealthPoint `json:"points"`
+}
+
+// HealthHistory compares health checks over time: each room's results,
+// oldest first, and the same results per dimension
+type HealthHistory struct {
+	Checks     []HealthCheck  `json:"checks"`
+	Dimensions []HealthSeries `json:"dimensions"`
+}
+
+// healthCheck returns the outcome of the room's health check. The caller
+// must hold the room's read lock.
+func (r *Room) healthCheck() HealthCheck {
+	return HealthCheck{
+		RoomID:      r.ID,
+		Name:        r.Name,
+		CreatedAt:   r.CreatedAt,
+		Respondents: len(r.HealthRatings),
+		Results:     healthResults(r.HealthDimensions, r.HealthRatings),
+	}
+}
+
+// newHealthHistory lines up health checks, oldest first, per dimension.
+// Series are listed in the order their dimension first appears and take the
+// latest title.
+func newHealthHistory(checks []HealthCheck) *HealthHistory {
+	history := &HealthHistory{Checks: checks, Dimensions: []HealthSeries{}}
+	index := make(map[string]int)
+	for _, check := range checks {
+		for _, result := range check.Results {
+			i, ok := index[result.DimensionID]
+			if !ok {
+				i = len(history.Dimensions)
+				index[result.DimensionID] = i
+				history.Dimensions = append(history.Dimensions, HealthSeries{DimensionID: result.DimensionID})
+			}
+			series := &history.Dimensions[i]
+			series.Title = result.Title
+			series.Points = append(series.Points, HealthPoint{
+				RoomID:    check.RoomID,
+				CreatedAt: check.CreatedAt,
+				Ratings:   result.Ratings,
+				Score:     result.Score,
+				Trend:     result.Trend,
+			})
+		}
+	}
+	return history
+}
@@ -0,0 +1,751 @@
+package models
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrRoomExists is returned when creating a room whose ID is already taken
+var ErrRoomExists = errors.New("room already exists")
+
+// ErrRoomNotFound is returned when updating a room that does not exist
+var ErrRoomNotFound = errors.New("room not found")
+
+// ErrTemplateNotFound is returned when updating or deleting a template that does not exist
+var ErrTemplateNotFound = errors.New("template not found")
+
+// ErrTeamNotFound is returned when changing a team that does not exist
+var ErrTeamNotFound = errors.New("team not found")
+
+// ErrVersionConflict is returned when writing a room that was modified since it was loaded
+var ErrVersionConflict = errors.New("room was modified concurrently")
+
+// MemoryStore is a thread-safe in-memory store for rooms, intended for local
+// development and tests. Rooms are copied on the way in and out so callers
+// never share state with the store.
+type MemoryStore struct {
+	rooms       map[string]*Room
+	votes       map[string][]*Vote // room ID -> votes, oldest first
+	events      []*RoomEvent
+	lastEventID int64
+	templates   map[string]*Template
+	teams       map[string]*Team
+	mu          sync.RWMutex
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore creates a new in-memory room store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		rooms:     make(map[string]*Room),
+		votes:     make(map[string][]*Vote),
+		templates: make(map[string]*Template),
+		teams:     make(map[string]*Team),
+	}
+}
+
+// Create adds a new room to the store
+func (s *MemoryStore) Create(room *Room) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.rooms[room.ID]; exists {
+		return ErrRoomExists
+	}
+	clone := room.Clone()
+	s.rooms[room.ID] = clone
+	s.votes[room.ID] = roomVotes(clone)
+	return nil
+}
+
+// Get retrieves a room by ID
+func (s *MemoryStore) Get(id string) (*Room, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	room, ok := s.rooms[id]
+	if !ok {
+		return nil, false
+	}
+	return s.load(room), true
+}
+
+// Update replaces the stored state of a room
+func (s *MemoryStore) Update(room *Room) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, exists := s.rooms[room.ID]
+	if !exists {
+		return ErrRoomNotFound
+	}
+	clone := room.Clone()
+	if clone.Version != stored.Version {
+		return ErrVersionConflict
+	}
+	clone.Version++
+	s.rooms[room.ID] = clone
+	s.votes[room.ID] = roomVotes(clone)
+	room.Lock()
+	room.Version = clone.Version
+	room.Unlock()
+	return nil
+}
+
+// Delete removes a room from the store
+func (s *MemoryStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.rooms, id)
+	delete(s.votes, id)
+
+	// Drop the room's event log along with it
+	events := s.events[:0]
+	for _, event := range s.events {
+		if event.RoomID != id {
+			events = append(events, event)
+		}
+	}
+	s.events = events
+	return nil
+}
+
+// List returns all rooms
+func (s *MemoryStore) List() []*Room {
+	return s.filter(func(*Room) bool { return true })
+}
+
+// ListByOwner returns all rooms owned by a user
+func (s *MemoryStore) ListByOwner(ownerID string) []*Room {
+	return s.filter(func(room *Room) bool { return room.OwnerID == ownerID })
+}
+
+// ListByParticipant returns all rooms where user is an approved participant
+func (s *MemoryStore) ListByParticipant(userID string) []*Room {
+	return s.filter(func(room *Room) bool {
+		_, ok := room.Participants[userID]
+		return ok
+	})
+}
+
+// ListByTeam returns all rooms belonging to a team
+func (s *MemoryStore) ListByTeam(teamID string) []*Room {
+	return s.filter(func(room *Room) bool { return room.TeamID == teamID })
+}
+
+// filter returns copies of the rooms matching the predicate, oldest first
+func (s *MemoryStore) filter(match func(*Room) bool) []*Room {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rooms := make([]*Room, 0)
+	for _, room := range s.rooms {
+		if match(room) {
+			rooms = append(rooms, s.load(room))
+		}
+	}
+	sort.Slice(rooms, func(i, j int) bool {
+		return rooms[i].CreatedAt.Before(rooms[j].CreatedAt)
+	})
+	return rooms
+}
+
+// UpdateRoomSettings persists the room's own fields (name, phase, settings)
+func (s *MemoryStore) UpdateRoomSettings(room *Room) error {
+	return s.apply(room, func(stored *Room) error {
+		room.RLock()
+		defer room.RUnlock()
+		copyRoomSettings(stored, room)
+		return nil
+	})
+}
+
+// copyRoomSettings copies the room's own fields onto the stored copy. The
+// caller must hold the room's read lock.
+func copyRoomSettings(stored, room *Room) {
+	stored.Name = room.Name
+	stored.OwnerID = room.OwnerID
+	stored.Phase = room.Phase
+	stored.Phases = append([]Phase{}, room.Phases...)
+	stored.Timer = cloneTimer(room.Timer)
+	stored.VotesPerUser = room.VotesPerUser
+	stored.MaxVotesPerTicket = room.MaxVotesPerTicket
+	stored.HiddenVotes = room.HiddenVotes
+	stored.VotesRevealed = room.VotesRevealed
+	stored.AnonymousTickets = room.AnonymousTickets
+	stored.BlindTickets = room.BlindTickets
+	stored.TicketsRevealed = room.TicketsRevealed
+	stored.AutoApprove = room.AutoApprove
+	stored.PreviousRoomID = room.PreviousRoomID
+	stored.TeamID = room.TeamID
+	stored.HealthDimensions = append([]HealthDimension(nil), room.HealthDimensions...)
+}
+
+// SetColumns replaces the room's board columns
+func (s *MemoryStore) SetColumns(room *Room) error {
+	return s.apply(room, func(stored *Room) error {
+		room.RLock()
+		defer room.RUnlock()
+		stored.Columns = append([]Column{}, room.Columns...)
+		return nil
+	})
+}
+
+// ChangePhase persists a phase change made with Room.SetPhase along with the
+// room's own fields, closing the previous phase history entry and adding the
+// new one
+func (s *MemoryStore) ChangePhase(room *Room) error {
+	return s.apply(room, func(stored *Room) error {
+		room.RLock()
+		defer room.RUnlock()
+		copyRoomSettings(stored, room)
+		stored.PhaseHistory = append([]PhaseHistoryEntry{}, room.PhaseHistory...)
+		return nil
+	})
+}
+
+// UpsertParticipant inserts or updates a participant, approved or pending
+func (s *MemoryStore) UpsertParticipant(room *Room, participant *Participant) error {
+	return s.apply(room, func(stored *Room) error {
+		room.RLock()
+		p := *participant
+		room.RUnlock()
+		delete(stored.Participants, p.User.ID)
+		delete(stored.PendingParticipants, p.User.ID)
+		if p.Status == StatusPending {
+			stored.PendingParticipants[p.User.ID] = &p
+		} else {
+			stored.Participants[p.User.ID] = &p
+		}
+		return nil
+	})
+}
+
+// RemoveParticipant removes a participant, approved or pending
+func (s *MemoryStore) RemoveParticipant(room *Room, userID string) error {
+	return s.apply(room, func(stored *Room) error {
+		delete(stored.Participants, userID)
+		delete(stored.PendingParticipants, userID)
+		return nil
+	})
+}
+
+// AddTicket inserts a new ticket
+func (s *MemoryStore) AddTicket(room *Room, ticket *Ticket) error {
+	return s.apply(room, func(stored *Room) error {
+		room.RLock()
+		defer room.RUnlock()
+		clone := ticket.Clone()
+		stored.Tickets[ticket.ID] = clone
+		s.votes[room.ID] = append(s.votes[room.ID], ticketVotes(room.ID, clone, time.Now())...)
+		return nil
+	})
+}
+
+// UpdateTicket persists a ticket's content, column, merge parent and covered flag
+func (s *MemoryStore) UpdateTicket(room *Room, ticket *Ticket) error {
+	return s.apply(room, func(stored *Room) error {
+		existing, ok := stored.Tickets[ticket.ID]
+		if !ok {
+			return nil
+		}
+		room.RLock()
+		defer room.RUnlock()
+		updated := ticket.Clone()
+		existing.Content = updated.Content
+		existing.ColumnID = updated.ColumnID
+		existing.DeduplicationTicketID = updated.DeduplicationTicketID
+		existing.Covered = updated.Covered
+		return nil
+	})
+}
+
+// DeleteTicket removes a ticket
+func (s *MemoryStore) DeleteTicket(room *Room, ticketID string) error {
+	return s.apply(room, func(stored *Room) error {
+		delete(stored.Tickets, ticketID)
+		s.removeVotes(stored.ID, func(v *Vote) bool { return v.TicketID == ticketID })
+		return nil
+	})
+}
+
+// AddVote records a user's vote on a ticket
+func (s *MemoryStore) AddVote(room *Room, ticketID, userID string) error {
+	return s.apply(room, func(stored *Room) error {
+		if _, ok := stored.Tickets[ticketID]; !ok {
+			return nil
+		}
+		for _, v := range s.votes[stored.ID] {
+			if v.TicketID == ticketID && v.UserID == userID {
+				v.Weight++
+				return nil
+			}
+		}
+		s.votes[stored.ID] = append(s.votes[stored.ID], &Vote{
+			RoomID:    stored.ID,
+			TicketID:  ticketID,
+			UserID:    userID,
+			Weight:    1,
+			CreatedAt: time.Now(),
+		})
+		return nil
+	})
+}
+
+// RemoveVote removes a user's vote from a ticket
+func (s *MemoryStore) RemoveVote(room *Room, ticketID, userID string) error {
+	return s.apply(room, func(stored *Room) error {
+		for _, v := range s.votes[stored.ID] {
+			if v.TicketID == ticketID && v.UserID == userID {
+				v.Weight--
+				break
+			}
+		}
+		s.removeVotes(stored.ID, func(v *Vote) bool { return v.Weight <= 0 })
+		return nil
+	})
+}
+
+// AddReaction records a user's emoji reaction to a ticket
+func (s *MemoryStore) AddReaction(room *Room, ticketID, userID, emoji string) error {
+	return s.apply(room, func(stored *Room) error {
+		stored.AddReaction(ticketID, userID, emoji)
+		return nil
+	})
+}
+
+// RemoveReaction removes a user's emoji reaction from a ticket
+func (s *MemoryStore) RemoveReaction(room *Room, ticketID, userID, emoji string) error {
+	return s.apply(room, func(stored *Room) error {
+		stored.RemoveReaction(ticketID, userID, emoji)
+		return nil
+	})
+}
+
+// AddComment inserts a new comment on a ticket
+func (s *MemoryStore) AddComment(room *Room, comment *Comment) error {
+	return s.apply(room, func(stored *Room) error {
+		stored.AddComment(comment.Clone())
+		return nil
+	})
+}
+
+// UpdateComment persists a comment's content and edit time
+func (s *MemoryStore) UpdateComment(room *Room, comment *Comment) error {
+	return s.apply(room, func(stored *Room) error {
+		if comment.UpdatedAt != nil {
+			stored.EditComment(comment.TicketID, comment.ID, comment.Content, *comment.UpdatedAt)
+		}
+		return nil
+	})
+}
+
+// DeleteComment removes a comment
+func (s *MemoryStore) DeleteComment(room *Room, ticketID, commentID string) error {
+	return s.apply(room, func(stored *Room) error {
+		stored.RemoveComment(ticketID, commentID)
+		return nil
+	})
+}
+
+// AddActionTicket inserts a new action item
+func (s *MemoryStore) AddActionTicket(room *Room, action *ActionTicket) error {
+	return s.apply(room, func(stored *Room) error {
+		room.RLock()
+		defer room.RUnlock()
+		stored.ActionTickets[action.ID] = action.Clone()
+		return nil
+	})
+}
+
+// UpdateActionTicket persists an action item's content, assignees, status,
+// owner, due date, status history and review outcome
+func (s *MemoryStore) UpdateActionTicket(room *Room, action *ActionTicket) error {
+	return s.apply(room, func(stored *Room) error {
+		room.RLock()
+		defer room.RUnlock()
+		stored.UpdateActionTicket(action.Clone())
+		return nil
+	})
+}
+
+// DeleteActionTicket removes an action item
+func (s *MemoryStore) DeleteActionTicket(room *Room, actionID string) error {
+	return s.apply(room, func(stored *Room) error {
+		delete(stored.ActionTickets, actionID)
+		return nil
+	})
+}
+
+// MarkCarriedOver persists the given action items as carried over to another room
+func (s *MemoryStore) MarkCarriedOver(room *Room, actionIDs []string, toRoomID string) error {
+	return s.apply(room, func(stored *Room) error {
+		for _, id := range actionIDs {
+			if action, ok := stored.ActionTickets[id]; ok {
+				marked := action.Clone()
+				marked.CarriedTo = toRoomID
+				stored.ActionTickets[id] = marked
+			}
+		}
+		return nil
+	})
+}
+
+// AnswerPoll records that a user answered a poll and, apart from who
+// answered, counts the score
+func (s *MemoryStore) AnswerPoll(room *Room, kind PollKind, userID string, score int) error {
+	return s.apply(room, func(stored *Room) error {
+		return stored.AnswerPoll(kind, userID, score)
+	})
+}
+
+// ClosePoll persists a poll being closed
+func (s *MemoryStore) ClosePoll(room *Room, kind PollKind) error {
+	closedAt := room.GetPoll(kind).ClosedAt
+	return s.apply(room, func(stored *Room) error {
+		if closedAt != nil {
+			stored.ClosePoll(kind, *closedAt)
+		}
+		return nil
+	})
+}
+
+// RateHealth records or replaces a user's rating of a health dimension
+func (s *MemoryStore) RateHealth(room *Room, userID, dimensionID string, rating HealthRating) error {
+	return s.apply(room, func(stored *Room) error {
+		return stored.RateHealth(userID, dimensionID, rating)
+	})
+}
+
+// Undo persists the changes of an undo command along with the event recording
+// it, assigning the event its ID
+func (s *MemoryStore) Undo(room *Room, undo *Undo) error {
+	return s.apply(room, func(stored *Room) error {
+		room.RLock()
+		defer room.RUnlock()
+
+		now := time.Now()
+		for _, restored := range undo.RestoredTickets {
+			clone := restored.Ticket.Clone()
+			stored.Tickets[clone.ID] = clone
+			s.votes[room.ID] = append(s.votes[room.ID], restoredVotes(room.ID, restored, now)...)
+		}
+		// Restored votes keep the time they were cast, so the votes are
+		// sorted again to stay oldest first
+		sort.SliceStable(s.votes[room.ID], func(i, j int) bool {
+			return s.votes[room.ID][i].CreatedAt.Before(s.votes[room.ID][j].CreatedAt)
+		})
+		for _, action := range undo.RestoredActions {
+			stored.ActionTickets[action.ID] = action.Clone()
+		}
+		for _, ticket := range undo.ParentResets {
+			if existing, ok := stored.Tickets[ticket.ID]; ok {
+				existing.DeduplicationTicketID = ticket.Clone().DeduplicationTicketID
+			}
+		}
+		if undo.PhaseChanges > 0 {
+			copyRoomSettings(stored, room)
+			stored.PhaseHistory = append([]PhaseHistoryEntry{}, room.PhaseHistory...)
+		}
+		s.appendEvent(undo.Event)
+		return nil
+	})
+}
+
+// ListVotes returns all votes cast in a room, oldest first
+func (s *MemoryStore) ListVotes(roomID string) ([]*Vote, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	votes := make([]*Vote, 0, len(s.votes[roomID]))
+	for _, vote := range s.votes[roomID] {
+		v := *vote
+		votes = append(votes, &v)
+	}
+	return votes, nil
+}
+
+// load returns a copy of a stored room with its vote counts derived from the stored votes
+func (s *MemoryStore) load(stored *Room) *Room {
+	room := stored.Clone()
+	room.clearVotes()
+	for _, vote := range s.votes[room.ID] {
+		room.applyVote(vote)
+	}
+	return room
+}
+
+// removeVotes drops the room's votes matching the predicate
+func (s *MemoryStore) removeVotes(roomID string, match func(*Vote) bool) {
+	votes := s.votes[roomID][:0]
+	for _, v := range s.votes[roomID] {
+		if !match(v) {
+			votes = append(votes, v)
+		}
+	}
+	s.votes[roomID] = votes
+}
+
+// Analytics aggregates the rooms selected by the filter
+func (s *MemoryStore) Analytics(filter AnalyticsFilter) (*Analytics, error) {
+	data := newAnalyticsData()
+	for _, room := range s.filter(filter.matches) {
+		data.addRoom(room)
+	}
+	return data.build(), nil
+}
+
+// HealthHistory returns the results of the health check rooms selected by
+// the filter, oldest first
+func (s *MemoryStore) HealthHistory(filter AnalyticsFilter) (*HealthHistory, error) {
+	rooms := s.filter(func(room *Room) bool {
+		return len(room.HealthDimensions) > 0 && filter.matches(room)
+	})
+	checks := make([]HealthCheck, 0, len(rooms))
+	for _, room := range rooms {
+		checks = append(checks, room.healthCheck())
+	}
+	return newHealthHistory(checks), nil
+}
+
+// AppendEvent records an event in the room's log and assigns its ID
+func (s *MemoryStore) AppendEvent(event *RoomEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.rooms[event.RoomID]; !ok {
+		return ErrRoomNotFound
+	}
+	s.appendEvent(event)
+	return nil
+}
+
+// appendEvent adds an event to the log and assigns its ID. The caller must
+// hold the store's lock.
+func (s *MemoryStore) appendEvent(event *RoomEvent) {
+	s.lastEventID++
+	event.ID = s.lastEventID
+	stored := *event
+	s.events = append(s.events, &stored)
+}
+
+// ListEvents returns up to limit events of a room with IDs greater than afterID, oldest first
+func (s *MemoryStore) ListEvents(roomID string, afterID int64, limit int) ([]*RoomEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	events := make([]*RoomEvent, 0)
+	for _, event := range s.events {
+		if len(events) == limit {
+			break
+		}
+		if event.RoomID == roomID && event.ID > afterID {
+			e := *event
+			events = append(events, &e)
+		}
+	}
+	return events, nil
+}
+
+// ListRecentEvents returns up to limit events of a room of the given types
+// with IDs less than beforeID, newest first. A beforeID of 0 starts from the
+// newest event.
+func (s *MemoryStore) ListRecentEvents(roomID string, types []EventType, beforeID int64, limit int) ([]*RoomEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	wanted := make(map[EventType]bool, len(types))
+	for _, eventType := range types {
+		wanted[eventType] = true
+	}
+
+	events := make([]*RoomEvent, 0)
+	for i := len(s.events) - 1; i >= 0 && len(events) < limit; i-- {
+		event := s.events[i]
+		if event.RoomID != roomID || (beforeID != 0 && event.ID >= beforeID) || !wanted[event.Type] {
+			continue
+		}
+		e := *event
+		events = append(events, &e)
+	}
+	return events, nil
+}
+
+// CreateTemplate adds a custom template
+func (s *MemoryStore) CreateTemplate(template *Template) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.templates[template.ID] = template.Clone()
+	return nil
+}
+
+// GetTemplate retrieves a custom template by ID
+func (s *MemoryStore) GetTemplate(id string) (*Template, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	template, ok := s.templates[id]
+	if !ok {
+		return nil, false
+	}
+	return template.Clone(), true
+}
+
+// ListTemplates returns the custom templates created by the user or shared
+// with one of the teams, oldest first
+func (s *MemoryStore) ListTemplates(userID string, teamIDs []string) ([]*Template, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	teams := make(map[string]bool, len(teamIDs))
+	for _, teamID := range teamIDs {
+		teams[teamID] = true
+	}
+	templates := make([]*Template, 0)
+	for _, template := range s.templates {
+		if template.CreatedBy == userID || (template.TeamID != "" && teams[template.TeamID]) {
+			templates = append(templates, template.Clone())
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		if !templates[i].CreatedAt.Equal(templates[j].CreatedAt) {
+			return templates[i].CreatedAt.Before(templates[j].CreatedAt)
+		}
+		return templates[i].ID < templates[j].ID
+	})
+	return templates, nil
+}
+
+// UpdateTemplate replaces a custom template
+func (s *MemoryStore) UpdateTemplate(template *Template) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.templates[template.ID]
+	if !ok {
+		return ErrTemplateNotFound
+	}
+	clone := template.Clone()
+	clone.CreatedBy, clone.CreatedAt = stored.CreatedBy, stored.CreatedAt
+	s.templates[template.ID] = clone
+	return nil
+}
+
+// DeleteTemplate removes a custom template
+func (s *MemoryStore) DeleteTemplate(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.templates[id]; !ok {
+		return ErrTemplateNotFound
+	}
+	delete(s.templates, id)
+	return nil
+}
+
+// CreateTeam adds a team along with its members
+func (s *MemoryStore) CreateTeam(team *Team) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.teams[team.ID] = team.Clone()
+	return nil
+}
+
+// GetTeam retrieves a team and its members by ID
+func (s *MemoryStore) GetTeam(id string) (*Team, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	team, ok := s.teams[id]
+	if !ok {
+		return nil, false
+	}
+	return team.Clone(), true
+}
+
+// ListTeamsByMember returns all teams the user is a member of, oldest first
+func (s *MemoryStore) ListTeamsByMember(userID string) ([]*Team, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	teams := make([]*Team, 0)
+	for _, team := range s.teams {
+		if team.IsMember(userID) {
+			teams = append(teams, team.Clone())
+		}
+	}
+	sort.Slice(teams, func(i, j int) bool {
+		if !teams[i].CreatedAt.Equal(teams[j].CreatedAt) {
+			return teams[i].CreatedAt.Before(teams[j].CreatedAt)
+		}
+		return teams[i].ID < teams[j].ID
+	})
+	return teams, nil
+}
+
+// DeleteTeam removes a team; its rooms and templates stay but no longer belong to a team
+func (s *MemoryStore) DeleteTeam(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.teams[id]; !ok {
+		return ErrTeamNotFound
+	}
+	delete(s.teams, id)
+	for _, room := range s.rooms {
+		if room.TeamID == id {
+			room.TeamID = ""
+			room.Version++
+		}
+	}
+	for _, template := range s.templates {
+		if template.TeamID == id {
+			template.TeamID = ""
+		}
+	}
+	return nil
+}
+
+// UpsertTeamMember inserts or updates a team member
+func (s *MemoryStore) UpsertTeamMember(teamID string, member *TeamMember) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	team, ok := s.teams[teamID]
+	if !ok {
+		return ErrTeamNotFound
+	}
+	m := *member
+	team.Members[member.User.ID] = &m
+	return nil
+}
+
+// RemoveTeamMember removes a member from a team
+func (s *MemoryStore) RemoveTeamMember(teamID, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	team, ok := s.teams[teamID]
+	if !ok {
+		return ErrTeamNotFound
+	}
+	delete(team.Members, userID)
+	return nil
+}
+
+// apply runs a change against the stored copy of a room and bumps its version,
+// failing with ErrVersionConflict if room is older than the stored copy
+func (s *MemoryStore) apply(room *Room, change func(stored *Room) error) error {
+	room.RLock()
+	roomID, version := room.ID, room.Version
+	room.RUnlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.rooms[roomID]
+	if !ok {
+		return ErrRoomNotFound
+	}
+	if stored.Version != version {
+		return ErrVersionConflict
+	}
+	if err := change(stored); err != nil {
+		return err
+	}
+	stored.Version++
+
+	room.Lock()
+	room.Version = stored.Version
+	room.Unlock()
+	return nil
+}
@@ -0,0 +1,232 @@
+package models
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var migrationFS embed.FS
+
+// migrationLockID is the advisory lock key that serializes migrations across instances
+const migrationLockID = 7420190011
+
+// migrationFilePattern matches migration files such as 0001_initial_schema.up.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a numbered, reversible schema change
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus reports whether a migration has been applied to the database
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// loadMigrations reads the embedded migrations ordered by version
+func loadMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFS, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+		content, err := migrationFS.ReadFile("migrations/" + entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s must have both up and down files", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// ensureMigrationsTable creates the schema_migrations tracking table
+func (s *RoomStore) ensureMigrationsTable() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMP NOT NULL
+		)
+	`)
+	return err
+}
+
+// querier is implemented by both *sql.DB and *sql.Tx
+type querier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// appliedMigrations returns the applied migration versions and when they were applied
+func appliedMigrations(q querier) (map[int]time.Time, error) {
+	rows, err := q.Query(`SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// MigrateUp applies all pending migrations in order and returns the ones applied
+func (s *RoomStore) MigrateUp() ([]Migration, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureMigrationsTable(); err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, m := range migrations {
+		applied, err := s.runMigration(m, true)
+		if err != nil {
+			return done, fmt.Errorf("migration %04d_%s up: %w", m.Version, m.Name, err)
+		}
+		if applied {
+			done = append(done, m)
+		}
+	}
+	return done, nil
+}
+
+// MigrateDown reverts up to steps of the most recently applied migrations and returns the ones reverted
+func (s *RoomStore) MigrateDown(steps int) ([]Migration, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureMigrationsTable(); err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		m := migrations[i]
+		reverted, err := s.runMigration(m, false)
+		if err != nil {
+			return done, fmt.Errorf("migration %04d_%s down: %w", m.Version, m.Name, err)
+		}
+		if reverted {
+			done = append(done, m)
+		}
+	}
+	return done, nil
+}
+
+// MigrationStatus lists every known migration and whether it has been applied
+func (s *RoomStore) MigrationStatus() ([]MigrationStatus, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureMigrationsTable(); err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		status := MigrationStatus{Migration: m}
+		if appliedAt, ok := applied[m.Version]; ok {
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// runMigration applies (up) or reverts (down) a single migration in its own
+// transaction, holding an advisory lock so concurrent instances don't race.
+// It reports whether anything was done.
+func (s *RoomStore) runMigration(m Migration, up bool) (bool, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1)`, migrationLockID); err != nil {
+		return false, err
+	}
+
+	applied, err := appliedMigrations(tx)
+	if err != nil {
+		return false, err
+	}
+	_, isApplied := applied[m.Version]
+	if up == isApplied {
+		return false, nil
+	}
+
+	if up {
+		if _, err := tx.Exec(m.Up); err != nil {
+			return false, err
+		}
+		if _, err := tx.Exec(`INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)`, m.Version, m.Name, time.Now()); err != nil {
+			return false, err
+		}
+	} else {
+		if _, err := tx.Exec(m.Down); err != nil {
+			return false, err
+		}
+		if _, err := tx.Exec(`DELETE FROM schema_migrations WHERE version = $1`, m.Version); err != nil {
+			return false, err
+		}
+	}
+
+	return true, tx.Commit()
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatalf("Failed to load migrations: %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("Expected at least one migration")
+	}
+
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			t.Errorf("Expected migration %d to have version %d, got %d", i, i+1, m.Version)
+		}
+		if m.Up == "" || m.Down == "" {
+			t.Errorf("Expected migration %04d_%s to have up and down SQL", m.Version, m.Name)
+		}
+	}
+
+	if migrations[0].Name != "initial_schema" {
+		t.Errorf("Expected first migration to be initial_schema, got '%s'", migrations[0].Name)
+	}
+}
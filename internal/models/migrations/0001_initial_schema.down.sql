@@ -0,0 +1,6 @@
+-- Revert the initial schema
+
+DROP TABLE IF EXISTS action_tickets;
+DROP TABLE IF EXISTS tickets;
+DROP TABLE IF EXISTS participants;
+DROP TABLE IF EXISTS rooms;
@@ -1,4 +1,4 @@
--- Schema for GoRetro PostgreSQL database
+-- Initial schema for GoRetro PostgreSQL database
 
 CREATE TABLE IF NOT EXISTS rooms (
     id VARCHAR(255) PRIMARY KEY,
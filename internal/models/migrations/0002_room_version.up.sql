@@ -0,0 +1,3 @@
+-- Version counter for optimistic concurrency control on room writes
+
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,13 @@
+-- Append-only log of changes made to a room
+
+CREATE TABLE IF NOT EXISTS room_events (
+    id BIGSERIAL PRIMARY KEY,
+    room_id VARCHAR(255) NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    actor_id VARCHAR(255) NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (room_id) REFERENCES rooms(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_room_events_room_id ON room_events(room_id, id);
@@ -0,0 +1,22 @@
+ALTER TABLE tickets
+    ADD COLUMN IF NOT EXISTS votes INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS voter_ids JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE participants ADD COLUMN IF NOT EXISTS votes_used INTEGER NOT NULL DEFAULT 0;
+
+UPDATE tickets t SET votes = v.votes, voter_ids = v.voter_ids
+FROM (
+    SELECT ticket_id, COUNT(*) AS votes, jsonb_agg(user_id ORDER BY created_at) AS voter_ids
+    FROM votes, generate_series(1, weight)
+    GROUP BY ticket_id
+) v
+WHERE t.id = v.ticket_id;
+
+UPDATE participants p SET votes_used = v.votes_used
+FROM (
+    SELECT room_id, user_id, SUM(weight) AS votes_used
+    FROM votes
+    GROUP BY room_id, user_id
+) v
+WHERE p.room_id = v.room_id AND p.user_id = v.user_id;
+
+DROP TABLE IF EXISTS votes;
@@ -0,0 +1,23 @@
+-- Votes as rows instead of counters and a JSON voter list on tickets
+
+CREATE TABLE IF NOT EXISTS votes (
+    room_id VARCHAR(255) NOT NULL,
+    ticket_id VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    weight INTEGER NOT NULL DEFAULT 1 CHECK (weight > 0),
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (ticket_id, user_id),
+    FOREIGN KEY (room_id) REFERENCES rooms(id) ON DELETE CASCADE,
+    FOREIGN KEY (ticket_id) REFERENCES tickets(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_votes_room_id ON votes(room_id, user_id);
+
+INSERT INTO votes (room_id, ticket_id, user_id, weight, created_at)
+SELECT t.room_id, t.id, v.user_id, COUNT(*), t.created_at
+FROM tickets t, jsonb_array_elements_text(t.voter_ids) AS v(user_id)
+GROUP BY t.room_id, t.id, v.user_id, t.created_at
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE tickets DROP COLUMN IF EXISTS votes, DROP COLUMN IF EXISTS voter_ids;
+ALTER TABLE participants DROP COLUMN IF EXISTS votes_used;
@@ -0,0 +1,3 @@
+-- How many votes a user can stack on a single ticket
+
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS max_votes_per_ticket INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,2 @@
+ALTER TABLE rooms DROP COLUMN IF EXISTS votes_revealed;
+ALTER TABLE rooms DROP COLUMN IF EXISTS hidden_votes;
@@ -0,0 +1,4 @@
+-- Hidden voting: participants only see their own votes until a moderator reveals them
+
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS hidden_votes BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS votes_revealed BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,3 @@
+-- Anonymous tickets: authors are only shown to themselves
+
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS anonymous_tickets BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,2 @@
+ALTER TABLE tickets DROP COLUMN IF EXISTS column_id;
+DROP TABLE IF EXISTS room_columns;
@@ -0,0 +1,14 @@
+-- Board columns: an ordered set of columns per room that tickets are sorted into
+
+CREATE TABLE IF NOT EXISTS room_columns (
+    room_id VARCHAR(255) NOT NULL,
+    id VARCHAR(255) NOT NULL,
+    title TEXT NOT NULL,
+    color VARCHAR(7) NOT NULL,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (room_id, id),
+    FOREIGN KEY (room_id) REFERENCES rooms(id) ON DELETE CASCADE
+);
+
+-- Tickets of rooms created before columns existed have no column
+ALTER TABLE tickets ADD COLUMN IF NOT EXISTS column_id VARCHAR(255) NOT NULL DEFAULT '';
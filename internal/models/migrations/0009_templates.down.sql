@@ -0,0 +1,2 @@
+ALTER TABLE room_columns DROP COLUMN IF EXISTS prompt;
+DROP TABLE IF EXISTS templates;
@@ -0,0 +1,18 @@
+-- Custom retrospective templates; built-in templates live in code
+
+CREATE TABLE IF NOT EXISTS templates (
+    id VARCHAR(255) PRIMARY KEY,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    columns JSONB NOT NULL,
+    votes_per_user INTEGER NOT NULL,
+    max_votes_per_ticket INTEGER NOT NULL,
+    phases JSONB NOT NULL,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_templates_created_by ON templates(created_by);
+
+-- Columns created from a template keep the template's writing prompt
+ALTER TABLE room_columns ADD COLUMN IF NOT EXISTS prompt TEXT NOT NULL DEFAULT '';
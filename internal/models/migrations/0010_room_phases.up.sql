@@ -0,0 +1,3 @@
+-- Each room runs its own ordered list of phases
+
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS phases JSONB NOT NULL DEFAULT '["TICKETING", "MERGING", "VOTING", "DISCUSSION", "SUMMARY"]';
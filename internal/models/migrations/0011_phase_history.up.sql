@@ -0,0 +1,16 @@
+-- When each room entered and left its phases
+
+CREATE TABLE IF NOT EXISTS phase_history (
+    id BIGSERIAL PRIMARY KEY,
+    room_id VARCHAR(255) NOT NULL,
+    phase VARCHAR(50) NOT NULL,
+    entered_at TIMESTAMP NOT NULL,
+    exited_at TIMESTAMP,
+    FOREIGN KEY (room_id) REFERENCES rooms(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_phase_history_room_id ON phase_history(room_id, entered_at);
+
+-- Existing rooms are taken to have been in their current phase since they were created
+INSERT INTO phase_history (room_id, phase, entered_at)
+SELECT id, phase, created_at FROM rooms;
@@ -0,0 +1,3 @@
+-- A room's phase timer, NULL when no timer is set
+
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS timer JSONB;
@@ -0,0 +1,2 @@
+ALTER TABLE rooms DROP COLUMN IF EXISTS tickets_revealed;
+ALTER TABLE rooms DROP COLUMN IF EXISTS blind_tickets;
@@ -0,0 +1,4 @@
+-- Blind ticket writing: tickets are only shown to their authors until revealed
+
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS blind_tickets BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS tickets_revealed BOOLEAN NOT NULL DEFAULT FALSE;
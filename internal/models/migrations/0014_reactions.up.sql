@@ -0,0 +1,14 @@
+-- Emoji reactions on tickets, one row per user and emoji
+
+CREATE TABLE IF NOT EXISTS reactions (
+    room_id VARCHAR(255) NOT NULL,
+    ticket_id VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    emoji VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (ticket_id, user_id, emoji),
+    FOREIGN KEY (room_id) REFERENCES rooms(id) ON DELETE CASCADE,
+    FOREIGN KEY (ticket_id) REFERENCES tickets(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_reactions_room_id ON reactions(room_id);
@@ -0,0 +1,5 @@
+ALTER TABLE action_tickets DROP COLUMN IF EXISTS status_history;
+ALTER TABLE action_tickets DROP COLUMN IF EXISTS completed_at;
+ALTER TABLE action_tickets DROP COLUMN IF EXISTS due_date;
+ALTER TABLE action_tickets DROP COLUMN IF EXISTS owner_id;
+ALTER TABLE action_tickets DROP COLUMN IF EXISTS status;
@@ -0,0 +1,7 @@
+-- Action item lifecycle: status, accountable owner, due date and status history
+
+ALTER TABLE action_tickets ADD COLUMN IF NOT EXISTS status VARCHAR(32) NOT NULL DEFAULT 'open';
+ALTER TABLE action_tickets ADD COLUMN IF NOT EXISTS owner_id VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE action_tickets ADD COLUMN IF NOT EXISTS due_date DATE;
+ALTER TABLE action_tickets ADD COLUMN IF NOT EXISTS completed_at TIMESTAMP;
+ALTER TABLE action_tickets ADD COLUMN IF NOT EXISTS status_history JSONB NOT NULL DEFAULT '[]';
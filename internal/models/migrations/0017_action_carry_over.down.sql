@@ -0,0 +1,4 @@
+ALTER TABLE action_tickets DROP COLUMN IF EXISTS review_outcome;
+ALTER TABLE action_tickets DROP COLUMN IF EXISTS source_action_id;
+ALTER TABLE action_tickets DROP COLUMN IF EXISTS source_room_id;
+ALTER TABLE rooms DROP COLUMN IF EXISTS previous_room_id;
@@ -0,0 +1,6 @@
+-- Carrying unfinished actions over from a previous room for review
+
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS previous_room_id VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE action_tickets ADD COLUMN IF NOT EXISTS source_room_id VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE action_tickets ADD COLUMN IF NOT EXISTS source_action_id VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE action_tickets ADD COLUMN IF NOT EXISTS review_outcome VARCHAR(32) NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_rooms_team_id;
+ALTER TABLE rooms DROP COLUMN IF EXISTS team_id;
+DROP TABLE IF EXISTS team_members;
+DROP TABLE IF EXISTS teams;
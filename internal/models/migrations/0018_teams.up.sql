@@ -0,0 +1,26 @@
+-- Teams whose members are approved in the team's rooms without asking
+
+CREATE TABLE IF NOT EXISTS teams (
+    id VARCHAR(255) PRIMARY KEY,
+    name TEXT NOT NULL,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS team_members (
+    team_id VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    user_email VARCHAR(255) NOT NULL DEFAULT '',
+    user_name VARCHAR(255) NOT NULL DEFAULT '',
+    role VARCHAR(32) NOT NULL,
+    joined_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (team_id, user_id),
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_members_user_id ON team_members(user_id);
+
+-- Rooms optionally belong to a team
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS team_id VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_rooms_team_id ON rooms(team_id);
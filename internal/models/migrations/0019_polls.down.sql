@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS poll_respondents;
+DROP TABLE IF EXISTS poll_scores;
+DROP TABLE IF EXISTS polls;
@@ -0,0 +1,27 @@
+-- Anonymous mood check-in and ROTI polls. Scores are only kept as counts,
+-- apart from who answered.
+
+CREATE TABLE IF NOT EXISTS polls (
+    room_id VARCHAR(255) NOT NULL,
+    kind VARCHAR(32) NOT NULL,
+    closed_at TIMESTAMP,
+    PRIMARY KEY (room_id, kind),
+    FOREIGN KEY (room_id) REFERENCES rooms(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS poll_scores (
+    room_id VARCHAR(255) NOT NULL,
+    kind VARCHAR(32) NOT NULL,
+    score INTEGER NOT NULL,
+    count INTEGER NOT NULL CHECK (count > 0),
+    PRIMARY KEY (room_id, kind, score),
+    FOREIGN KEY (room_id) REFERENCES rooms(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS poll_respondents (
+    room_id VARCHAR(255) NOT NULL,
+    kind VARCHAR(32) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    PRIMARY KEY (room_id, kind, user_id),
+    FOREIGN KEY (room_id) REFERENCES rooms(id) ON DELETE CASCADE
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS health_ratings;
+ALTER TABLE rooms DROP COLUMN IF EXISTS health_dimensions;
+ALTER TABLE templates DROP COLUMN IF EXISTS health_dimensions;
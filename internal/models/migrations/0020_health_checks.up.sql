@@ -0,0 +1,14 @@
+-- Health check rooms rate the dimensions defined by their template
+
+ALTER TABLE templates ADD COLUMN IF NOT EXISTS health_dimensions JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS health_dimensions JSONB NOT NULL DEFAULT '[]';
+
+CREATE TABLE IF NOT EXISTS health_ratings (
+    room_id VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    dimension_id VARCHAR(255) NOT NULL,
+    color VARCHAR(16) NOT NULL,
+    trend VARCHAR(16) NOT NULL,
+    PRIMARY KEY (room_id, user_id, dimension_id),
+    FOREIGN KEY (room_id) REFERENCES rooms(id) ON DELETE CASCADE
+);
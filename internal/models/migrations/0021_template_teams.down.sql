@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_templates_team_id;
+ALTER TABLE templates DROP COLUMN IF EXISTS team_id;
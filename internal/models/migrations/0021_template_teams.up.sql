@@ -0,0 +1,5 @@
+-- Custom templates can be shared with a team
+
+ALTER TABLE templates ADD COLUMN IF NOT EXISTS team_id VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_templates_team_id ON templates(team_id);
@@ -0,0 +1 @@
+ALTER TABLE action_tickets DROP COLUMN IF EXISTS carried_to;
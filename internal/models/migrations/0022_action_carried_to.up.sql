@@ -0,0 +1,3 @@
+-- Actions carried over to a follow-up room are not carried over again
+
+ALTER TABLE action_tickets ADD COLUMN IF NOT EXISTS carried_to VARCHAR(255) NOT NULL DEFAULT '';
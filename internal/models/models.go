@@ -9,11 +9,15 @@ import (
 type Phase string
 
 const (
-	PhaseTicketing  Phase = "TICKETING"
-	PhaseMerging    Phase = "MERGING"
-	PhaseVoting     Phase = "VOTING"
-	PhaseDiscussion Phase = "DISCUSSION"
-	PhaseSummary    Phase = "SUMMARY"
+	PhaseIcebreaker  Phase = "ICEBREAKER"
+	PhaseReview      Phase = "REVIEW"
+	PhaseHealthCheck Phase = "HEALTH_CHECK"
+	PhaseTicketing   Phase = "TICKETING"
+	PhaseMerging     Phase = "MERGING"
+	PhaseVoting      Phase = "VOTING"
+	PhaseDiscussion  Phase = "DISCUSSION"
+	PhaseSummary     Phase = "SUMMARY"
+	PhaseCheckout    Phase = "CHECKOUT"
 )
 
 // Role represents a user's role in a room
@@ -53,20 +57,71 @@ type Ticket struct {
 	ID                    string    `json:"id"`
 	Content               string    `json:"content"`
 	AuthorID              string    `json:"author_id"`
+	ColumnID              string    `json:"column_id"`
 	DeduplicationTicketID *string   `json:"deduplication_ticket_id,omitempty"`
 	Votes                 int       `json:"votes"`
 	VoterIDs              []string  `json:"voter_ids"`
 	Covered               bool      `json:"covered"`
 	CreatedAt             time.Time `json:"created_at"`
+	// Reactions maps each emoji to the users who reacted with it, in order
+	Reactions map[string][]string `json:"reactions,omitempty"`
+	// Comments is the ticket's comment thread, oldest first
+	Comments []*Comment `json:"comments,omitempty"`
 }
 
-// ActionTicket represents an action item from the discussion phase
+// Vote is a user's vote on a ticket; Weight counts the votes the user put on it
+type Vote struct {
+	RoomID    string    `json:"room_id"`
+	TicketID  string    `json:"ticket_id"`
+	UserID    string    `json:"user_id"`
+	Weight    int       `json:"weight"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// VoteCounts returns how many votes each user put on the ticket
+func (t *Ticket) VoteCounts() map[string]int {
+	counts := make(map[string]int)
+	for _, voterID := range t.VoterIDs {
+		counts[voterID]++
+	}
+	return counts
+}
+
+// WithVotesOf returns a copy of the ticket that only carries the given user's votes
+func (t *Ticket) WithVotesOf(userID string) *Ticket {
+	clone := t.Clone()
+	clone.VoterIDs = make([]string, 0, len(t.VoterIDs))
+	for _, voterID := range t.VoterIDs {
+		if voterID == userID {
+			clone.VoterIDs = append(clone.VoterIDs, voterID)
+		}
+	}
+	clone.Votes = len(clone.VoterIDs)
+	return clone
+}
+
+// ActionTicket represents an action item from the discussion phase. OwnerID
+// is the single participant accountable for it; DueDate is a calendar date.
 type ActionTicket struct {
-	ID          string    `json:"id"`
-	Content     string    `json:"content"`
-	AssigneeIDs []string  `json:"assignee_ids,omitempty"`
-	TicketID    string    `json:"ticket_id"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID            string               `json:"id"`
+	Content       string               `json:"content"`
+	AssigneeIDs   []string             `json:"assignee_ids,omitempty"`
+	TicketID      string               `json:"ticket_id"`
+	Status        ActionStatus         `json:"status"`
+	OwnerID       string               `json:"owner_id,omitempty"`
+	DueDate       *time.Time           `json:"due_date,omitempty"`
+	CompletedAt   *time.Time           `json:"completed_at,omitempty"`
+	StatusHistory []ActionStatusChange `json:"status_history,omitempty"`
+	CreatedAt     time.Time            `json:"created_at"`
+	// SourceRoomID and SourceActionID point to the action this one was
+	// carried over from
+	SourceRoomID   string `json:"source_room_id,omitempty"`
+	SourceActionID string `json:"source_action_id,omitempty"`
+	// CarriedTo is the room the action was carried over to, if any
+	CarriedTo string `json:"carried_to,omitempty"`
+	// ReviewOutcome is set once the action was reviewed in a follow-up
+	// retrospective, on both the carried over action and its source
+	ReviewOutcome ReviewOutcome `json:"review_outcome,omitempty"`
 }
 
 // Room represents a retrospective room
@@ -75,31 +130,161 @@ type Room struct {
 	Name                string                   `json:"name"`
 	OwnerID             string                   `json:"owner_id"`
 	Phase               Phase                    `json:"phase"`
+	Phases              []Phase                  `json:"phases"`
+	PhaseHistory        []PhaseHistoryEntry      `json:"phase_history"`
+	Timer               *Timer                   `json:"timer,omitempty"`
 	VotesPerUser        int                      `json:"votes_per_user"`
+	MaxVotesPerTicket   int                      `json:"max_votes_per_ticket"`
+	HiddenVotes         bool                     `json:"hidden_votes"`
+	VotesRevealed       bool                     `json:"votes_revealed"`
+	AnonymousTickets    bool                     `json:"anonymous_tickets"`
+	BlindTickets        bool                     `json:"blind_tickets"`
+	TicketsRevealed     bool                     `json:"tickets_revealed"`
 	AutoApprove         bool                     `json:"auto_approve"`
+	PreviousRoomID      string                   `json:"previous_room_id,omitempty"`
+	TeamID              string                   `json:"team_id,omitempty"`
+	Columns             []Column                 `json:"columns"`
 	Participants        map[string]*Participant  `json:"participants"`
 	PendingParticipants map[string]*Participant  `json:"pending_participants"`
 	Tickets             map[string]*Ticket       `json:"tickets"`
 	ActionTickets       map[string]*ActionTicket `json:"action_tickets"`
-	CreatedAt           time.Time                `json:"created_at"`
-	mu                  sync.RWMutex
+	Polls               map[PollKind]*Poll       `json:"polls,omitempty"`
+	// HealthDimensions are set, from the room's template, on health check
+	// rooms; HealthRatings are keyed by user, then dimension
+	HealthDimensions []HealthDimension                  `json:"health_dimensions,omitempty"`
+	HealthRatings    map[string]map[string]HealthRating `json:"health_ratings,omitempty"`
+	CreatedAt        time.Time                          `json:"created_at"`
+	Version          int64                              `json:"version"`
+	mu               sync.RWMutex
 }
 
 // NewRoom creates a new room with the given settings
 func NewRoom(id, name, ownerID string, votesPerUser int) *Room {
+	now := time.Now()
 	return &Room{
 		ID:                  id,
 		Name:                name,
 		OwnerID:             ownerID,
 		Phase:               PhaseTicketing,
+		Phases:              DefaultPhases(),
+		PhaseHistory:        []PhaseHistoryEntry{{Phase: PhaseTicketing, EnteredAt: now}},
 		VotesPerUser:        votesPerUser,
+		MaxVotesPerTicket:   1,
 		AutoApprove:         false,
+		Columns:             DefaultColumns(),
 		Participants:        make(map[string]*Participant),
 		PendingParticipants: make(map[string]*Participant),
 		Tickets:             make(map[string]*Ticket),
 		ActionTickets:       make(map[string]*ActionTicket),
-		CreatedAt:           time.Now(),
+		CreatedAt:           now,
+	}
+}
+
+// Clone returns a deep copy of the room that shares no mutable state with the original
+func (r *Room) Clone() *Room {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	clone := &Room{
+		ID:                  r.ID,
+		Name:                r.Name,
+		OwnerID:             r.OwnerID,
+		Phase:               r.Phase,
+		Phases:              append([]Phase{}, r.Phases...),
+		PhaseHistory:        append([]PhaseHistoryEntry{}, r.PhaseHistory...),
+		VotesPerUser:        r.VotesPerUser,
+		MaxVotesPerTicket:   r.MaxVotesPerTicket,
+		HiddenVotes:         r.HiddenVotes,
+		VotesRevealed:       r.VotesRevealed,
+		AnonymousTickets:    r.AnonymousTickets,
+		BlindTickets:        r.BlindTickets,
+		TicketsRevealed:     r.TicketsRevealed,
+		AutoApprove:         r.AutoApprove,
+		PreviousRoomID:      r.PreviousRoomID,
+		TeamID:              r.TeamID,
+		Columns:             append([]Column{}, r.Columns...),
+		Participants:        make(map[string]*Participant, len(r.Participants)),
+		PendingParticipants: make(map[string]*Participant, len(r.PendingParticipants)),
+		Tickets:             make(map[string]*Ticket, len(r.Tickets)),
+		ActionTickets:       make(map[string]*ActionTicket, len(r.ActionTickets)),
+		CreatedAt:           r.CreatedAt,
+		Version:             r.Version,
+	}
+	clone.Timer = cloneTimer(r.Timer)
+	for id, p := range r.Participants {
+		cp := *p
+		clone.Participants[id] = &cp
+	}
+	for id, p := range r.PendingParticipants {
+		cp := *p
+		clone.PendingParticipants[id] = &cp
+	}
+	for id, t := range r.Tickets {
+		clone.Tickets[id] = t.Clone()
+	}
+	for id, a := range r.ActionTickets {
+		clone.ActionTickets[id] = a.Clone()
+	}
+	if r.Polls != nil {
+		clone.Polls = make(map[PollKind]*Poll, len(r.Polls))
+		for kind, p := range r.Polls {
+			clone.Polls[kind] = p.Clone()
+		}
+	}
+	clone.HealthDimensions = append([]HealthDimension(nil), r.HealthDimensions...)
+	if r.HealthRatings != nil {
+		clone.HealthRatings = make(map[string]map[string]HealthRating, len(r.HealthRatings))
+		for userID, ratings := range r.HealthRatings {
+			clone.HealthRatings[userID] = make(map[string]HealthRating, len(ratings))
+			for dimensionID, rating := range ratings {
+				clone.HealthRatings[userID][dimensionID] = rating
+			}
+		}
 	}
+	return clone
+}
+
+// Clone returns a deep copy of the ticket
+func (t *Ticket) Clone() *Ticket {
+	clone := *t
+	if t.DeduplicationTicketID != nil {
+		parentID := *t.DeduplicationTicketID
+		clone.DeduplicationTicketID = &parentID
+	}
+	clone.VoterIDs = append([]string{}, t.VoterIDs...)
+	if t.Reactions != nil {
+		clone.Reactions = make(map[string][]string, len(t.Reactions))
+		for emoji, userIDs := range t.Reactions {
+			clone.Reactions[emoji] = append([]string{}, userIDs...)
+		}
+	}
+	if t.Comments != nil {
+		clone.Comments = make([]*Comment, len(t.Comments))
+		for i, comment := range t.Comments {
+			clone.Comments[i] = comment.Clone()
+		}
+	}
+	return &clone
+}
+
+// Clone returns a deep copy of the action ticket
+func (a *ActionTicket) Clone() *ActionTicket {
+	clone := *a
+	if a.AssigneeIDs != nil {
+		clone.AssigneeIDs = append([]string{}, a.AssigneeIDs...)
+	}
+	if a.DueDate != nil {
+		dueDate := *a.DueDate
+		clone.DueDate = &dueDate
+	}
+	if a.CompletedAt != nil {
+		completedAt := *a.CompletedAt
+		clone.CompletedAt = &completedAt
+	}
+	if a.StatusHistory != nil {
+		clone.StatusHistory = append([]ActionStatusChange{}, a.StatusHistory...)
+	}
+	return &clone
 }
 
 // Lock acquires write lock
@@ -202,23 +387,67 @@ func (r *Room) SetParticipantRole(userID string, role Role) bool {
 func (r *Room) IsModeratorOrOwner(userID string) bool {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
+	return r.isModeratorOrOwner(userID)
+}
+
+func (r *Room) isModeratorOrOwner(userID string) bool {
 	if p, ok := r.Participants[userID]; ok {
 		return p.Role == RoleOwner || p.Role == RoleModerator
 	}
 	return false
 }
 
-// AddTicket adds a new ticket to the room
+// AddTicket adds a ticket to the room, counting any votes it already carries
+// (such as a restored ticket) against its voters
 func (r *Room) AddTicket(ticket *Ticket) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.Tickets[ticket.ID] = ticket
+	for _, voterID := range ticket.VoterIDs {
+		if p, ok := r.Participants[voterID]; ok {
+			p.VotesUsed++
+		}
+	}
+}
+
+// RestoreTicket adds back a deleted ticket along with the votes its voters can
+// still afford. Deleting the ticket gave its votes back; a voter who has spent
+// them elsewhere since loses the votes that would take them over VotesPerUser.
+// It returns the number of votes dropped.
+func (r *Room) RestoreTicket(ticket *Ticket) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	voterIDs := make([]string, 0, len(ticket.VoterIDs))
+	dropped := 0
+	for _, voterID := range ticket.VoterIDs {
+		if p, ok := r.Participants[voterID]; ok {
+			if p.VotesUsed >= r.VotesPerUser {
+				dropped++
+				continue
+			}
+			p.VotesUsed++
+		}
+		voterIDs = append(voterIDs, voterID)
+	}
+	ticket.VoterIDs = voterIDs
+	ticket.Votes = len(voterIDs)
+	r.Tickets[ticket.ID] = ticket
+	return dropped
 }
 
-// RemoveTicket removes a ticket from the room
+// RemoveTicket removes a ticket from the room and gives its votes back to the voters
 func (r *Room) RemoveTicket(ticketID string) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	t, ok := r.Tickets[ticketID]
+	if !ok {
+		return
+	}
+	for _, voterID := range t.VoterIDs {
+		if p, ok := r.Participants[voterID]; ok && p.VotesUsed > 0 {
+			p.VotesUsed--
+		}
+	}
 	delete(r.Tickets, ticketID)
 }
 
@@ -252,14 +481,8 @@ func (r *Room) GetActionTicket(actionID string) (*ActionTicket, bool) {
 	return a, ok
 }
 
-// SetPhase changes the room's phase
-func (r *Room) SetPhase(phase Phase) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	r.Phase = phase
-}
-
-// Vote adds a vote to a ticket
+// Vote adds one of the user's votes to a ticket. A user can stack up to
+// MaxVotesPerTicket votes on the same ticket.
 func (r *Room) Vote(userID, ticketID string) bool {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -275,11 +498,9 @@ func (r *Room) Vote(userID, ticketID string) bool {
 		return false
 	}
 
-	// Check if user already voted on this ticket
-	for _, vid := range t.VoterIDs {
-		if vid == userID {
-			return false
-		}
+	// Check if user already put as many votes on this ticket as allowed
+	if t.VoteCounts()[userID] >= max(r.MaxVotesPerTicket, 1) {
+		return false
 	}
 
 	t.Votes++
@@ -288,7 +509,7 @@ func (r *Room) Vote(userID, ticketID string) bool {
 	return true
 }
 
-// Unvote removes a vote from a ticket
+// Unvote removes one of the user's votes from a ticket
 func (r *Room) Unvote(userID, ticketID string) bool {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -312,6 +533,69 @@ func (r *Room) Unvote(userID, ticketID string) bool {
 	return false
 }
 
+// VotesHidden reports whether participants can currently only see their own votes
+func (r *Room) VotesHidden() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.HiddenVotes && !r.VotesRevealed
+}
+
+// SetHiddenVotes turns hidden voting on or off. Turning it on hides votes again
+// even if they were revealed before.
+func (r *Room) SetHiddenVotes(hidden bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.HiddenVotes = hidden
+	r.VotesRevealed = false
+}
+
+// RevealVotes makes everyone's votes visible in a room with hidden voting
+func (r *Room) RevealVotes() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.VotesRevealed = true
+}
+
+// TicketsHidden reports whether blind ticket writing currently keeps tickets
+// from everyone but their authors
+func (r *Room) TicketsHidden() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.ticketsHidden()
+}
+
+func (r *Room) ticketsHidden() bool {
+	return r.BlindTickets && !r.TicketsRevealed
+}
+
+// SetBlindTickets turns blind ticket writing on or off. Turning it on hides
+// tickets again even if they were revealed before.
+func (r *Room) SetBlindTickets(blind bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.BlindTickets = blind
+	r.TicketsRevealed = false
+}
+
+// RevealTickets shows everyone's tickets in a room with blind ticket writing
+func (r *Room) RevealTickets() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.TicketsRevealed = true
+}
+
+// SetAnonymousTickets turns anonymous tickets on or off. Anonymity can't be
+// lifted once tickets were written under it.
+func (r *Room) SetAnonymousTickets(anonymous bool) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.AnonymousTickets && !anonymous && len(r.Tickets) > 0 {
+		return false
+	}
+	r.AnonymousTickets = anonymous
+	return true
+}
+
 // SetAutoApprove sets the auto-approve setting for the room
 func (r *Room) SetAutoApprove(autoApprove bool) {
 	r.mu.Lock()
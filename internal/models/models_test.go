@@ -1,7 +1,13 @@
 package models
 
 import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewRoom(t *testing.T) {
@@ -148,6 +154,37 @@ func TestRoom_Vote(t *testing.T) {
 	}
 }
 
+func TestRoom_StackedVotes(t *testing.T) {
+	room := NewRoom("room-1", "Test Room", "owner-1", 3)
+	room.MaxVotesPerTicket = 2
+	user := User{ID: "user-1", Email: "test@example.com", Name: "Test User"}
+	room.AddParticipant(user, RoleParticipant, StatusApproved)
+
+	ticket := &Ticket{ID: "ticket-1", Content: "Test ticket", AuthorID: "owner-1", VoterIDs: []string{}}
+	room.AddTicket(ticket)
+
+	if !room.Vote("user-1", "ticket-1") || !room.Vote("user-1", "ticket-1") {
+		t.Fatal("Expected two votes on the same ticket to succeed")
+	}
+	if room.Vote("user-1", "ticket-1") {
+		t.Error("Expected third vote on the same ticket to fail")
+	}
+
+	got, _ := room.GetTicket("ticket-1")
+	if got.Votes != 2 || got.VoteCounts()["user-1"] != 2 {
+		t.Errorf("Expected 2 votes by user-1, got %d (%v)", got.Votes, got.VoteCounts())
+	}
+
+	// Unvote takes back one vote at a time
+	if !room.Unvote("user-1", "ticket-1") {
+		t.Fatal("Expected unvote to succeed")
+	}
+	p, _ := room.GetParticipant("user-1")
+	if got.Votes != 1 || p.VotesUsed != 1 {
+		t.Errorf("Expected 1 vote left and 1 used, got %d votes and %d used", got.Votes, p.VotesUsed)
+	}
+}
+
 func TestRoom_Unvote(t *testing.T) {
 	room := NewRoom("room-1", "Test Room", "owner-1", 3)
 	user := User{ID: "user-1", Email: "test@example.com", Name: "Test User"}
@@ -194,6 +231,23 @@ func TestRoom_VotesPerUserLimit(t *testing.T) {
 	}
 }
 
+func TestRoom_RemoveTicketRefundsVotes(t *testing.T) {
+	room := NewRoom("room-1", "Test Room", "owner-1", 2)
+	user := User{ID: "user-1", Email: "test@example.com", Name: "Test User"}
+	room.AddParticipant(user, RoleParticipant, StatusApproved)
+
+	ticket := &Ticket{ID: "ticket-1", Content: "Ticket 1", AuthorID: "owner-1", VoterIDs: []string{}}
+	room.AddTicket(ticket)
+	room.Vote("user-1", "ticket-1")
+
+	room.RemoveTicket("ticket-1")
+
+	p, _ := room.GetParticipant("user-1")
+	if p.VotesUsed != 0 {
+		t.Errorf("Expected vote to be refunded, got %d votes used", p.VotesUsed)
+	}
+}
+
 func TestRoom_SetPhase(t *testing.T) {
 	room := NewRoom("room-1", "Test Room", "owner-1", 3)
 
@@ -207,3 +261,664 @@ func TestRoom_SetPhase(t *testing.T) {
 		t.Errorf("Expected phase VOTING, got '%s'", room.Phase)
 	}
 }
+
+func TestRoom_SetPhases(t *testing.T) {
+	room := NewRoom("room-1", "Test Room", "owner-1", 3)
+
+	invalid := [][]Phase{
+		nil,
+		{PhaseTicketing, PhaseTicketing},
+		{PhaseTicketing, "BRAINSTORM"},
+		{PhaseIcebreaker, PhaseVoting},
+	}
+	for _, phases := range invalid {
+		if err := room.SetPhases(phases); err == nil {
+			t.Errorf("Expected phases %v to be rejected", phases)
+		}
+	}
+
+	phases := []Phase{PhaseIcebreaker, PhaseTicketing, PhaseVoting, PhaseSummary, PhaseCheckout}
+	if err := room.SetPhases(phases); err != nil {
+		t.Fatalf("Expected phases to be accepted, got %v", err)
+	}
+	if room.HasPhase(PhaseMerging) || !room.HasPhase(PhaseCheckout) {
+		t.Errorf("Expected the room to run %v, got %v", phases, room.Phases)
+	}
+
+	// Without a discussion phase, actions move to the summary
+	room.SetPhase(PhaseSummary)
+	if !room.Allows(ActivityManageActions) || !room.Allows(ActivityMarkCovered) {
+		t.Error("Expected actions and covering tickets to be allowed in SUMMARY")
+	}
+	if room.Allows(ActivityAddTickets) || room.Allows(ActivityVote) {
+		t.Error("Expected tickets and votes not to be allowed in SUMMARY")
+	}
+
+	if err := room.SetPhases(DefaultPhases()); err != nil {
+		t.Fatalf("Expected default phases to be accepted, got %v", err)
+	}
+	if room.Allows(ActivityManageActions) {
+		t.Error("Expected actions to be back in DISCUSSION only")
+	}
+}
+
+func TestRoom_CheckTransition(t *testing.T) {
+	room := NewRoom("room-1", "Test Room", "owner-1", 3)
+
+	if err := room.CheckTransition(PhaseVoting, false); err == nil {
+		t.Error("Expected voting without tickets to be refused")
+	}
+	if err := room.CheckTransition(PhaseTicketing, false); !errors.Is(err, ErrAlreadyInPhase) {
+		t.Errorf("Expected ErrAlreadyInPhase, got %v", err)
+	}
+	if err := room.CheckTransition(PhaseIcebreaker, false); !errors.Is(err, ErrPhaseNotConfigured) {
+		t.Errorf("Expected ErrPhaseNotConfigured, got %v", err)
+	}
+
+	room.AddTicket(&Ticket{ID: "ticket-1", Content: "Ticket", VoterIDs: []string{}, CreatedAt: time.Now()})
+	if err := room.CheckTransition(PhaseVoting, false); err != nil {
+		t.Errorf("Expected skipping ahead to voting to be allowed, got %v", err)
+	}
+
+	room.SetPhase(PhaseVoting)
+	if err := room.CheckTransition(PhaseTicketing, false); !errors.Is(err, ErrConfirmationRequired) {
+		t.Errorf("Expected ErrConfirmationRequired, got %v", err)
+	}
+	if err := room.CheckTransition(PhaseTicketing, true); err != nil {
+		t.Errorf("Expected confirmed move back to be allowed, got %v", err)
+	}
+}
+
+func TestRoom_PhaseHistory(t *testing.T) {
+	room := NewRoom("room-1", "Test Room", "owner-1", 3)
+	started := room.PhaseStartedAt()
+
+	change := room.SetPhase(PhaseMerging)
+	if change.From != PhaseTicketing || change.To != PhaseMerging || !change.FromEnteredAt.Equal(started) {
+		t.Errorf("Unexpected phase change %+v", change)
+	}
+	if change.FromDuration() < 0 {
+		t.Errorf("Expected a non-negative duration, got %v", change.FromDuration())
+	}
+
+	if len(room.PhaseHistory) != 2 {
+		t.Fatalf("Expected two history entries, got %+v", room.PhaseHistory)
+	}
+	first, second := room.PhaseHistory[0], room.PhaseHistory[1]
+	if first.ExitedAt == nil || !first.ExitedAt.Equal(change.At) {
+		t.Errorf("Expected TICKETING to be closed when MERGING started, got %+v", first)
+	}
+	if second.Phase != PhaseMerging || second.ExitedAt != nil || !room.PhaseStartedAt().Equal(change.At) {
+		t.Errorf("Expected MERGING to be the open entry, got %+v", second)
+	}
+}
+
+func TestRoom_Timer(t *testing.T) {
+	room := NewRoom("room-1", "Test Room", "owner-1", 3)
+	now := time.Now()
+
+	if err := room.StartTimer(0, false, now); !errors.Is(err, ErrInvalidTimerDuration) {
+		t.Errorf("Expected ErrInvalidTimerDuration, got %v", err)
+	}
+	if err := room.PauseTimer(now); !errors.Is(err, ErrNoTimer) {
+		t.Errorf("Expected ErrNoTimer, got %v", err)
+	}
+
+	if err := room.StartTimer(5*time.Minute, true, now); err != nil {
+		t.Fatalf("Failed to start timer: %v", err)
+	}
+	if room.Timer.Phase != PhaseTicketing || !room.Timer.EndsAt.Equal(now.Add(5*time.Minute)) {
+		t.Errorf("Unexpected timer %+v", room.Timer)
+	}
+
+	// Pausing keeps the time that is left, resuming counts it down again
+	if err := room.PauseTimer(now.Add(2 * time.Minute)); err != nil {
+		t.Fatalf("Failed to pause timer: %v", err)
+	}
+	if room.Timer.Running() || room.Timer.RemainingAt(now.Add(time.Hour)) != 3*time.Minute {
+		t.Errorf("Expected a paused timer with 3 minutes left, got %+v", room.Timer)
+	}
+	if err := room.ExtendTimer(time.Minute); err != nil {
+		t.Fatalf("Failed to extend timer: %v", err)
+	}
+	resumedAt := now.Add(10 * time.Minute)
+	if err := room.ResumeTimer(resumedAt); err != nil {
+		t.Fatalf("Failed to resume timer: %v", err)
+	}
+	if !room.Timer.EndsAt.Equal(resumedAt.Add(4*time.Minute)) || room.Timer.Duration != 6*time.Minute {
+		t.Errorf("Expected the timer to end 4 minutes after resuming, got %+v", room.Timer)
+	}
+	if err := room.ExtendTimer(MaxTimerDuration); !errors.Is(err, ErrInvalidTimerDuration) {
+		t.Errorf("Expected ErrInvalidTimerDuration, got %v", err)
+	}
+
+	// Only the timer that is due expires
+	endsAt := room.Timer.EndsAt
+	if room.ExpireTimer(endsAt, endsAt.Add(-time.Second)) != nil || room.ExpireTimer(now, endsAt) != nil {
+		t.Error("Expected the timer not to expire early or for another end time")
+	}
+	if expired := room.ExpireTimer(endsAt, endsAt); expired == nil || !expired.AutoAdvance || room.Timer != nil {
+		t.Errorf("Expected the timer to expire, got %+v", expired)
+	}
+
+	// Changing phase stops the timer
+	room.StartTimer(time.Minute, false, now)
+	room.SetPhase(PhaseMerging)
+	if room.Timer != nil {
+		t.Errorf("Expected the timer to stop with the phase, got %+v", room.Timer)
+	}
+	if next, ok := room.NextPhase(); !ok || next != PhaseVoting {
+		t.Errorf("Expected VOTING to be next, got %s", next)
+	}
+}
+
+func TestRoom_TicketView(t *testing.T) {
+	room := NewRoom("room-1", "Test Room", "owner-1", 3)
+	room.AddParticipant(User{ID: "user-1", Name: "User 1"}, RoleParticipant, StatusApproved)
+	room.AddParticipant(User{ID: "user-2", Name: "User 2"}, RoleParticipant, StatusApproved)
+	ticket := &Ticket{ID: "ticket-1", Content: "Ticket 1", AuthorID: "user-1", VoterIDs: []string{}}
+	room.AddTicket(ticket)
+	room.Vote("user-1", "ticket-1")
+	room.Vote("user-2", "ticket-1")
+
+	if view := room.TicketView(ticket, "user-2"); view != ticket {
+		t.Error("Expected the ticket itself when nothing is hidden")
+	}
+
+	room.AnonymousTickets = true
+	room.HiddenVotes = true
+	if view := room.TicketView(ticket, "user-1"); view.AuthorID != "user-1" || view.Votes != 1 {
+		t.Errorf("Expected the author to see themselves and their own vote, got %q with %d votes", view.AuthorID, view.Votes)
+	}
+	view := room.TicketView(ticket, "user-2")
+	if view.AuthorID != "" {
+		t.Errorf("Expected the author to be hidden, got %q", view.AuthorID)
+	}
+	if view.Votes != 1 || len(view.VoterIDs) != 1 || view.VoterIDs[0] != "user-2" {
+		t.Errorf("Expected only the viewer's vote, got %v", view.VoterIDs)
+	}
+	if ticket.AuthorID != "user-1" || ticket.Votes != 2 {
+		t.Error("Expected the room's ticket to be left untouched")
+	}
+
+	room.RevealVotes()
+	if view := room.TicketView(ticket, "user-2"); view.Votes != 2 || view.AuthorID != "" {
+		t.Errorf("Expected all votes but no author after reveal, got %q with %d votes", view.AuthorID, view.Votes)
+	}
+
+	if room.SetAnonymousTickets(false) {
+		t.Error("Expected anonymity to stay on once tickets were written")
+	}
+}
+
+func TestRoom_BlindTickets(t *testing.T) {
+	room := NewRoom("room-1", "Test Room", "owner-1", 3)
+	room.AddParticipant(User{ID: "owner-1", Name: "Owner"}, RoleOwner, StatusApproved)
+	room.AddParticipant(User{ID: "user-1", Name: "User 1"}, RoleParticipant, StatusApproved)
+	room.AddTicket(&Ticket{ID: "ticket-1", Content: "Mine", AuthorID: "user-1", VoterIDs: []string{}})
+	room.AddTicket(&Ticket{ID: "ticket-2", Content: "Theirs", AuthorID: "owner-1", VoterIDs: []string{}})
+
+	room.SetBlindTickets(true)
+	if !room.TicketsHidden() || !room.PersonalizesTickets() {
+		t.Fatal("Expected tickets to be hidden")
+	}
+	if views := room.TicketViews("user-1"); len(views) != 1 || views["ticket-1"] == nil {
+		t.Errorf("Expected only the viewer's own ticket, got %v", views)
+	}
+	if count, ok := room.HiddenTicketCount("owner-1"); !ok || count != 1 {
+		t.Errorf("Expected moderators to be told one ticket is hidden, got %d %v", count, ok)
+	}
+	if _, ok := room.HiddenTicketCount("user-1"); ok {
+		t.Error("Expected participants not to be told the hidden ticket count")
+	}
+
+	// Leaving TICKETING reveals the tickets for good
+	change := room.SetPhase(PhaseMerging)
+	if !change.TicketsRevealed || room.TicketsHidden() {
+		t.Errorf("Expected leaving TICKETING to reveal tickets, got %+v", change)
+	}
+	room.SetPhase(PhaseTicketing)
+	if room.TicketsHidden() || len(room.TicketViews("user-1")) != 2 {
+		t.Error("Expected tickets to stay revealed after going back to TICKETING")
+	}
+
+	// Turning blind writing on again hides them until revealed
+	room.SetBlindTickets(true)
+	if !room.TicketsHidden() {
+		t.Error("Expected tickets to be hidden again")
+	}
+	room.RevealTickets()
+	if room.TicketsHidden() {
+		t.Error("Expected tickets to be revealed")
+	}
+}
+
+func TestRoom_Reactions(t *testing.T) {
+	room := NewRoom("room-1", "Test Room", "owner-1", 3)
+	room.AddTicket(&Ticket{ID: "ticket-1", Content: "Ticket", AuthorID: "owner-1", VoterIDs: []string{}})
+
+	for _, emoji := range []string{"", "ok", "👍 ", "1️⃣", "👍👍👍👍👍👍👍👍👍👍👍"} {
+		if err := ValidateReaction(emoji); err == nil {
+			t.Errorf("Expected %q to be rejected", emoji)
+		}
+	}
+	for _, emoji := range []string{"👍", "❤️", "👍🏽", "🏳️‍🌈"} {
+		if err := ValidateReaction(emoji); err != nil {
+			t.Errorf("Expected %q to be accepted, got %v", emoji, err)
+		}
+	}
+
+	if !room.AddReaction("ticket-1", "user-1", "👍") || !room.AddReaction("ticket-1", "user-2", "👍") || !room.AddReaction("ticket-1", "user-1", "🎉") {
+		t.Fatal("Failed to add reactions")
+	}
+	if room.AddReaction("ticket-1", "user-1", "👍") {
+		t.Error("Expected a second identical reaction to be refused")
+	}
+	if room.AddReaction("missing", "user-1", "👍") {
+		t.Error("Expected reacting to a missing ticket to fail")
+	}
+
+	ticket, _ := room.GetTicket("ticket-1")
+	if counts := ticket.ReactionCounts(); counts["👍"] != 2 || counts["🎉"] != 1 {
+		t.Errorf("Unexpected reaction counts %v", counts)
+	}
+	if ticket.Votes != 0 {
+		t.Error("Expected reactions not to count as votes")
+	}
+
+	if !room.RemoveReaction("ticket-1", "user-1", "🎉") || room.RemoveReaction("ticket-1", "user-1", "🎉") {
+		t.Error("Expected the reaction to be removed once")
+	}
+	if _, ok := ticket.Reactions["🎉"]; ok {
+		t.Error("Expected emoji without reactions to be dropped")
+	}
+}
+
+func TestRoom_Comments(t *testing.T) {
+	room := NewRoom("room-1", "Test Room", "owner-1", 3)
+	room.AddTicket(&Ticket{ID: "ticket-1", Content: "Ticket", AuthorID: "owner-1", VoterIDs: []string{}})
+
+	if _, err := NormalizeComment(" \n "); err == nil {
+		t.Error("Expected a blank comment to be rejected")
+	}
+	if _, err := NormalizeComment(strings.Repeat("a", MaxCommentLength+1)); err == nil {
+		t.Error("Expected a too long comment to be rejected")
+	}
+
+	if room.AddComment(&Comment{ID: "comment-1", TicketID: "missing"}) {
+		t.Error("Expected commenting on a missing ticket to fail")
+	}
+	room.AddComment(&Comment{ID: "comment-1", TicketID: "ticket-1", AuthorID: "user-1", Content: "First"})
+	room.AddComment(&Comment{ID: "comment-2", TicketID: "ticket-1", AuthorID: "user-2", Content: "Second"})
+
+	clone := room.Tickets["ticket-1"].Clone()
+	edited, ok := room.EditComment("ticket-1", "comment-1", "Changed", time.Now())
+	if !ok || edited.Content != "Changed" || edited.UpdatedAt == nil {
+		t.Fatalf("Expected the comment to be edited, got %+v", edited)
+	}
+	if clone.Comments[0].Content != "First" {
+		t.Error("Expected the ticket clone not to share comments")
+	}
+
+	if _, ok := room.RemoveComment("ticket-1", "comment-1"); !ok {
+		t.Fatal("Expected the comment to be removed")
+	}
+	if _, ok := room.GetComment("ticket-1", "comment-1"); ok {
+		t.Error("Expected the removed comment to be gone")
+	}
+	if remaining, _ := room.GetComment("ticket-1", "comment-2"); remaining.Content != "Second" {
+		t.Errorf("Expected the other comment to remain, got %+v", remaining)
+	}
+
+	// Comment authors are hidden in anonymous rooms like ticket authors
+	room.AnonymousTickets = true
+	view := room.TicketView(room.Tickets["ticket-1"], "user-1")
+	if view.Comments[0].AuthorID != "" || room.Tickets["ticket-1"].Comments[0].AuthorID != "user-2" {
+		t.Error("Expected only the view to hide the comment author")
+	}
+}
+
+func TestActionTicket_SetStatus(t *testing.T) {
+	action := &ActionTicket{ID: "action-1", Content: "Fix it", Status: ActionOpen, OwnerID: "owner-1", AssigneeIDs: []string{"user-1"}}
+
+	if ValidateActionStatus("finished") == nil {
+		t.Error("Expected an unknown status to be rejected")
+	}
+	if !action.IsAssigned("owner-1") || !action.IsAssigned("user-1") || action.IsAssigned("user-2") {
+		t.Error("Expected the owner and assignees to be assigned")
+	}
+
+	now := time.Now()
+	if action.SetStatus(ActionOpen, "user-1", now) {
+		t.Error("Expected setting the same status to be a no-op")
+	}
+	action.SetStatus(ActionInProgress, "user-1", now)
+	action.SetStatus(ActionDone, "user-1", now.Add(time.Hour))
+	if action.CompletedAt == nil || !action.CompletedAt.Equal(now.Add(time.Hour)) || !action.Closed() {
+		t.Errorf("Expected the action to be completed, got %+v", action)
+	}
+
+	clone := action.Clone()
+	action.SetStatus(ActionOpen, "owner-1", now.Add(2*time.Hour))
+	if action.CompletedAt != nil {
+		t.Error("Expected reopening to clear the completion time")
+	}
+	if len(action.StatusHistory) != 3 || action.StatusHistory[2].ChangedBy != "owner-1" {
+		t.Errorf("Expected three recorded status changes, got %+v", action.StatusHistory)
+	}
+	if clone.Status != ActionDone || len(clone.StatusHistory) != 2 {
+		t.Error("Expected the clone not to share the status history")
+	}
+}
+
+func TestRoom_CarryOverActions(t *testing.T) {
+	previous := NewRoom("room-1", "Sprint 1", "owner-1", 3)
+	now := time.Now()
+	previous.AddActionTicket(&ActionTicket{ID: "open", Content: "Open", Status: ActionOpen, OwnerID: "owner-1", CreatedAt: now})
+	previous.AddActionTicket(&ActionTicket{ID: "started", Content: "Started", Status: ActionInProgress, CreatedAt: now.Add(time.Second)})
+	previous.AddActionTicket(&ActionTicket{ID: "done", Content: "Done", Status: ActionDone, CreatedAt: now})
+
+	ids := 0
+	newID := func() string {
+		ids++
+		return fmt.Sprintf("carried-%d", ids)
+	}
+
+	room := NewRoom("room-2", "Sprint 2", "owner-1", 3)
+	room.CarryOverActions(previous, newID)
+	if room.PreviousRoomID != "room-1" || len(room.ActionTickets) != 2 {
+		t.Fatalf("Expected the two open actions to be carried over, got %v", room.ActionTickets)
+	}
+	carried, _ := room.GetActionTicket("carried-1")
+	if carried.SourceActionID != "open" || carried.SourceRoomID != "room-1" || carried.OwnerID != "owner-1" {
+		t.Errorf("Expected the oldest action to be carried first with its owner, got %+v", carried)
+	}
+	if room.Phase != PhaseReview || room.Phases[1] != PhaseTicketing {
+		t.Errorf("Expected the room to start with a review, got %v", room.Phases)
+	}
+
+	withIcebreaker := NewRoom("room-3", "Sprint 2", "owner-1", 3)
+	withIcebreaker.ConfigurePhases([]Phase{PhaseIcebreaker, PhaseTicketing})
+	withIcebreaker.CarryOverActions(previous, newID)
+	if withIcebreaker.Phase != PhaseIcebreaker || withIcebreaker.Phases[1] != PhaseReview {
+		t.Errorf("Expected the review after the icebreaker, got %v", withIcebreaker.Phases)
+	}
+
+	nothingOpen := NewRoom("room-4", "Sprint 3", "owner-1", 3)
+	nothingOpen.CarryOverActions(NewRoom("room-5", "Empty", "owner-1", 3), newID)
+	if nothingOpen.HasPhase(PhaseReview) {
+		t.Error("Expected no review without open actions")
+	}
+
+	carried.Review(ReviewRelevant, "owner-1", now)
+	if carried.Status != ActionOpen || carried.ReviewOutcome != ReviewRelevant {
+		t.Errorf("Expected a relevant action to stay open, got %+v", carried)
+	}
+	carried.Review(ReviewDropped, "owner-1", now)
+	if carried.Status != ActionDropped {
+		t.Errorf("Expected a dropped action to be closed, got %+v", carried)
+	}
+}
+
+func TestRoom_MarkCarriedOver(t *testing.T) {
+	previous := NewRoom("room-1", "Sprint 1", "owner-1", 3)
+	now := time.Now()
+	previous.AddActionTicket(&ActionTicket{ID: "open", Content: "Open", Status: ActionOpen, CreatedAt: now})
+	previous.AddActionTicket(&ActionTicket{ID: "done", Content: "Done", Status: ActionDone, CreatedAt: now})
+	previous.AddActionTicket(&ActionTicket{ID: "elsewhere", Content: "Elsewhere", Status: ActionOpen, CreatedAt: now, CarriedTo: "room-0"})
+
+	if ids := previous.MarkCarriedOver("room-2"); !slices.Equal(ids, []string{"open"}) {
+		t.Fatalf("Expected only the open action that wasn't carried over to be marked, got %v", ids)
+	}
+	if ids := previous.MarkCarriedOver("room-3"); len(ids) != 0 {
+		t.Errorf("Expected nothing left to mark, got %v", ids)
+	}
+
+	room := NewRoom("room-2", "Sprint 2", "owner-1", 3)
+	room.CarryOverActions(previous, func() string { return "carried" })
+	if carried, ok := room.GetActionTicket("carried"); !ok || carried.SourceActionID != "open" || len(room.ActionTickets) != 1 {
+		t.Errorf("Expected the action marked for the room to be carried over, got %v", room.ActionTickets)
+	}
+
+	other := NewRoom("room-3", "Sprint 2 again", "owner-1", 3)
+	other.CarryOverActions(previous, func() string { return "again" })
+	if len(other.ActionTickets) != 0 || other.HasPhase(PhaseReview) {
+		t.Errorf("Expected actions carried over to another room to be left out, got %v", other.ActionTickets)
+	}
+}
+
+func TestRoom_SetColumns(t *testing.T) {
+	room := NewRoom("room-1", "Test Room", "owner-1", 3)
+	room.AddTicket(&Ticket{ID: "ticket-1", Content: "Old", ColumnID: "went-well", VoterIDs: []string{}, CreatedAt: time.Now()})
+	room.AddTicket(&Ticket{ID: "ticket-2", Content: "New", ColumnID: "went-well", VoterIDs: []string{}, CreatedAt: time.Now().Add(time.Second)})
+
+	invalid := [][]Column{
+		{{ID: "a", Title: "", Color: "#000000"}},
+		{{ID: "a b", Title: "A", Color: "#000000"}},
+		{{ID: "a", Title: "A", Color: "red"}},
+		{{ID: "a", Title: "A", Color: "#000000"}, {ID: "a", Title: "B", Color: "#000000"}},
+		{{ID: "ideas", Title: "Ideas", Color: "#000000"}},
+	}
+	for _, columns := range invalid {
+		if err := room.SetColumns(columns); err == nil {
+			t.Errorf("Expected columns %+v to be rejected", columns)
+		}
+	}
+
+	columns := []Column{{ID: "went-well", Title: "Good", Color: "#00ff00"}, {ID: "new", Title: "New", Color: "#0000ff"}}
+	if err := room.SetColumns(columns); err != nil {
+		t.Fatalf("Expected columns to be accepted, got %v", err)
+	}
+
+	if id, ok := room.ResolveColumn(""); !ok || id != "went-well" {
+		t.Errorf("Expected tickets without a column to go into the first one, got %q", id)
+	}
+	if _, ok := room.ResolveColumn("missing"); ok {
+		t.Error("Expected unknown column to be rejected")
+	}
+
+	grouped := room.TicketIDsByColumn()
+	if ids := grouped["went-well"]; len(ids) != 2 || ids[0] != "ticket-1" || ids[1] != "ticket-2" {
+		t.Errorf("Expected tickets grouped oldest first, got %v", ids)
+	}
+	if ids, ok := grouped["new"]; !ok || len(ids) != 0 {
+		t.Errorf("Expected empty column to be listed, got %v", ids)
+	}
+}
+
+func TestTemplate_Validate(t *testing.T) {
+	for _, template := range BuiltinTemplates() {
+		if err := template.Validate(); err != nil {
+			t.Errorf("Expected built-in template %s to be valid, got %v", template.ID, err)
+		}
+	}
+
+	valid := func() *Template {
+		return &Template{
+			Name:              "Keep, Drop, Try",
+			Columns:           []Column{{ID: "keep", Title: "Keep", Color: "#22c55e"}},
+			VotesPerUser:      3,
+			MaxVotesPerTicket: 1,
+		}
+	}
+	template := valid()
+	if err := template.Validate(); err != nil {
+		t.Fatalf("Expected template to be valid, got %v", err)
+	}
+	if len(template.Phases) != len(DefaultPhases()) {
+		t.Errorf("Expected missing phases to default to the full sequence, got %v", template.Phases)
+	}
+
+	invalid := []func(*Template){
+		func(t *Template) { t.Name = "" },
+		func(t *Template) { t.Columns = nil },
+		func(t *Template) { t.Columns[0].Color = "green" },
+		func(t *Template) { t.VotesPerUser = 0 },
+		func(t *Template) { t.MaxVotesPerTicket = 4 },
+		func(t *Template) { t.Phases = []Phase{PhaseVoting, PhaseVoting} },
+		func(t *Template) { t.Phases = []Phase{"BRAINSTORM"} },
+		func(t *Template) { t.HealthDimensions = []HealthDimension{{ID: "fun"}} },
+		func(t *Template) {
+			t.HealthDimensions = []HealthDimension{{ID: "fun", Title: "Fun"}, {ID: "fun", Title: "More fun"}}
+		},
+	}
+	for i, change := range invalid {
+		template := valid()
+		change(template)
+		if err := template.Validate(); err == nil {
+			t.Errorf("Expected invalid template %d to be rejected", i)
+		}
+	}
+}
+
+func TestTemplate_ApplyTo(t *testing.T) {
+	template, ok := BuiltinTemplate("sailboat")
+	if !ok {
+		t.Fatal("Expected the sailboat template to exist")
+	}
+	room := NewRoom("room-1", "Test Room", "owner-1", 3)
+	if err := template.ApplyTo(room); err != nil {
+		t.Fatalf("Failed to apply template: %v", err)
+	}
+
+	if len(room.Columns) != 4 || room.Columns[0].ID != "wind" || room.Columns[0].Prompt == "" {
+		t.Errorf("Expected the template's columns, got %+v", room.Columns)
+	}
+	room.Columns[0].Title = "Changed"
+	if again, _ := BuiltinTemplate("sailboat"); again.Columns[0].Title != "Wind" {
+		t.Error("Expected built-in templates not to share columns with rooms")
+	}
+}
+
+func TestTeam_CheckMemberChange(t *testing.T) {
+	team := NewTeam("team-1", "Platform", User{ID: "admin"})
+	team.Members["member"] = &TeamMember{User: User{ID: "member"}, Role: TeamRoleMember}
+
+	if err := team.CheckMemberChange("admin", TeamRoleMember); !errors.Is(err, ErrLastTeamAdmin) {
+		t.Errorf("Expected the only admin not to be demoted, got %v", err)
+	}
+	if err := team.CheckMemberChange("admin", ""); !errors.Is(err, ErrLastTeamAdmin) {
+		t.Errorf("Expected the only admin not to be removed, got %v", err)
+	}
+	if err := team.CheckMemberChange("member", ""); err != nil {
+		t.Errorf("Expected members to be removable, got %v", err)
+	}
+
+	team.Members["member"].Role = TeamRoleAdmin
+	if err := team.CheckMemberChange("admin", ""); err != nil {
+		t.Errorf("Expected an admin to be removable when another is left, got %v", err)
+	}
+}
+
+func TestRoom_Polls(t *testing.T) {
+	room := NewRoom("room-1", "Test Room", "owner-1", 3)
+
+	if err := room.AnswerPoll(PollMood, "user-1", 4); err != nil {
+		t.Fatalf("Failed to answer poll: %v", err)
+	}
+	if err := room.AnswerPoll(PollMood, "user-2", 2); err != nil {
+		t.Fatalf("Failed to answer poll: %v", err)
+	}
+	if err := room.AnswerPoll(PollMood, "user-1", 5); !errors.Is(err, ErrAlreadyAnswered) {
+		t.Errorf("Expected a second answer to be rejected, got %v", err)
+	}
+	if err := room.AnswerPoll(PollROTI, "user-1", 6); !errors.Is(err, ErrInvalidPollScore) {
+		t.Errorf("Expected scores above 5 to be rejected, got %v", err)
+	}
+	if err := room.AnswerPoll("stress", "user-1", 3); !errors.Is(err, ErrInvalidPollKind) {
+		t.Errorf("Expected unknown polls to be rejected, got %v", err)
+	}
+
+	view := room.GetPoll(PollMood).View("user-1")
+	if view.Responses != 2 || !view.Answered || view.Closed || view.Histogram != nil {
+		t.Errorf("Expected only the number of answers while the poll is open, got %+v", view)
+	}
+	if view := room.GetPoll(PollMood).View("user-3"); view.Answered {
+		t.Error("Expected the poll not to be answered by someone who didn't")
+	}
+
+	if !room.ClosePoll(PollMood, time.Now()) || room.ClosePoll(PollMood, time.Now()) {
+		t.Error("Expected the poll to be closed once")
+	}
+	if err := room.AnswerPoll(PollMood, "user-3", 3); !errors.Is(err, ErrPollClosed) {
+		t.Errorf("Expected a closed poll to reject answers, got %v", err)
+	}
+	view = room.GetPoll(PollMood).View("user-3")
+	if view.Histogram == nil || *view.Histogram != [MaxPollScore]int{0, 1, 0, 1, 0} || view.Average != 3 {
+		t.Errorf("Expected the results once the poll is closed, got %+v", view)
+	}
+
+	room.RLock()
+	views := room.PollViews("user-1")
+	room.RUnlock()
+	if len(views) != 2 || views[0].Kind != PollMood || views[1].Kind != PollROTI || views[1].Responses != 0 {
+		t.Errorf("Expected both polls, got %+v", views)
+	}
+}
+
+func TestRoom_HealthCheck(t *testing.T) {
+	room := NewRoom("room-1", "Test Room", "owner-1", 3)
+	if room.IsHealthCheck() || room.Allows(ActivityRateHealth) {
+		t.Error("Expected rooms without dimensions not to be health checks")
+	}
+
+	template, _ := BuiltinTemplate("squad-health-check")
+	if err := template.ApplyTo(room); err != nil {
+		t.Fatalf("Failed to apply template: %v", err)
+	}
+	if !room.IsHealthCheck() || room.Phase != PhaseHealthCheck || !room.Allows(ActivityRateHealth) {
+		t.Fatalf("Expected the room to start rating health, got phase %s", room.Phase)
+	}
+
+	if err := room.RateHealth("user-1", "fun", HealthRating{Color: "blue", Trend: TrendUp}); !errors.Is(err, ErrInvalidHealthColor) {
+		t.Errorf("Expected unknown colors to be rejected, got %v", err)
+	}
+	if err := room.RateHealth("user-1", "fun", HealthRating{Color: HealthGreen}); !errors.Is(err, ErrInvalidHealthTrend) {
+		t.Errorf("Expected a missing trend to be rejected, got %v", err)
+	}
+	if err := room.RateHealth("user-1", "salary", HealthRating{Color: HealthGreen, Trend: TrendUp}); !errors.Is(err, ErrDimensionNotFound) {
+		t.Errorf("Expected unknown dimensions to be rejected, got %v", err)
+	}
+
+	ratings := []struct {
+		userID string
+		rating HealthRating
+	}{
+		{"user-1", HealthRating{Color: HealthRed, Trend: TrendDown}},
+		{"user-1", HealthRating{Color: HealthGreen, Trend: TrendUp}},
+		{"user-2", HealthRating{Color: HealthYellow, Trend: TrendUp}},
+		{"user-3", HealthRating{Color: HealthGreen, Trend: TrendStable}},
+	}
+	for _, r := range ratings {
+		if err := room.RateHealth(r.userID, "fun", r.rating); err != nil {
+			t.Fatalf("Failed to rate health: %v", err)
+		}
+	}
+
+	room.RLock()
+	view := room.HealthCheckView("user-1")
+	room.RUnlock()
+	if view.Respondents != 3 || view.Ratings["fun"].Color != HealthGreen || len(view.Results) != len(template.HealthDimensions) {
+		t.Fatalf("Expected the latest rating of each user, got %+v", view)
+	}
+	for _, result := range view.Results {
+		if result.DimensionID != "fun" {
+			if result.Ratings != 0 || result.Score != 0 {
+				t.Errorf("Expected no ratings for %s, got %+v", result.DimensionID, result)
+			}
+			continue
+		}
+		if result.Ratings != 3 || result.Green != 2 || result.Yellow != 1 || result.Up != 2 || result.Stable != 1 {
+			t.Errorf("Expected the ratings to be counted, got %+v", result)
+		}
+		if math.Abs(result.Score-2.5/3) > 1e-9 || math.Abs(result.Trend-2.0/3) > 1e-9 {
+			t.Errorf("Expected score 5/6 and trend 2/3, got %v and %v", result.Score, result.Trend)
+		}
+	}
+
+	clone := room.Clone()
+	clone.HealthRatings["user-1"]["fun"] = HealthRating{Color: HealthRed, Trend: TrendDown}
+	if room.HealthRatings["user-1"]["fun"].Color != HealthGreen {
+		t.Error("Expected clones not to share ratings")
+	}
+}
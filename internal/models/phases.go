@@ -0,0 +1,302 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// knownPhases lists every phase a room can be configured with, in the order
+// they usually run
+var knownPhases = []Phase{
+	PhaseIcebreaker,
+	PhaseReview,
+	PhaseHealthCheck,
+	PhaseTicketing,
+	PhaseMerging,
+	PhaseVoting,
+	PhaseDiscussion,
+	PhaseSummary,
+	PhaseCheckout,
+}
+
+// DefaultPhases returns the phases a retrospective goes through, in order
+func DefaultPhases() []Phase {
+	return []Phase{PhaseTicketing, PhaseMerging, PhaseVoting, PhaseDiscussion, PhaseSummary}
+}
+
+// ValidatePhases checks that a phase sequence is not empty and lists known phases at most once
+func ValidatePhases(phases []Phase) error {
+	if len(phases) == 0 {
+		return errors.New("at least one phase is required")
+	}
+	known := make(map[Phase]bool, len(knownPhases))
+	for _, phase := range knownPhases {
+		known[phase] = true
+	}
+	seen := make(map[Phase]bool, len(phases))
+	for _, phase := range phases {
+		if !known[phase] {
+			return fmt.Errorf("unknown phase %q", phase)
+		}
+		if seen[phase] {
+			return fmt.Errorf("phase %q is listed twice", phase)
+		}
+		seen[phase] = true
+	}
+	return nil
+}
+
+// Activity is something participants can only do in some phases
+type Activity string
+
+const (
+	ActivityAddTickets    Activity = "add_tickets"
+	ActivityAutoMerge     Activity = "auto_merge"
+	ActivityVote          Activity = "vote"
+	ActivityManageActions Activity = "manage_actions"
+	ActivityMarkCovered   Activity = "mark_covered"
+	ActivityReact         Activity = "react"
+	ActivityReviewActions Activity = "review_actions"
+	ActivityMoodCheck     Activity = "mood_check"
+	ActivityROTI          Activity = "roti"
+	ActivityRateHealth    Activity = "rate_health"
+)
+
+// activityRule says in which phases an activity is allowed. When a room's
+// pipeline has none of those phases, the fallback phases are used instead so
+// that skipping a phase doesn't lock the activity out altogether.
+type activityRule struct {
+	phases   []Phase
+	fallback []Phase
+}
+
+var activityRules = map[Activity]activityRule{
+	ActivityAddTickets:    {phases: []Phase{PhaseTicketing}},
+	ActivityAutoMerge:     {phases: []Phase{PhaseMerging}},
+	ActivityVote:          {phases: []Phase{PhaseVoting}},
+	ActivityManageActions: {phases: []Phase{PhaseDiscussion}, fallback: []Phase{PhaseSummary}},
+	ActivityMarkCovered:   {phases: []Phase{PhaseDiscussion, PhaseSummary}},
+	ActivityReact:         {phases: knownPhases},
+	ActivityReviewActions: {phases: []Phase{PhaseReview}, fallback: []Phase{PhaseDiscussion}},
+	ActivityMoodCheck:     {phases: []Phase{PhaseIcebreaker}, fallback: []Phase{PhaseTicketing}},
+	ActivityROTI:          {phases: []Phase{PhaseCheckout}, fallback: []Phase{PhaseSummary}},
+	ActivityRateHealth:    {phases: []Phase{PhaseHealthCheck}, fallback: []Phase{PhaseTicketing}},
+}
+
+// ErrPhaseNotConfigured is returned when moving a room to a phase that is not in its pipeline
+var ErrPhaseNotConfigured = errors.New("phase is not part of the room's pipeline")
+
+// ErrAlreadyInPhase is returned when moving a room to the phase it is already in
+var ErrAlreadyInPhase = errors.New("room is already in this phase")
+
+// ErrConfirmationRequired is returned when going back to an earlier phase without confirming it
+var ErrConfirmationRequired = errors.New("going back to an earlier phase has to be confirmed")
+
+// phaseGuards are checked before a room enters a phase. They return why the
+// phase can't be entered yet.
+var phaseGuards = map[Phase]func(r *Room) error{
+	PhaseVoting: func(r *Room) error {
+		if len(r.Tickets) == 0 {
+			return errors.New("there are no tickets to vote on")
+		}
+		return nil
+	},
+	PhaseDiscussion: func(r *Room) error {
+		if len(r.Tickets) == 0 {
+			return errors.New("there are no tickets to discuss")
+		}
+		return nil
+	},
+}
+
+// PhaseHistoryEntry records when a room entered a phase and, unless it is
+// still in it, when it left
+type PhaseHistoryEntry struct {
+	Phase     Phase      `json:"phase"`
+	EnteredAt time.Time  `json:"entered_at"`
+	ExitedAt  *time.Time `json:"exited_at,omitempty"`
+}
+
+// PhaseChange describes a room moving from one phase to another
+type PhaseChange struct {
+	From          Phase
+	To            Phase
+	FromEnteredAt time.Time
+	At            time.Time
+	// TicketsRevealed is set when leaving TICKETING revealed blind tickets
+	TicketsRevealed bool
+}
+
+// FromDuration returns how long the room spent in the phase it left
+func (c PhaseChange) FromDuration() time.Duration {
+	if c.FromEnteredAt.IsZero() {
+		return 0
+	}
+	return c.At.Sub(c.FromEnteredAt)
+}
+
+// CheckTransition reports whether the room may move to the phase. Moving
+// forward, even past several phases, is always possible; going back has to be
+// confirmed. The phase's guard has to pass either way.
+func (r *Room) CheckTransition(to Phase, confirmed bool) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if !r.hasPhase(to) {
+		return ErrPhaseNotConfigured
+	}
+	if to == r.Phase {
+		return ErrAlreadyInPhase
+	}
+	if r.phaseIndex(to) < r.phaseIndex(r.Phase) && !confirmed {
+		return ErrConfirmationRequired
+	}
+	if guard, ok := phaseGuards[to]; ok {
+		if err := guard(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// phaseIndex returns the position of the phase in the room's pipeline, or -1
+func (r *Room) phaseIndex(phase Phase) int {
+	for i, p := range r.Phases {
+		if p == phase {
+			return i
+		}
+	}
+	return -1
+}
+
+// SetPhase moves the room to the phase without checking CheckTransition and
+// records the change in the room's phase history. The previous phase's timer
+// is stopped, and leaving TICKETING reveals blind tickets.
+func (r *Room) SetPhase(phase Phase) PhaseChange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Timer = nil
+
+	change := PhaseChange{From: r.Phase, To: phase, At: time.Now()}
+	if r.Phase == PhaseTicketing && phase != PhaseTicketing && r.ticketsHidden() {
+		r.TicketsRevealed = true
+		change.TicketsRevealed = true
+	}
+	if n := len(r.PhaseHistory); n > 0 && r.PhaseHistory[n-1].ExitedAt == nil {
+		exitedAt := change.At
+		change.FromEnteredAt = r.PhaseHistory[n-1].EnteredAt
+		r.PhaseHistory[n-1].ExitedAt = &exitedAt
+	}
+	r.PhaseHistory = append(r.PhaseHistory, PhaseHistoryEntry{Phase: phase, EnteredAt: change.At})
+	r.Phase = phase
+	return change
+}
+
+// PhaseStartedAt returns when the room entered its current phase. The caller
+// must hold the room's read lock.
+func (r *Room) PhaseStartedAt() time.Time {
+	if n := len(r.PhaseHistory); n > 0 {
+		return r.PhaseHistory[n-1].EnteredAt
+	}
+	return r.CreatedAt
+}
+
+// ConfigurePhases sets the phase pipeline of a room that has not started yet
+// and puts it in the first phase
+func (r *Room) ConfigurePhases(phases []Phase) error {
+	if err := ValidatePhases(phases); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Phases = append([]Phase{}, phases...)
+	r.Phase = phases[0]
+	r.PhaseHistory = []PhaseHistoryEntry{{Phase: phases[0], EnteredAt: r.CreatedAt}}
+	return nil
+}
+
+// HasPhase reports whether the phase is part of the room's pipeline
+func (r *Room) HasPhase(phase Phase) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.hasPhase(phase)
+}
+
+func (r *Room) hasPhase(phase Phase) bool {
+	for _, p := range r.Phases {
+		if p == phase {
+			return true
+		}
+	}
+	return false
+}
+
+// SetPhases replaces the room's phase pipeline. The phase the room is
+// currently in can't be removed.
+func (r *Room) SetPhases(phases []Phase) error {
+	if err := ValidatePhases(phases); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	found := false
+	for _, phase := range phases {
+		if phase == r.Phase {
+			found = true
+		}
+	}
+	if !found {
+		return errors.New("the current phase can't be removed")
+	}
+	r.Phases = append([]Phase{}, phases...)
+	return nil
+}
+
+// Allows reports whether the activity is allowed in the room's current phase
+func (r *Room) Allows(activity Activity) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.allows(activity)
+}
+
+func (r *Room) allows(activity Activity) bool {
+	// Only health check rooms have dimensions to rate
+	if activity == ActivityRateHealth && len(r.HealthDimensions) == 0 {
+		return false
+	}
+	rule := activityRules[activity]
+	phases := rule.phases
+	if !r.hasAnyPhase(phases) {
+		phases = rule.fallback
+	}
+	for _, phase := range phases {
+		if r.Phase == phase {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Room) hasAnyPhase(phases []Phase) bool {
+	for _, phase := range phases {
+		if r.hasPhase(phase) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowedActivities returns the activities allowed in the room's current
+// phase. The caller must hold the room's read lock.
+func (r *Room) AllowedActivities() []Activity {
+	activities := make([]Activity, 0)
+	for _, activity := range []Activity{ActivityAddTickets, ActivityAutoMerge, ActivityVote, ActivityManageActions, ActivityMarkCovered, ActivityReact, ActivityReviewActions, ActivityMoodCheck, ActivityROTI, ActivityRateHealth} {
+		if r.allows(activity) {
+			activities = append(activities, activity)
+		}
+	}
+	return activities
+}
@@ -0,0 +1,201 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// PollKind identifies one of the anonymous polls a room runs
+type PollKind string
+
+const (
+	// PollMood is the mood check-in at the start of a retrospective
+	PollMood PollKind = "mood"
+	// PollROTI rates the return on time invested at its end
+	PollROTI PollKind = "roti"
+)
+
+// PollKinds lists the polls every room has, in the order they run
+var PollKinds = []PollKind{PollMood, PollROTI}
+
+// Poll answers are scores from MinPollScore to MaxPollScore
+const (
+	MinPollScore = 1
+	MaxPollScore = 5
+)
+
+var (
+	// ErrInvalidPollKind is returned for kinds other than the PollKind constants
+	ErrInvalidPollKind = errors.New("poll must be mood or roti")
+	// ErrInvalidPollScore is returned for scores outside of the poll's scale
+	ErrInvalidPollScore = errors.New("score must be between 1 and 5")
+	// ErrPollClosed is returned when answering a poll a moderator closed
+	ErrPollClosed = errors.New("poll is closed")
+	// ErrAlreadyAnswered is returned when answering a poll a second time
+	ErrAlreadyAnswered = errors.New("poll was already answered")
+)
+
+// pollActivities are the activities that allow answering each poll
+var pollActivities = map[PollKind]Activity{
+	PollMood: ActivityMoodCheck,
+	PollROTI: ActivityROTI,
+}
+
+// ValidatePollKind checks that a kind is one of the known polls
+func ValidatePollKind(kind PollKind) error {
+	if _, ok := pollActivities[kind]; !ok {
+		return ErrInvalidPollKind
+	}
+	return nil
+}
+
+// Activity returns the activity that allows answering the poll
+func (k PollKind) Activity() Activity {
+	return pollActivities[k]
+}
+
+// Poll is a room's anonymous poll. Answers are only kept as counts per
+// score, apart from who answered, so nobody's score can be told.
+type Poll struct {
+	Kind PollKind `json:"kind"`
+	// Histogram[i] counts the answers with score i+1
+	Histogram     [MaxPollScore]int `json:"histogram"`
+	RespondentIDs []string          `json:"respondent_ids"`
+	ClosedAt      *time.Time        `json:"closed_at,omitempty"`
+}
+
+// Clone returns a deep copy of the poll
+func (p *Poll) Clone() *Poll {
+	clone := *p
+	clone.RespondentIDs = append([]string{}, p.RespondentIDs...)
+	if p.ClosedAt != nil {
+		closedAt := *p.ClosedAt
+		clone.ClosedAt = &closedAt
+	}
+	return &clone
+}
+
+// Answered reports whether the user answered the poll
+func (p *Poll) Answered(userID string) bool {
+	for _, id := range p.RespondentIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// Average returns the mean score, or 0 without answers
+func (p *Poll) Average() float64 {
+	sum, count := 0, 0
+	for i, n := range p.Histogram {
+		sum += (i + MinPollScore) * n
+		count += n
+	}
+	if count == 0 {
+		return 0
+	}
+	return float64(sum) / float64(count)
+}
+
+// PollView is a poll as participants see it: how many answered and whether
+// the viewer did, with the results only once the poll is closed
+type PollView struct {
+	Kind      PollKind           `json:"kind"`
+	Closed    bool               `json:"closed"`
+	Responses int                `json:"responses"`
+	Answered  bool               `json:"answered"`
+	Histogram *[MaxPollScore]int `json:"histogram,omitempty"`
+	Average   float64            `json:"average,omitempty"`
+}
+
+// View returns the poll as the viewer is allowed to see it
+func (p *Poll) View(viewerID string) PollView {
+	view := PollView{
+		Kind:      p.Kind,
+		Closed:    p.ClosedAt != nil,
+		Responses: len(p.RespondentIDs),
+		Answered:  p.Answered(viewerID),
+	}
+	if view.Closed {
+		histogram := p.Histogram
+		view.Histogram = &histogram
+		view.Average = p.Average()
+	}
+	return view
+}
+
+// poll returns the room's poll of the kind, creating it on first use. The
+// caller must hold the room's write lock.
+func (r *Room) poll(kind PollKind) *Poll {
+	if r.Polls == nil {
+		r.Polls = make(map[PollKind]*Poll)
+	}
+	p, ok := r.Polls[kind]
+	if !ok {
+		p = &Poll{Kind: kind, RespondentIDs: []string{}}
+		r.Polls[kind] = p
+	}
+	return p
+}
+
+// AnswerPoll records a user's score in a poll. Each user can answer once,
+// until the poll is closed.
+func (r *Room) AnswerPoll(kind PollKind, userID string, score int) error {
+	if err := ValidatePollKind(kind); err != nil {
+		return err
+	}
+	if score < MinPollScore || score > MaxPollScore {
+		return ErrInvalidPollScore
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p := r.poll(kind)
+	if p.ClosedAt != nil {
+		return ErrPollClosed
+	}
+	if p.Answered(userID) {
+		return ErrAlreadyAnswered
+	}
+	p.RespondentIDs = append(p.RespondentIDs, userID)
+	p.Histogram[score-MinPollScore]++
+	return nil
+}
+
+// ClosePoll stops a poll from taking answers and reveals its results. It
+// returns false if the poll was already closed.
+func (r *Room) ClosePoll(kind PollKind, now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p := r.poll(kind)
+	if p.ClosedAt != nil {
+		return false
+	}
+	p.ClosedAt = &now
+	return true
+}
+
+// GetPoll returns a copy of the room's poll of the kind
+func (r *Room) GetPoll(kind PollKind) *Poll {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if p, ok := r.Polls[kind]; ok {
+		return p.Clone()
+	}
+	return &Poll{Kind: kind, RespondentIDs: []string{}}
+}
+
+// PollViews returns every poll of the room as the viewer is allowed to see
+// it. The caller must hold the room's read lock.
+func (r *Room) PollViews(viewerID string) []PollView {
+	views := make([]PollView, 0, len(PollKinds))
+	for _, kind := range PollKinds {
+		p, ok := r.Polls[kind]
+		if !ok {
+			p = &Poll{Kind: kind}
+		}
+		views = append(views, p.View(viewerID))
+	}
+	return views
+}
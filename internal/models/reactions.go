@@ -0,0 +1,96 @@
+package models
+
+import (
+	"errors"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxReactionRunes caps how long a reaction can be. Emoji built from several
+// code points (skin tones, flags, ZWJ sequences) need more than one.
+const maxReactionRunes = 10
+
+// ErrInvalidReaction is returned for reactions that are not a short emoji
+var ErrInvalidReaction = errors.New("reaction must be a single emoji")
+
+// ValidateReaction checks that a reaction is a short run of symbols without
+// letters, digits or whitespace
+func ValidateReaction(emoji string) error {
+	if emoji == "" || !utf8.ValidString(emoji) || utf8.RuneCountInString(emoji) > maxReactionRunes {
+		return ErrInvalidReaction
+	}
+	for _, r := range emoji {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsSpace(r) || unicode.IsControl(r) {
+			return ErrInvalidReaction
+		}
+	}
+	return nil
+}
+
+// ReactionCounts returns how many users reacted to the ticket with each emoji
+func (t *Ticket) ReactionCounts() map[string]int {
+	counts := make(map[string]int, len(t.Reactions))
+	for emoji, userIDs := range t.Reactions {
+		counts[emoji] = len(userIDs)
+	}
+	return counts
+}
+
+// ReactionCounts returns the reaction counts of the given tickets by ticket ID,
+// leaving out tickets nobody reacted to
+func ReactionCounts(tickets map[string]*Ticket) map[string]map[string]int {
+	counts := make(map[string]map[string]int)
+	for id, ticket := range tickets {
+		if len(ticket.Reactions) > 0 {
+			counts[id] = ticket.ReactionCounts()
+		}
+	}
+	return counts
+}
+
+// AddReaction records a user's reaction to a ticket. Reactions don't use the
+// vote budget; a user can react with several emoji but with each one once.
+// It returns false if the ticket doesn't exist or the user already reacted
+// with the emoji.
+func (r *Room) AddReaction(ticketID, userID, emoji string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ticket, ok := r.Tickets[ticketID]
+	if !ok {
+		return false
+	}
+	for _, id := range ticket.Reactions[emoji] {
+		if id == userID {
+			return false
+		}
+	}
+	if ticket.Reactions == nil {
+		ticket.Reactions = make(map[string][]string)
+	}
+	ticket.Reactions[emoji] = append(ticket.Reactions[emoji], userID)
+	return true
+}
+
+// RemoveReaction takes back a user's reaction to a ticket. It returns false
+// if the user hadn't reacted with the emoji.
+func (r *Room) RemoveReaction(ticketID, userID, emoji string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ticket, ok := r.Tickets[ticketID]
+	if !ok {
+		return false
+	}
+	userIDs := ticket.Reactions[emoji]
+	for i, id := range userIDs {
+		if id == userID {
+			userIDs = append(userIDs[:i:i], userIDs[i+1:]...)
+			if len(userIDs) == 0 {
+				delete(ticket.Reactions, emoji)
+			} else {
+				ticket.Reactions[emoji] = userIDs
+			}
+			return true
+		}
+	}
+	return false
+}
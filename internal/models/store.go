@@ -3,17 +3,154 @@ package models
 import (
 	"database/sql"
 	"encoding/json"
+	"strings"
+	"time"
 
-	_ "embed"
-
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
+// Store is the persistence interface for rooms
+type Store interface {
+	// Create adds a new room to the store
+	Create(room *Room) error
+	// Get retrieves a room by ID
+	Get(id string) (*Room, bool)
+	// Update replaces the stored state of a room
+	Update(room *Room) error
+	// Delete removes a room from the store
+	Delete(id string) error
+	// List returns all rooms
+	List() []*Room
+	// ListByOwner returns all rooms owned by a user
+	ListByOwner(ownerID string) []*Room
+	// ListByParticipant returns all rooms where user is an approved participant
+	ListByParticipant(userID string) []*Room
+	// ListByTeam returns all rooms belonging to a team
+	ListByTeam(teamID string) []*Room
+
+	// The granular operations below persist a single change that has already
+	// been applied to room, touching only the rows affected by it.
+
+	// UpdateRoomSettings persists the room's own fields (name, phase, settings)
+	UpdateRoomSettings(room *Room) error
+	// SetColumns replaces the room's board columns
+	SetColumns(room *Room) error
+	// ChangePhase persists a phase change made with Room.SetPhase along with
+	// the room's own fields, closing the previous phase history entry and
+	// adding the new one
+	ChangePhase(room *Room) error
+	// UpsertParticipant inserts or updates a participant, approved or pending
+	UpsertParticipant(room *Room, participant *Participant) error
+	// RemoveParticipant removes a participant, approved or pending
+	RemoveParticipant(room *Room, userID string) error
+	// AddTicket inserts a new ticket
+	AddTicket(room *Room, ticket *Ticket) error
+	// UpdateTicket persists a ticket's content, column, merge parent and covered flag
+	UpdateTicket(room *Room, ticket *Ticket) error
+	// DeleteTicket removes a ticket
+	DeleteTicket(room *Room, ticketID string) error
+	// AddVote records a user's vote on a ticket
+	AddVote(room *Room, ticketID, userID string) error
+	// RemoveVote removes a user's vote from a ticket
+	RemoveVote(room *Room, ticketID, userID string) error
+	// AddReaction records a user's emoji reaction to a ticket
+	AddReaction(room *Room, ticketID, userID, emoji string) error
+	// RemoveReaction removes a user's emoji reaction from a ticket
+	RemoveReaction(room *Room, ticketID, userID, emoji string) error
+	// AddComment inserts a new comment on a ticket
+	AddComment(room *Room, comment *Comment) error
+	// UpdateComment persists a comment's content and edit time
+	UpdateComment(room *Room, comment *Comment) error
+	// DeleteComment removes a comment
+	DeleteComment(room *Room, ticketID, commentID string) error
+	// AddActionTicket inserts a new action item
+	AddActionTicket(room *Room, action *ActionTicket) error
+	// UpdateActionTicket persists an action item's content, assignees,
+	// status, owner, due date, status history and review outcome
+	UpdateActionTicket(room *Room, action *ActionTicket) error
+	// DeleteActionTicket removes an action item
+	DeleteActionTicket(room *Room, actionID string) error
+	// MarkCarriedOver persists the given action items as carried over to
+	// another room
+	MarkCarriedOver(room *Room, actionIDs []string, toRoomID string) error
+	// AnswerPoll records that a user answered a poll and, apart from who
+	// answered, counts the score
+	AnswerPoll(room *Room, kind PollKind, userID string, score int) error
+	// ClosePoll persists a poll being closed
+	ClosePoll(room *Room, kind PollKind) error
+	// RateHealth records or replaces a user's rating of a health dimension
+	RateHealth(room *Room, userID, dimensionID string, rating HealthRating) error
+	// Undo persists the changes of an undo command along with the event
+	// recording it, assigning the event its ID
+	Undo(room *Room, undo *Undo) error
+
+	// ListVotes returns all votes cast in a room, oldest first
+	ListVotes(roomID string) ([]*Vote, error)
+
+	// Analytics aggregates the rooms selected by the filter
+	Analytics(filter AnalyticsFilter) (*Analytics, error)
+	// HealthHistory returns the results of the health check rooms selected
+	// by the filter, oldest first
+	HealthHistory(filter AnalyticsFilter) (*HealthHistory, error)
+
+	// The event log is append-only and does not change the room's version.
+
+	// AppendEvent records an event in the room's log and assigns its ID
+	AppendEvent(event *RoomEvent) error
+	// ListEvents returns up to limit events of a room with IDs greater than afterID, oldest first
+	ListEvents(roomID string, afterID int64, limit int) ([]*RoomEvent, error)
+	// ListRecentEvents returns up to limit events of a room of the given types
+	// with IDs less than beforeID, newest first. A beforeID of 0 starts from the
+	// newest event.
+	ListRecentEvents(roomID string, types []EventType, beforeID int64, limit int) ([]*RoomEvent, error)
+
+	// Custom templates are not tied to a room. Built-in templates are not stored.
+
+	// CreateTemplate adds a custom template
+	CreateTemplate(template *Template) error
+	// GetTemplate retrieves a custom template by ID
+	GetTemplate(id string) (*Template, bool)
+	// ListTemplates returns the custom templates created by the user or
+	// shared with one of the teams, oldest first
+	ListTemplates(userID string, teamIDs []string) ([]*Template, error)
+	// UpdateTemplate replaces a custom template
+	UpdateTemplate(template *Template) error
+	// DeleteTemplate removes a custom template
+	DeleteTemplate(id string) error
+
+	// Teams are not tied to a room; rooms point to their team with TeamID.
+
+	// CreateTeam adds a team along with its members
+	CreateTeam(team *Team) error
+	// GetTeam retrieves a team and its members by ID
+	GetTeam(id string) (*Team, bool)
+	// ListTeamsByMember returns all teams the user is a member of, oldest first
+	ListTeamsByMember(userID string) ([]*Team, error)
+	// DeleteTeam removes a team; its rooms and templates stay but no longer belong to a team
+	DeleteTeam(id string) error
+	// UpsertTeamMember inserts or updates a team member
+	UpsertTeamMember(teamID string, member *TeamMember) error
+	// RemoveTeamMember removes a member from a team
+	RemoveTeamMember(teamID, userID string) error
+}
+
+// execer is implemented by both *sql.DB and *sql.Tx
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// rowQuerier is implemented by both *sql.DB and *sql.Tx
+type rowQuerier interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
 // RoomStore is a PostgreSQL store for rooms
 type RoomStore struct {
 	db *sql.DB
 }
 
+var _ Store = (*RoomStore)(nil)
+
 // NewRoomStore creates a new room store
 func NewRoomStore(db *sql.DB) *RoomStore {
 	return &RoomStore{
@@ -21,12 +158,9 @@ func NewRoomStore(db *sql.DB) *RoomStore {
 	}
 }
 
-//go:embed schema.sql
-var schema string
-
-// InitSchema initializes the database schema
+// InitSchema brings the database schema up to date by applying pending migrations
 func (s *RoomStore) InitSchema() error {
-	_, err := s.db.Exec(schema)
+	_, err := s.MigrateUp()
 	return err
 }
 
@@ -38,64 +172,135 @@ func (s *RoomStore) Create(room *Room) error {
 	}
 	defer tx.Rollback()
 
+	phases, err := json.Marshal(room.Phases)
+	if err != nil {
+		return err
+	}
+	timer, err := encodeTimer(room.Timer)
+	if err != nil {
+		return err
+	}
+	dimensions, err := encodeHealthDimensions(room.HealthDimensions)
+	if err != nil {
+		return err
+	}
+
 	// Insert room
 	_, err = tx.Exec(`
-		INSERT INTO rooms (id, name, owner_id, phase, votes_per_user, auto_approve, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-	`, room.ID, room.Name, room.OwnerID, room.Phase, room.VotesPerUser, room.AutoApprove, room.CreatedAt)
+		INSERT INTO rooms (id, name, owner_id, phase, phases, timer, votes_per_user, max_votes_per_ticket, hidden_votes, votes_revealed, anonymous_tickets, blind_tickets, tickets_revealed, auto_approve, previous_room_id, team_id, health_dimensions, created_at, version)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+	`, room.ID, room.Name, room.OwnerID, room.Phase, string(phases), timer, room.VotesPerUser, room.MaxVotesPerTicket, room.HiddenVotes, room.VotesRevealed, room.AnonymousTickets, room.BlindTickets, room.TicketsRevealed, room.AutoApprove, room.PreviousRoomID, room.TeamID, dimensions, room.CreatedAt, room.Version)
 	if err != nil {
 		return err
 	}
 
-	// Insert participants
 	room.RLock()
+	defer room.RUnlock()
+
+	// Insert columns
+	if err := insertColumns(tx, room); err != nil {
+		return err
+	}
+
+	// Insert phase history
+	for _, entry := range room.PhaseHistory {
+		if err := insertPhaseHistoryEntry(tx, room.ID, entry); err != nil {
+			return err
+		}
+	}
+
+	// Insert participants
 	for _, participant := range room.Participants {
-		_, err = tx.Exec(`
-			INSERT INTO participants (room_id, user_id, user_email, user_name, role, status, votes_used)
-			VALUES ($1, $2, $3, $4, $5, $6, $7)
-		`, room.ID, participant.User.ID, participant.User.Email, participant.User.Name, participant.Role, participant.Status, participant.VotesUsed)
-		if err != nil {
-			room.RUnlock()
+		if err := insertParticipant(tx, room.ID, participant); err != nil {
 			return err
 		}
 	}
 	// Insert pending participants
 	for _, participant := range room.PendingParticipants {
-		_, err = tx.Exec(`
-			INSERT INTO participants (room_id, user_id, user_email, user_name, role, status, votes_used)
-			VALUES ($1, $2, $3, $4, $5, $6, $7)
-		`, room.ID, participant.User.ID, participant.User.Email, participant.User.Name, participant.Role, participant.Status, participant.VotesUsed)
-		if err != nil {
-			room.RUnlock()
+		if err := insertParticipant(tx, room.ID, participant); err != nil {
 			return err
 		}
 	}
-	room.RUnlock()
+
+	// Insert tickets and action tickets, such as actions carried over from
+	// the previous room
+	for _, ticket := range room.Tickets {
+		if err := insertTicket(tx, room.ID, ticket); err != nil {
+			return err
+		}
+	}
+	for _, action := range room.ActionTickets {
+		if err := insertActionTicket(tx, room.ID, action); err != nil {
+			return err
+		}
+	}
+
+	// Insert polls and health ratings
+	for _, poll := range room.Polls {
+		if err := insertPoll(tx, room.ID, poll); err != nil {
+			return err
+		}
+	}
+	for userID, ratings := range room.HealthRatings {
+		for dimensionID, rating := range ratings {
+			if err := upsertHealthRating(tx, room.ID, userID, dimensionID, rating); err != nil {
+				return err
+			}
+		}
+	}
 
 	return tx.Commit()
 }
 
 // Get retrieves a room by ID
 func (s *RoomStore) Get(id string) (*Room, bool) {
-	room := &Room{
-		Participants:        make(map[string]*Participant),
-		PendingParticipants: make(map[string]*Participant),
-		Tickets:             make(map[string]*Ticket),
-		ActionTickets:       make(map[string]*ActionTicket),
+	// Get room data
+	room, err := scanRoom(s.db.QueryRow(`SELECT `+roomColumns+` FROM rooms WHERE id = $1`, id))
+	if err != nil {
+		return nil, false
 	}
 
-	// Get room data
-	err := s.db.QueryRow(`
-		SELECT id, name, owner_id, phase, votes_per_user, auto_approve, created_at
-		FROM rooms WHERE id = $1
-	`, id).Scan(&room.ID, &room.Name, &room.OwnerID, &room.Phase, &room.VotesPerUser, &room.AutoApprove, &room.CreatedAt)
+	// Get columns
+	columnRows, err := s.db.Query(`
+		SELECT id, title, color, prompt FROM room_columns WHERE room_id = $1 ORDER BY position
+	`, id)
+	if err != nil {
+		return nil, false
+	}
+	defer columnRows.Close()
+
+	for columnRows.Next() {
+		var column Column
+		if err := columnRows.Scan(&column.ID, &column.Title, &column.Color, &column.Prompt); err != nil {
+			return nil, false
+		}
+		room.Columns = append(room.Columns, column)
+	}
+
+	// Get phase history
+	historyRows, err := s.db.Query(`
+		SELECT phase, entered_at, exited_at FROM phase_history WHERE room_id = $1 ORDER BY entered_at, id
+	`, id)
 	if err != nil {
 		return nil, false
 	}
+	defer historyRows.Close()
+
+	for historyRows.Next() {
+		var entry PhaseHistoryEntry
+		var exitedAt sql.NullTime
+		if err := historyRows.Scan(&entry.Phase, &entry.EnteredAt, &exitedAt); err != nil {
+			return nil, false
+		}
+		if exitedAt.Valid {
+			entry.ExitedAt = &exitedAt.Time
+		}
+		room.PhaseHistory = append(room.PhaseHistory, entry)
+	}
 
 	// Get participants
 	rows, err := s.db.Query(`
-		SELECT user_id, user_email, user_name, role, status, votes_used
+		SELECT user_id, user_email, user_name, role, status
 		FROM participants WHERE room_id = $1
 	`, id)
 	if err != nil {
@@ -105,7 +310,7 @@ func (s *RoomStore) Get(id string) (*Room, bool) {
 
 	for rows.Next() {
 		var p Participant
-		err := rows.Scan(&p.User.ID, &p.User.Email, &p.User.Name, &p.Role, &p.Status, &p.VotesUsed)
+		err := rows.Scan(&p.User.ID, &p.User.Email, &p.User.Name, &p.Role, &p.Status)
 		if err != nil {
 			return nil, false
 		}
@@ -118,7 +323,7 @@ func (s *RoomStore) Get(id string) (*Room, bool) {
 
 	// Get tickets
 	ticketRows, err := s.db.Query(`
-		SELECT id, content, author_id, deduplication_ticket_id, votes, voter_ids, covered, created_at
+		SELECT id, content, author_id, column_id, deduplication_ticket_id, covered, created_at
 		FROM tickets WHERE room_id = $1
 	`, id)
 	if err != nil {
@@ -127,43 +332,159 @@ func (s *RoomStore) Get(id string) (*Room, bool) {
 	defer ticketRows.Close()
 
 	for ticketRows.Next() {
-		var t Ticket
+		t := Ticket{VoterIDs: []string{}}
 		var deduplicationTicketID sql.NullString
-		var voterIDsJSON []byte
-		err := ticketRows.Scan(&t.ID, &t.Content, &t.AuthorID, &deduplicationTicketID, &t.Votes, &voterIDsJSON, &t.Covered, &t.CreatedAt)
+		err := ticketRows.Scan(&t.ID, &t.Content, &t.AuthorID, &t.ColumnID, &deduplicationTicketID, &t.Covered, &t.CreatedAt)
 		if err != nil {
 			return nil, false
 		}
 		if deduplicationTicketID.Valid {
 			t.DeduplicationTicketID = &deduplicationTicketID.String
 		}
-		if err := json.Unmarshal(voterIDsJSON, &t.VoterIDs); err != nil {
+		room.Tickets[t.ID] = &t
+	}
+
+	// Derive vote counts and votes used from the votes table
+	votes, err := s.ListVotes(id)
+	if err != nil {
+		return nil, false
+	}
+	for _, vote := range votes {
+		room.applyVote(vote)
+	}
+
+	// Get reactions
+	reactionRows, err := s.db.Query(`
+		SELECT ticket_id, user_id, emoji FROM reactions WHERE room_id = $1 ORDER BY created_at, user_id
+	`, id)
+	if err != nil {
+		return nil, false
+	}
+	defer reactionRows.Close()
+
+	for reactionRows.Next() {
+		var ticketID, userID, emoji string
+		if err := reactionRows.Scan(&ticketID, &userID, &emoji); err != nil {
+			return nil, false
+		}
+		if ticket, ok := room.Tickets[ticketID]; ok {
+			if ticket.Reactions == nil {
+				ticket.Reactions = make(map[string][]string)
+			}
+			ticket.Reactions[emoji] = append(ticket.Reactions[emoji], userID)
+		}
+	}
+
+	// Get comments
+	commentRows, err := s.db.Query(`
+		SELECT id, ticket_id, author_id, content, created_at, updated_at
+		FROM comments WHERE room_id = $1 ORDER BY created_at, id
+	`, id)
+	if err != nil {
+		return nil, false
+	}
+	defer commentRows.Close()
+
+	for commentRows.Next() {
+		var c Comment
+		var updatedAt sql.NullTime
+		if err := commentRows.Scan(&c.ID, &c.TicketID, &c.AuthorID, &c.Content, &c.CreatedAt, &updatedAt); err != nil {
 			return nil, false
 		}
-		room.Tickets[t.ID] = &t
+		if updatedAt.Valid {
+			c.UpdatedAt = &updatedAt.Time
+		}
+		if ticket, ok := room.Tickets[c.TicketID]; ok {
+			ticket.Comments = append(ticket.Comments, &c)
+		}
 	}
 
 	// Get action tickets
-	actionRows, err := s.db.Query(`
-		SELECT id, content, assignee_ids, ticket_id, created_at
-		FROM action_tickets WHERE room_id = $1
-	`, id)
+	actionRows, err := s.db.Query(`SELECT `+actionColumns+` FROM action_tickets WHERE room_id = $1`, id)
 	if err != nil {
 		return nil, false
 	}
 	defer actionRows.Close()
 
 	for actionRows.Next() {
-		var at ActionTicket
-		var assigneeIDsJSON []byte
-		err := actionRows.Scan(&at.ID, &at.Content, &assigneeIDsJSON, &at.TicketID, &at.CreatedAt)
+		at, err := scanActionTicket(actionRows)
 		if err != nil {
 			return nil, false
 		}
-		if err := json.Unmarshal(assigneeIDsJSON, &at.AssigneeIDs); err != nil {
+		room.ActionTickets[at.ID] = at
+	}
+
+	// Get polls
+	pollRows, err := s.db.Query(`SELECT kind, closed_at FROM polls WHERE room_id = $1`, id)
+	if err != nil {
+		return nil, false
+	}
+	defer pollRows.Close()
+
+	for pollRows.Next() {
+		var kind PollKind
+		var closedAt sql.NullTime
+		if err := pollRows.Scan(&kind, &closedAt); err != nil {
+			return nil, false
+		}
+		if closedAt.Valid {
+			room.poll(kind).ClosedAt = &closedAt.Time
+		}
+	}
+
+	scoreRows, err := s.db.Query(`SELECT kind, score, count FROM poll_scores WHERE room_id = $1`, id)
+	if err != nil {
+		return nil, false
+	}
+	defer scoreRows.Close()
+
+	for scoreRows.Next() {
+		var kind PollKind
+		var score, count int
+		if err := scoreRows.Scan(&kind, &score, &count); err != nil {
+			return nil, false
+		}
+		if score >= MinPollScore && score <= MaxPollScore {
+			room.poll(kind).Histogram[score-MinPollScore] = count
+		}
+	}
+
+	respondentRows, err := s.db.Query(`SELECT kind, user_id FROM poll_respondents WHERE room_id = $1 ORDER BY user_id`, id)
+	if err != nil {
+		return nil, false
+	}
+	defer respondentRows.Close()
+
+	for respondentRows.Next() {
+		var kind PollKind
+		var userID string
+		if err := respondentRows.Scan(&kind, &userID); err != nil {
+			return nil, false
+		}
+		p := room.poll(kind)
+		p.RespondentIDs = append(p.RespondentIDs, userID)
+	}
+
+	// Get health ratings
+	ratingRows, err := s.db.Query(`SELECT user_id, dimension_id, color, trend FROM health_ratings WHERE room_id = $1`, id)
+	if err != nil {
+		return nil, false
+	}
+	defer ratingRows.Close()
+
+	for ratingRows.Next() {
+		var userID, dimensionID string
+		var rating HealthRating
+		if err := ratingRows.Scan(&userID, &dimensionID, &rating.Color, &rating.Trend); err != nil {
 			return nil, false
 		}
-		room.ActionTickets[at.ID] = &at
+		if room.HealthRatings == nil {
+			room.HealthRatings = make(map[string]map[string]HealthRating)
+		}
+		if room.HealthRatings[userID] == nil {
+			room.HealthRatings[userID] = make(map[string]HealthRating)
+		}
+		room.HealthRatings[userID][dimensionID] = rating
 	}
 
 	return room, true
@@ -177,68 +498,30 @@ func (s *RoomStore) Delete(id string) error {
 
 // List returns all rooms
 func (s *RoomStore) List() []*Room {
-	rows, err := s.db.Query(`
-		SELECT id, name, owner_id, phase, votes_per_user, auto_approve, created_at
-		FROM rooms
-	`)
-	if err != nil {
-		return []*Room{}
-	}
-	defer rows.Close()
-
-	rooms := make([]*Room, 0)
-	for rows.Next() {
-		var room Room
-		err := rows.Scan(&room.ID, &room.Name, &room.OwnerID, &room.Phase, &room.VotesPerUser, &room.AutoApprove, &room.CreatedAt)
-		if err != nil {
-			continue
-		}
-		// Initialize maps
-		room.Participants = make(map[string]*Participant)
-		room.PendingParticipants = make(map[string]*Participant)
-		room.Tickets = make(map[string]*Ticket)
-		room.ActionTickets = make(map[string]*ActionTicket)
-		rooms = append(rooms, &room)
-	}
-	return rooms
+	return s.listRooms(`SELECT ` + roomColumns + ` FROM rooms`)
 }
 
 // ListByOwner returns all rooms owned by a user
 func (s *RoomStore) ListByOwner(ownerID string) []*Room {
-	rows, err := s.db.Query(`
-		SELECT id, name, owner_id, phase, votes_per_user, auto_approve, created_at
-		FROM rooms WHERE owner_id = $1
-	`, ownerID)
-	if err != nil {
-		return []*Room{}
-	}
-	defer rows.Close()
-
-	rooms := make([]*Room, 0)
-	for rows.Next() {
-		var room Room
-		err := rows.Scan(&room.ID, &room.Name, &room.OwnerID, &room.Phase, &room.VotesPerUser, &room.AutoApprove, &room.CreatedAt)
-		if err != nil {
-			continue
-		}
-		// Initialize maps
-		room.Participants = make(map[string]*Participant)
-		room.PendingParticipants = make(map[string]*Participant)
-		room.Tickets = make(map[string]*Ticket)
-		room.ActionTickets = make(map[string]*ActionTicket)
-		rooms = append(rooms, &room)
-	}
-	return rooms
+	return s.listRooms(`SELECT `+roomColumns+` FROM rooms WHERE owner_id = $1`, ownerID)
 }
 
 // ListByParticipant returns all rooms where user is a participant
 func (s *RoomStore) ListByParticipant(userID string) []*Room {
-	rows, err := s.db.Query(`
-		SELECT DISTINCT r.id, r.name, r.owner_id, r.phase, r.votes_per_user, r.auto_approve, r.created_at
-		FROM rooms r
-		INNER JOIN participants p ON r.id = p.room_id
-		WHERE p.user_id = $1 AND p.status = 'approved'
+	return s.listRooms(`
+		SELECT `+roomColumns+` FROM rooms
+		WHERE id IN (SELECT room_id FROM participants WHERE user_id = $1 AND status = 'approved')
 	`, userID)
+}
+
+// ListByTeam returns all rooms belonging to a team
+func (s *RoomStore) ListByTeam(teamID string) []*Room {
+	return s.listRooms(`SELECT `+roomColumns+` FROM rooms WHERE team_id = $1 ORDER BY created_at`, teamID)
+}
+
+// listRooms returns the rooms selected by query without their participants, tickets or actions
+func (s *RoomStore) listRooms(query string, args ...any) []*Room {
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		return []*Room{}
 	}
@@ -246,111 +529,1288 @@ func (s *RoomStore) ListByParticipant(userID string) []*Room {
 
 	rooms := make([]*Room, 0)
 	for rows.Next() {
-		var room Room
-		err := rows.Scan(&room.ID, &room.Name, &room.OwnerID, &room.Phase, &room.VotesPerUser, &room.AutoApprove, &room.CreatedAt)
+		room, err := scanRoom(rows)
 		if err != nil {
 			continue
 		}
-		// Initialize maps
-		room.Participants = make(map[string]*Participant)
-		room.PendingParticipants = make(map[string]*Participant)
-		room.Tickets = make(map[string]*Ticket)
-		room.ActionTickets = make(map[string]*ActionTicket)
-		rooms = append(rooms, &room)
+		rooms = append(rooms, room)
 	}
 	return rooms
 }
 
-// Update updates a room in the database
-func (s *RoomStore) Update(room *Room) error {
-	tx, err := s.db.Begin()
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
+// roomColumns are the rooms table columns read by scanRoom, in order
+const roomColumns = `id, name, owner_id, phase, phases, timer, votes_per_user, max_votes_per_ticket, hidden_votes, votes_revealed, anonymous_tickets, blind_tickets, tickets_revealed, auto_approve, previous_room_id, team_id, health_dimensions, created_at, version`
 
-	// Update room
-	_, err = tx.Exec(`
-		UPDATE rooms SET name = $1, owner_id = $2, phase = $3, votes_per_user = $4, auto_approve = $5
-		WHERE id = $6
-	`, room.Name, room.OwnerID, room.Phase, room.VotesPerUser, room.AutoApprove, room.ID)
-	if err != nil {
-		return err
-	}
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
 
-	// Delete existing participants, tickets, and actions
-	_, err = tx.Exec(`DELETE FROM participants WHERE room_id = $1`, room.ID)
-	if err != nil {
-		return err
+// scanRoom reads a rooms row selected with roomColumns into a room with empty collections
+func scanRoom(row rowScanner) (*Room, error) {
+	room := &Room{
+		Participants:        make(map[string]*Participant),
+		PendingParticipants: make(map[string]*Participant),
+		Tickets:             make(map[string]*Ticket),
+		ActionTickets:       make(map[string]*ActionTicket),
+		Columns:             []Column{},
+		PhaseHistory:        []PhaseHistoryEntry{},
 	}
-	_, err = tx.Exec(`DELETE FROM tickets WHERE room_id = $1`, room.ID)
+	var phases, timer, dimensions []byte
+	err := row.Scan(&room.ID, &room.Name, &room.OwnerID, &room.Phase, &phases, &timer, &room.VotesPerUser, &room.MaxVotesPerTicket, &room.HiddenVotes, &room.VotesRevealed, &room.AnonymousTickets, &room.BlindTickets, &room.TicketsRevealed, &room.AutoApprove, &room.PreviousRoomID, &room.TeamID, &dimensions, &room.CreatedAt, &room.Version)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	_, err = tx.Exec(`DELETE FROM action_tickets WHERE room_id = $1`, room.ID)
-	if err != nil {
-		return err
+	if err := json.Unmarshal(phases, &room.Phases); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(dimensions, &room.HealthDimensions); err != nil {
+		return nil, err
+	}
+	if len(room.HealthDimensions) == 0 {
+		room.HealthDimensions = nil
 	}
+	if timer != nil {
+		room.Timer = &Timer{}
+		if err := json.Unmarshal(timer, room.Timer); err != nil {
+			return nil, err
+		}
+	}
+	return room, nil
+}
 
-	// Insert participants
-	room.RLock()
-	for _, participant := range room.Participants {
-		_, err = tx.Exec(`
-			INSERT INTO participants (room_id, user_id, user_email, user_name, role, status, votes_used)
-			VALUES ($1, $2, $3, $4, $5, $6, $7)
-		`, room.ID, participant.User.ID, participant.User.Email, participant.User.Name, participant.Role, participant.Status, participant.VotesUsed)
-		if err != nil {
-			room.RUnlock()
+// Update replaces the stored state of a room
+func (s *RoomStore) Update(room *Room) error {
+	return s.withVersion(room, func(tx *sql.Tx) error {
+		room.RLock()
+		defer room.RUnlock()
+
+		// Update room
+		if err := updateRoomRow(tx, room); err != nil {
 			return err
 		}
-	}
 
-	// Insert pending participants
-	for _, participant := range room.PendingParticipants {
-		_, err = tx.Exec(`
-			INSERT INTO participants (room_id, user_id, user_email, user_name, role, status, votes_used)
-			VALUES ($1, $2, $3, $4, $5, $6, $7)
-		`, room.ID, participant.User.ID, participant.User.Email, participant.User.Name, participant.Role, participant.Status, participant.VotesUsed)
+		// Delete existing columns, phase history, participants, tickets, and actions
+		_, err := tx.Exec(`DELETE FROM room_columns WHERE room_id = $1`, room.ID)
 		if err != nil {
-			room.RUnlock()
 			return err
 		}
-	}
-
-	// Insert tickets
-	for _, ticket := range room.Tickets {
-		voterIDsJSON, err := json.Marshal(ticket.VoterIDs)
+		_, err = tx.Exec(`DELETE FROM phase_history WHERE room_id = $1`, room.ID)
 		if err != nil {
-			room.RUnlock()
 			return err
 		}
-		_, err = tx.Exec(`
-			INSERT INTO tickets (id, room_id, content, author_id, deduplication_ticket_id, votes, voter_ids, covered, created_at)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-		`, ticket.ID, room.ID, ticket.Content, ticket.AuthorID, ticket.DeduplicationTicketID, ticket.Votes, voterIDsJSON, ticket.Covered, ticket.CreatedAt)
+		_, err = tx.Exec(`DELETE FROM participants WHERE room_id = $1`, room.ID)
 		if err != nil {
-			room.RUnlock()
 			return err
 		}
-	}
-
-	// Insert action tickets
-	for _, action := range room.ActionTickets {
-		assigneeIDsJSON, err := json.Marshal(action.AssigneeIDs)
+		_, err = tx.Exec(`DELETE FROM tickets WHERE room_id = $1`, room.ID)
 		if err != nil {
-			room.RUnlock()
 			return err
 		}
-		_, err = tx.Exec(`
-			INSERT INTO action_tickets (id, room_id, content, assignee_ids, ticket_id, created_at)
-			VALUES ($1, $2, $3, $4, $5, $6)
-		`, action.ID, room.ID, action.Content, assigneeIDsJSON, action.TicketID, action.CreatedAt)
+		_, err = tx.Exec(`DELETE FROM action_tickets WHERE room_id = $1`, room.ID)
 		if err != nil {
-			room.RUnlock()
 			return err
 		}
-	}
-	room.RUnlock()
+		for _, table := range []string{"polls", "poll_scores", "poll_respondents", "health_ratings"} {
+			if _, err := tx.Exec(`DELETE FROM `+table+` WHERE room_id = $1`, room.ID); err != nil {
+				return err
+			}
+		}
 
-	return tx.Commit()
+		// Insert columns
+		if err := insertColumns(tx, room); err != nil {
+			return err
+		}
+
+		// Insert phase history
+		for _, entry := range room.PhaseHistory {
+			if err := insertPhaseHistoryEntry(tx, room.ID, entry); err != nil {
+				return err
+			}
+		}
+
+		// Insert participants
+		for _, participant := range room.Participants {
+			if err := insertParticipant(tx, room.ID, participant); err != nil {
+				return err
+			}
+		}
+
+		// Insert pending participants
+		for _, participant := range room.PendingParticipants {
+			if err := insertParticipant(tx, room.ID, participant); err != nil {
+				return err
+			}
+		}
+
+		// Insert tickets
+		for _, ticket := range room.Tickets {
+			if err := insertTicket(tx, room.ID, ticket); err != nil {
+				return err
+			}
+		}
+
+		// Insert action tickets
+		for _, action := range room.ActionTickets {
+			if err := insertActionTicket(tx, room.ID, action); err != nil {
+				return err
+			}
+		}
+
+		// Insert polls
+		for _, poll := range room.Polls {
+			if err := insertPoll(tx, room.ID, poll); err != nil {
+				return err
+			}
+		}
+
+		// Insert health ratings
+		for userID, ratings := range room.HealthRatings {
+			for dimensionID, rating := range ratings {
+				if err := upsertHealthRating(tx, room.ID, userID, dimensionID, rating); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// UpdateRoomSettings persists the room's own fields (name, phase, settings)
+func (s *RoomStore) UpdateRoomSettings(room *Room) error {
+	return s.withVersion(room, func(tx *sql.Tx) error {
+		room.RLock()
+		defer room.RUnlock()
+		return updateRoomRow(tx, room)
+	})
+}
+
+// SetColumns replaces the room's board columns
+func (s *RoomStore) SetColumns(room *Room) error {
+	return s.withVersion(room, func(tx *sql.Tx) error {
+		room.RLock()
+		defer room.RUnlock()
+		if _, err := tx.Exec(`DELETE FROM room_columns WHERE room_id = $1`, room.ID); err != nil {
+			return err
+		}
+		return insertColumns(tx, room)
+	})
+}
+
+// ChangePhase persists a phase change made with Room.SetPhase along with the
+// room's own fields, closing the previous phase history entry and adding the
+// new one
+func (s *RoomStore) ChangePhase(room *Room) error {
+	return s.withVersion(room, func(tx *sql.Tx) error {
+		room.RLock()
+		defer room.RUnlock()
+		return changePhase(tx, room, 1)
+	})
+}
+
+// changePhase writes the room's own fields and the phase history entries of
+// the last changes phase changes, each closing the one before. The caller
+// must hold the room's read lock.
+func changePhase(ex execer, room *Room, changes int) error {
+	if err := updateRoomRow(ex, room); err != nil {
+		return err
+	}
+	for _, entry := range room.PhaseHistory[max(len(room.PhaseHistory)-changes, 0):] {
+		if _, err := ex.Exec(`
+			UPDATE phase_history SET exited_at = $1 WHERE room_id = $2 AND exited_at IS NULL
+		`, entry.EnteredAt, room.ID); err != nil {
+			return err
+		}
+		if err := insertPhaseHistoryEntry(ex, room.ID, entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpsertParticipant inserts or updates a participant, approved or pending
+func (s *RoomStore) UpsertParticipant(room *Room, participant *Participant) error {
+	return s.withVersion(room, func(tx *sql.Tx) error {
+		room.RLock()
+		defer room.RUnlock()
+		_, err := tx.Exec(`
+			INSERT INTO participants (room_id, user_id, user_email, user_name, role, status)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (room_id, user_id) DO UPDATE SET
+				user_email = EXCLUDED.user_email,
+				user_name = EXCLUDED.user_name,
+				role = EXCLUDED.role,
+				status = EXCLUDED.status
+		`, room.ID, participant.User.ID, participant.User.Email, participant.User.Name, participant.Role, participant.Status)
+		return err
+	})
+}
+
+// RemoveParticipant removes a participant, approved or pending
+func (s *RoomStore) RemoveParticipant(room *Room, userID string) error {
+	return s.withVersion(room, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`DELETE FROM participants WHERE room_id = $1 AND user_id = $2`, room.ID, userID)
+		return err
+	})
+}
+
+// AddTicket inserts a new ticket
+func (s *RoomStore) AddTicket(room *Room, ticket *Ticket) error {
+	return s.withVersion(room, func(tx *sql.Tx) error {
+		room.RLock()
+		defer room.RUnlock()
+		return insertTicket(tx, room.ID, ticket)
+	})
+}
+
+// UpdateTicket persists a ticket's content, column, merge parent and covered flag
+func (s *RoomStore) UpdateTicket(room *Room, ticket *Ticket) error {
+	return s.withVersion(room, func(tx *sql.Tx) error {
+		room.RLock()
+		defer room.RUnlock()
+		_, err := tx.Exec(`
+			UPDATE tickets SET content = $1, column_id = $2, deduplication_ticket_id = $3, covered = $4
+			WHERE id = $5 AND room_id = $6
+		`, ticket.Content, ticket.ColumnID, ticket.DeduplicationTicketID, ticket.Covered, ticket.ID, room.ID)
+		return err
+	})
+}
+
+// DeleteTicket removes a ticket
+func (s *RoomStore) DeleteTicket(room *Room, ticketID string) error {
+	return s.withVersion(room, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`DELETE FROM tickets WHERE id = $1 AND room_id = $2`, ticketID, room.ID)
+		return err
+	})
+}
+
+// AddVote records a user's vote on a ticket
+func (s *RoomStore) AddVote(room *Room, ticketID, userID string) error {
+	return s.withVersion(room, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			INSERT INTO votes (room_id, ticket_id, user_id, weight, created_at)
+			VALUES ($1, $2, $3, 1, $4)
+			ON CONFLICT (ticket_id, user_id) DO UPDATE SET weight = votes.weight + 1
+		`, room.ID, ticketID, userID, time.Now())
+		return err
+	})
+}
+
+// RemoveVote removes a user's vote from a ticket
+func (s *RoomStore) RemoveVote(room *Room, ticketID, userID string) error {
+	return s.withVersion(room, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			DELETE FROM votes WHERE ticket_id = $1 AND user_id = $2 AND room_id = $3 AND weight = 1
+		`, ticketID, userID, room.ID)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`
+			UPDATE votes SET weight = weight - 1
+			WHERE ticket_id = $1 AND user_id = $2 AND room_id = $3 AND weight > 1
+		`, ticketID, userID, room.ID)
+		return err
+	})
+}
+
+// AddReaction records a user's emoji reaction to a ticket
+func (s *RoomStore) AddReaction(room *Room, ticketID, userID, emoji string) error {
+	return s.withVersion(room, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			INSERT INTO reactions (room_id, ticket_id, user_id, emoji, created_at)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT DO NOTHING
+		`, room.ID, ticketID, userID, emoji, time.Now())
+		return err
+	})
+}
+
+// RemoveReaction removes a user's emoji reaction from a ticket
+func (s *RoomStore) RemoveReaction(room *Room, ticketID, userID, emoji string) error {
+	return s.withVersion(room, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			DELETE FROM reactions WHERE ticket_id = $1 AND user_id = $2 AND emoji = $3 AND room_id = $4
+		`, ticketID, userID, emoji, room.ID)
+		return err
+	})
+}
+
+// AddComment inserts a new comment on a ticket
+func (s *RoomStore) AddComment(room *Room, comment *Comment) error {
+	return s.withVersion(room, func(tx *sql.Tx) error {
+		return insertComment(tx, room.ID, comment)
+	})
+}
+
+// UpdateComment persists a comment's content and edit time
+func (s *RoomStore) UpdateComment(room *Room, comment *Comment) error {
+	return s.withVersion(room, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			UPDATE comments SET content = $1, updated_at = $2 WHERE id = $3 AND room_id = $4
+		`, comment.Content, comment.UpdatedAt, comment.ID, room.ID)
+		return err
+	})
+}
+
+// DeleteComment removes a comment
+func (s *RoomStore) DeleteComment(room *Room, ticketID, commentID string) error {
+	return s.withVersion(room, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			DELETE FROM comments WHERE id = $1 AND ticket_id = $2 AND room_id = $3
+		`, commentID, ticketID, room.ID)
+		return err
+	})
+}
+
+// AddActionTicket inserts a new action item
+func (s *RoomStore) AddActionTicket(room *Room, action *ActionTicket) error {
+	return s.withVersion(room, func(tx *sql.Tx) error {
+		room.RLock()
+		defer room.RUnlock()
+		return insertActionTicket(tx, room.ID, action)
+	})
+}
+
+// UpdateActionTicket persists an action item's content, assignees, status,
+// owner, due date, status history and review outcome
+func (s *RoomStore) UpdateActionTicket(room *Room, action *ActionTicket) error {
+	return s.withVersion(room, func(tx *sql.Tx) error {
+		assigneeIDsJSON, historyJSON, err := encodeActionLists(action)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`
+			UPDATE action_tickets
+			SET content = $1, assignee_ids = $2, status = $3, owner_id = $4, due_date = $5, completed_at = $6, status_history = $7, review_outcome = $8
+			WHERE id = $9 AND room_id = $10
+		`, action.Content, assigneeIDsJSON, action.Status, action.OwnerID, action.DueDate, action.CompletedAt, historyJSON, action.ReviewOutcome, action.ID, room.ID)
+		return err
+	})
+}
+
+// DeleteActionTicket removes an action item
+func (s *RoomStore) DeleteActionTicket(room *Room, actionID string) error {
+	return s.withVersion(room, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`DELETE FROM action_tickets WHERE id = $1 AND room_id = $2`, actionID, room.ID)
+		return err
+	})
+}
+
+// MarkCarriedOver persists the given action items as carried over to another room
+func (s *RoomStore) MarkCarriedOver(room *Room, actionIDs []string, toRoomID string) error {
+	return s.withVersion(room, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`UPDATE action_tickets SET carried_to = $1 WHERE room_id = $2 AND id = ANY($3)`, toRoomID, room.ID, pq.Array(actionIDs))
+		return err
+	})
+}
+
+// AnswerPoll records that a user answered a poll and, apart from who
+// answered, counts the score
+func (s *RoomStore) AnswerPoll(room *Room, kind PollKind, userID string, score int) error {
+	return s.withVersion(room, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			INSERT INTO poll_respondents (room_id, kind, user_id) VALUES ($1, $2, $3)
+		`, room.ID, kind, userID)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`
+			INSERT INTO poll_scores (room_id, kind, score, count) VALUES ($1, $2, $3, 1)
+			ON CONFLICT (room_id, kind, score) DO UPDATE SET count = poll_scores.count + 1
+		`, room.ID, kind, score)
+		return err
+	})
+}
+
+// ClosePoll persists a poll being closed
+func (s *RoomStore) ClosePoll(room *Room, kind PollKind) error {
+	closedAt := room.GetPoll(kind).ClosedAt
+	return s.withVersion(room, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			INSERT INTO polls (room_id, kind, closed_at) VALUES ($1, $2, $3)
+			ON CONFLICT (room_id, kind) DO UPDATE SET closed_at = $3
+		`, room.ID, kind, closedAt)
+		return err
+	})
+}
+
+// RateHealth records or replaces a user's rating of a health dimension
+func (s *RoomStore) RateHealth(room *Room, userID, dimensionID string, rating HealthRating) error {
+	return s.withVersion(room, func(tx *sql.Tx) error {
+		return upsertHealthRating(tx, room.ID, userID, dimensionID, rating)
+	})
+}
+
+// Undo persists the changes of an undo command along with the event recording
+// it, assigning the event its ID
+func (s *RoomStore) Undo(room *Room, undo *Undo) error {
+	return s.withVersion(room, func(tx *sql.Tx) error {
+		room.RLock()
+		defer room.RUnlock()
+
+		now := time.Now()
+		for _, restored := range undo.RestoredTickets {
+			if err := insertTicketWithVotes(tx, room.ID, restored.Ticket, restoredVotes(room.ID, restored, now)); err != nil {
+				return err
+			}
+		}
+		for _, action := range undo.RestoredActions {
+			if err := insertActionTicket(tx, room.ID, action); err != nil {
+				return err
+			}
+		}
+		for _, ticket := range undo.ParentResets {
+			if _, err := tx.Exec(`
+				UPDATE tickets SET deduplication_ticket_id = $1 WHERE id = $2 AND room_id = $3
+			`, ticket.DeduplicationTicketID, ticket.ID, room.ID); err != nil {
+				return err
+			}
+		}
+		if undo.PhaseChanges > 0 {
+			if err := changePhase(tx, room, undo.PhaseChanges); err != nil {
+				return err
+			}
+		}
+		return appendEvent(tx, undo.Event)
+	})
+}
+
+// ListVotes returns all votes cast in a room, oldest first
+func (s *RoomStore) ListVotes(roomID string) ([]*Vote, error) {
+	rows, err := s.db.Query(`
+		SELECT room_id, ticket_id, user_id, weight, created_at
+		FROM votes WHERE room_id = $1
+		ORDER BY created_at, ticket_id, user_id
+	`, roomID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	votes := make([]*Vote, 0)
+	for rows.Next() {
+		var v Vote
+		if err := rows.Scan(&v.RoomID, &v.TicketID, &v.UserID, &v.Weight, &v.CreatedAt); err != nil {
+			return nil, err
+		}
+		votes = append(votes, &v)
+	}
+	return votes, rows.Err()
+}
+
+// Analytics aggregates the rooms selected by the filter
+func (s *RoomStore) Analytics(filter AnalyticsFilter) (*Analytics, error) {
+	conditions, args := filter.conditions()
+	where := func(extra ...string) string {
+		all := append(append([]string{}, conditions...), extra...)
+		if len(all) == 0 {
+			return ""
+		}
+		return "WHERE " + strings.Join(all, " AND ")
+	}
+	data := newAnalyticsData()
+
+	// Figures of each room
+	rows, err := s.db.Query(`
+		SELECT r.id, r.name, r.created_at,
+			(SELECT COUNT(*) FROM tickets t WHERE t.room_id = r.id),
+			(SELECT COUNT(*) FROM participants p WHERE p.room_id = r.id AND p.status = 'approved'),
+			(SELECT COUNT(*) FROM participants p WHERE p.room_id = r.id AND p.status = 'approved' AND (
+				EXISTS (SELECT 1 FROM tickets t WHERE t.room_id = r.id AND t.author_id = p.user_id) OR
+				EXISTS (SELECT 1 FROM votes v WHERE v.room_id = r.id AND v.user_id = p.user_id))),
+			(SELECT COALESCE(SUM(v.weight), 0) FROM votes v WHERE v.room_id = r.id),
+			(SELECT COUNT(*) FROM action_tickets a WHERE a.room_id = r.id AND a.source_action_id = ''),
+			(SELECT COUNT(*) FROM action_tickets a WHERE a.room_id = r.id AND a.source_action_id = '' AND a.status = 'done'),
+			(SELECT COUNT(*) FROM action_tickets a WHERE a.room_id = r.id AND a.source_action_id = '' AND a.status = 'dropped')
+		FROM rooms r `+where()+`
+		ORDER BY r.created_at, r.id
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var stats RoomStats
+		if err := rows.Scan(&stats.RoomID, &stats.Name, &stats.CreatedAt, &stats.Tickets, &stats.Participants,
+			&stats.Contributors, &stats.Votes, &stats.Actions, &stats.ActionsDone, &stats.ActionsDropped); err != nil {
+			return nil, err
+		}
+		data.rooms = append(data.rooms, stats)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Tickets by the votes they got, leaving out merged tickets
+	voteRows, err := s.db.Query(`
+		SELECT votes, COUNT(*) FROM (
+			SELECT t.id, COALESCE(SUM(v.weight), 0) AS votes
+			FROM tickets t
+			JOIN rooms r ON r.id = t.room_id
+			LEFT JOIN votes v ON v.ticket_id = t.id
+			`+where("t.deduplication_ticket_id IS NULL")+`
+			GROUP BY t.id
+		) per_ticket
+		GROUP BY votes
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer voteRows.Close()
+	for voteRows.Next() {
+		var votes, tickets int
+		if err := voteRows.Scan(&votes, &tickets); err != nil {
+			return nil, err
+		}
+		data.ticketsByVotes[votes] = tickets
+	}
+	if err := voteRows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Time it took to get actions done
+	closeRows, err := s.db.Query(`
+		SELECT EXTRACT(EPOCH FROM a.completed_at - a.created_at) / 3600
+		FROM action_tickets a
+		JOIN rooms r ON r.id = a.room_id
+		`+where("a.source_action_id = ''", "a.status = 'done'", "a.completed_at IS NOT NULL"), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows.Close()
+	for closeRows.Next() {
+		var hours float64
+		if err := closeRows.Scan(&hours); err != nil {
+			return nil, err
+		}
+		data.hoursToClose = append(data.hoursToClose, hours)
+	}
+	if err := closeRows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Answers to closed polls
+	pollRows, err := s.db.Query(`
+		SELECT s.room_id, s.kind, s.score, s.count
+		FROM poll_scores s
+		JOIN polls p ON p.room_id = s.room_id AND p.kind = s.kind
+		JOIN rooms r ON r.id = s.room_id
+		`+where("p.closed_at IS NOT NULL"), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer pollRows.Close()
+	polls := make(map[string]map[PollKind]*Poll)
+	for pollRows.Next() {
+		var roomID string
+		var kind PollKind
+		var score, count int
+		if err := pollRows.Scan(&roomID, &kind, &score, &count); err != nil {
+			return nil, err
+		}
+		if score < MinPollScore || score > MaxPollScore {
+			continue
+		}
+		if polls[roomID] == nil {
+			polls[roomID] = make(map[PollKind]*Poll)
+		}
+		poll, ok := polls[roomID][kind]
+		if !ok {
+			poll = &Poll{Kind: kind}
+			polls[roomID][kind] = poll
+			data.polls[roomID] = append(data.polls[roomID], poll)
+		}
+		poll.Histogram[score-MinPollScore] = count
+	}
+	if err := pollRows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Ticket contents, except those still hidden by blind ticket writing
+	contentRows, err := s.db.Query(`
+		SELECT t.room_id, t.content
+		FROM tickets t
+		JOIN rooms r ON r.id = t.room_id
+		`+where("NOT (r.blind_tickets AND NOT r.tickets_revealed)"), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer contentRows.Close()
+	for contentRows.Next() {
+		var roomID, content string
+		if err := contentRows.Scan(&roomID, &content); err != nil {
+			return nil, err
+		}
+		data.contents[roomID] = append(data.contents[roomID], content)
+	}
+	if err := contentRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return data.build(), nil
+}
+
+// HealthHistory returns the results of the health check rooms selected by
+// the filter, oldest first
+func (s *RoomStore) HealthHistory(filter AnalyticsFilter) (*HealthHistory, error) {
+	conditions, args := filter.conditions()
+	where := "WHERE " + strings.Join(append(conditions, "r.health_dimensions <> '[]'"), " AND ")
+
+	rows, err := s.db.Query(`SELECT `+roomColumns+` FROM rooms r `+where+` ORDER BY r.created_at, r.id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var rooms []*Room
+	byID := make(map[string]*Room)
+	for rows.Next() {
+		room, err := scanRoom(rows)
+		if err != nil {
+			return nil, err
+		}
+		rooms = append(rooms, room)
+		byID[room.ID] = room
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	ratingRows, err := s.db.Query(`
+		SELECT h.room_id, h.user_id, h.dimension_id, h.color, h.trend
+		FROM health_ratings h
+		JOIN rooms r ON r.id = h.room_id
+		`+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer ratingRows.Close()
+	for ratingRows.Next() {
+		var roomID, userID, dimensionID string
+		var rating HealthRating
+		if err := ratingRows.Scan(&roomID, &userID, &dimensionID, &rating.Color, &rating.Trend); err != nil {
+			return nil, err
+		}
+		room, ok := byID[roomID]
+		if !ok {
+			continue
+		}
+		if room.HealthRatings == nil {
+			room.HealthRatings = make(map[string]map[string]HealthRating)
+		}
+		if room.HealthRatings[userID] == nil {
+			room.HealthRatings[userID] = make(map[string]HealthRating)
+		}
+		room.HealthRatings[userID][dimensionID] = rating
+	}
+	if err := ratingRows.Err(); err != nil {
+		return nil, err
+	}
+
+	checks := make([]HealthCheck, 0, len(rooms))
+	for _, room := range rooms {
+		checks = append(checks, room.healthCheck())
+	}
+	return newHealthHistory(checks), nil
+}
+
+// AppendEvent records an event in the room's log and assigns its ID
+func (s *RoomStore) AppendEvent(event *RoomEvent) error {
+	return appendEvent(s.db, event)
+}
+
+// appendEvent inserts an event row and assigns the event its ID
+func appendEvent(q rowQuerier, event *RoomEvent) error {
+	return q.QueryRow(`
+		INSERT INTO room_events (room_id, type, actor_id, payload, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, event.RoomID, event.Type, event.ActorID, string(event.Payload), event.CreatedAt).Scan(&event.ID)
+}
+
+// ListEvents returns up to limit events of a room with IDs greater than afterID, oldest first
+func (s *RoomStore) ListEvents(roomID string, afterID int64, limit int) ([]*RoomEvent, error) {
+	rows, err := s.db.Query(`
+		SELECT id, room_id, type, actor_id, payload, created_at
+		FROM room_events
+		WHERE room_id = $1 AND id > $2
+		ORDER BY id
+		LIMIT $3
+	`, roomID, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanEvents(rows)
+}
+
+// ListRecentEvents returns up to limit events of a room of the given types
+// with IDs less than beforeID, newest first. A beforeID of 0 starts from the
+// newest event.
+func (s *RoomStore) ListRecentEvents(roomID string, types []EventType, beforeID int64, limit int) ([]*RoomEvent, error) {
+	typeNames := make([]string, len(types))
+	for i, eventType := range types {
+		typeNames[i] = string(eventType)
+	}
+	rows, err := s.db.Query(`
+		SELECT id, room_id, type, actor_id, payload, created_at
+		FROM room_events
+		WHERE room_id = $1 AND type = ANY($2) AND ($3::bigint = 0 OR id < $3::bigint)
+		ORDER BY id DESC
+		LIMIT $4
+	`, roomID, pq.Array(typeNames), beforeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanEvents(rows)
+}
+
+func scanEvents(rows *sql.Rows) ([]*RoomEvent, error) {
+	events := make([]*RoomEvent, 0)
+	for rows.Next() {
+		var event RoomEvent
+		var payload []byte
+		if err := rows.Scan(&event.ID, &event.RoomID, &event.Type, &event.ActorID, &payload, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		event.Payload = payload
+		events = append(events, &event)
+	}
+	return events, rows.Err()
+}
+
+// CreateTemplate adds a custom template
+func (s *RoomStore) CreateTemplate(template *Template) error {
+	columns, phases, dimensions, err := encodeTemplate(template)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`
+		INSERT INTO templates (id, name, description, columns, votes_per_user, max_votes_per_ticket, phases, health_dimensions, created_by, team_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`, template.ID, template.Name, template.Description, columns, template.VotesPerUser, template.MaxVotesPerTicket, phases, dimensions, template.CreatedBy, template.TeamID, template.CreatedAt)
+	return err
+}
+
+// GetTemplate retrieves a custom template by ID
+func (s *RoomStore) GetTemplate(id string) (*Template, bool) {
+	template, err := scanTemplate(s.db.QueryRow(`SELECT `+templateColumns+` FROM templates WHERE id = $1`, id))
+	if err != nil {
+		return nil, false
+	}
+	return template, true
+}
+
+// ListTemplates returns the custom templates created by the user or shared
+// with one of the teams, oldest first
+func (s *RoomStore) ListTemplates(userID string, teamIDs []string) ([]*Template, error) {
+	rows, err := s.db.Query(`
+		SELECT `+templateColumns+` FROM templates
+		WHERE created_by = $1 OR team_id = ANY($2)
+		ORDER BY created_at, id
+	`, userID, pq.Array(teamIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := make([]*Template, 0)
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+	return templates, rows.Err()
+}
+
+// UpdateTemplate replaces a custom template
+func (s *RoomStore) UpdateTemplate(template *Template) error {
+	columns, phases, dimensions, err := encodeTemplate(template)
+	if err != nil {
+		return err
+	}
+	result, err := s.db.Exec(`
+		UPDATE templates SET name = $1, description = $2, columns = $3, votes_per_user = $4,
+			max_votes_per_ticket = $5, phases = $6, health_dimensions = $7, team_id = $8
+		WHERE id = $9
+	`, template.Name, template.Description, columns, template.VotesPerUser, template.MaxVotesPerTicket, phases, dimensions, template.TeamID, template.ID)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrTemplateNotFound
+	}
+	return err
+}
+
+// DeleteTemplate removes a custom template
+func (s *RoomStore) DeleteTemplate(id string) error {
+	result, err := s.db.Exec(`DELETE FROM templates WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrTemplateNotFound
+	}
+	return err
+}
+
+// templateColumns are the templates table columns read by scanTemplate, in order
+const templateColumns = `id, name, description, columns, votes_per_user, max_votes_per_ticket, phases, health_dimensions, created_by, team_id, created_at`
+
+// scanTemplate reads a templates row selected with templateColumns
+func scanTemplate(row rowScanner) (*Template, error) {
+	var template Template
+	var columns, phases, dimensions []byte
+	if err := row.Scan(&template.ID, &template.Name, &template.Description, &columns,
+		&template.VotesPerUser, &template.MaxVotesPerTicket, &phases, &dimensions, &template.CreatedBy, &template.TeamID, &template.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(columns, &template.Columns); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(phases, &template.Phases); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(dimensions, &template.HealthDimensions); err != nil {
+		return nil, err
+	}
+	if len(template.HealthDimensions) == 0 {
+		template.HealthDimensions = nil
+	}
+	return &template, nil
+}
+
+// encodeTemplate encodes a template's columns, phases and health dimensions
+// for their JSONB columns
+func encodeTemplate(template *Template) (string, string, string, error) {
+	columns, err := json.Marshal(template.Columns)
+	if err != nil {
+		return "", "", "", err
+	}
+	phases, err := json.Marshal(template.Phases)
+	if err != nil {
+		return "", "", "", err
+	}
+	dimensions, err := encodeHealthDimensions(template.HealthDimensions)
+	if err != nil {
+		return "", "", "", err
+	}
+	return string(columns), string(phases), dimensions, nil
+}
+
+// CreateTeam adds a team along with its members
+func (s *RoomStore) CreateTeam(team *Team) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO teams (id, name, created_by, created_at) VALUES ($1, $2, $3, $4)
+	`, team.ID, team.Name, team.CreatedBy, team.CreatedAt)
+	if err != nil {
+		return err
+	}
+	for _, member := range team.Members {
+		if err := upsertTeamMember(tx, team.ID, member); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetTeam retrieves a team and its members by ID
+func (s *RoomStore) GetTeam(id string) (*Team, bool) {
+	team := &Team{Members: make(map[string]*TeamMember)}
+	err := s.db.QueryRow(`
+		SELECT id, name, created_by, created_at FROM teams WHERE id = $1
+	`, id).Scan(&team.ID, &team.Name, &team.CreatedBy, &team.CreatedAt)
+	if err != nil {
+		return nil, false
+	}
+
+	rows, err := s.db.Query(`
+		SELECT user_id, user_email, user_name, role, joined_at FROM team_members WHERE team_id = $1
+	`, id)
+	if err != nil {
+		return nil, false
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var member TeamMember
+		if err := rows.Scan(&member.User.ID, &member.User.Email, &member.User.Name, &member.Role, &member.JoinedAt); err != nil {
+			return nil, false
+		}
+		team.Members[member.User.ID] = &member
+	}
+	if rows.Err() != nil {
+		return nil, false
+	}
+	return team, true
+}
+
+// ListTeamsByMember returns all teams the user is a member of, oldest first
+func (s *RoomStore) ListTeamsByMember(userID string) ([]*Team, error) {
+	rows, err := s.db.Query(`
+		SELECT team_id FROM team_members
+		JOIN teams ON teams.id = team_members.team_id
+		WHERE user_id = $1
+		ORDER BY teams.created_at, teams.id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	teams := make([]*Team, 0, len(ids))
+	for _, id := range ids {
+		// A team deleted in the meantime is left out
+		if team, ok := s.GetTeam(id); ok {
+			teams = append(teams, team)
+		}
+	}
+	return teams, nil
+}
+
+// DeleteTeam removes a team; its rooms and templates stay but no longer belong to a team
+func (s *RoomStore) DeleteTeam(id string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`DELETE FROM teams WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrTeamNotFound
+	}
+	if _, err := tx.Exec(`UPDATE rooms SET team_id = '', version = version + 1 WHERE team_id = $1`, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE templates SET team_id = '' WHERE team_id = $1`, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpsertTeamMember inserts or updates a team member
+func (s *RoomStore) UpsertTeamMember(teamID string, member *TeamMember) error {
+	return upsertTeamMember(s.db, teamID, member)
+}
+
+// RemoveTeamMember removes a member from a team
+func (s *RoomStore) RemoveTeamMember(teamID, userID string) error {
+	_, err := s.db.Exec(`DELETE FROM team_members WHERE team_id = $1 AND user_id = $2`, teamID, userID)
+	return err
+}
+
+// upsertTeamMember inserts a team member or updates their name, email and role
+func upsertTeamMember(ex execer, teamID string, member *TeamMember) error {
+	_, err := ex.Exec(`
+		INSERT INTO team_members (team_id, user_id, user_email, user_name, role, joined_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (team_id, user_id) DO UPDATE SET user_email = $3, user_name = $4, role = $5
+	`, teamID, member.User.ID, member.User.Email, member.User.Name, member.Role, member.JoinedAt)
+	return err
+}
+
+// withVersion runs change in a transaction that also bumps the room's version.
+// It fails with ErrVersionConflict if the stored version no longer matches
+// room.Version, i.e. someone else wrote the room since it was loaded.
+func (s *RoomStore) withVersion(room *Room, change func(tx *sql.Tx) error) error {
+	room.RLock()
+	roomID, version := room.ID, room.Version
+	room.RUnlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE rooms SET version = version + 1 WHERE id = $1 AND version = $2`, roomID, version)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		var exists bool
+		if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM rooms WHERE id = $1)`, roomID).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return ErrRoomNotFound
+		}
+		return ErrVersionConflict
+	}
+
+	if err := change(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	room.Lock()
+	room.Version = version + 1
+	room.Unlock()
+	return nil
+}
+
+// updateRoomRow writes the room's own fields (name, phase, settings) to its rooms row
+func updateRoomRow(ex execer, room *Room) error {
+	phases, err := json.Marshal(room.Phases)
+	if err != nil {
+		return err
+	}
+	timer, err := encodeTimer(room.Timer)
+	if err != nil {
+		return err
+	}
+	dimensions, err := encodeHealthDimensions(room.HealthDimensions)
+	if err != nil {
+		return err
+	}
+	_, err = ex.Exec(`
+		UPDATE rooms SET name = $1, owner_id = $2, phase = $3, phases = $4, timer = $5, votes_per_user = $6, max_votes_per_ticket = $7,
+			hidden_votes = $8, votes_revealed = $9, anonymous_tickets = $10, blind_tickets = $11, tickets_revealed = $12, auto_approve = $13,
+			previous_room_id = $14, team_id = $15, health_dimensions = $16
+		WHERE id = $17
+	`, room.Name, room.OwnerID, room.Phase, string(phases), timer, room.VotesPerUser, room.MaxVotesPerTicket, room.HiddenVotes, room.VotesRevealed, room.AnonymousTickets, room.BlindTickets, room.TicketsRevealed, room.AutoApprove, room.PreviousRoomID, room.TeamID, dimensions, room.ID)
+	return err
+}
+
+// encodeTimer encodes a room's timer for its JSONB column, which is NULL when no timer is set
+func encodeTimer(timer *Timer) (*string, error) {
+	if timer == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(timer)
+	if err != nil {
+		return nil, err
+	}
+	encoded := string(data)
+	return &encoded, nil
+}
+
+// insertColumns inserts the room's columns in order
+func insertColumns(ex execer, room *Room) error {
+	for position, column := range room.Columns {
+		_, err := ex.Exec(`
+			INSERT INTO room_columns (room_id, id, title, color, prompt, position)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, room.ID, column.ID, column.Title, column.Color, column.Prompt, position)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// insertPhaseHistoryEntry inserts a single phase history row
+func insertPhaseHistoryEntry(ex execer, roomID string, entry PhaseHistoryEntry) error {
+	_, err := ex.Exec(`
+		INSERT INTO phase_history (room_id, phase, entered_at, exited_at)
+		VALUES ($1, $2, $3, $4)
+	`, roomID, entry.Phase, entry.EnteredAt, entry.ExitedAt)
+	return err
+}
+
+// insertParticipant inserts a single participant row
+func insertParticipant(ex execer, roomID string, participant *Participant) error {
+	_, err := ex.Exec(`
+		INSERT INTO participants (room_id, user_id, user_email, user_name, role, status)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, roomID, participant.User.ID, participant.User.Email, participant.User.Name, participant.Role, participant.Status)
+	return err
+}
+
+// insertPoll inserts a poll's respondents, its score counts and when it was closed
+func insertPoll(ex execer, roomID string, poll *Poll) error {
+	if _, err := ex.Exec(`
+		INSERT INTO polls (room_id, kind, closed_at) VALUES ($1, $2, $3)
+	`, roomID, poll.Kind, poll.ClosedAt); err != nil {
+		return err
+	}
+	for i, count := range poll.Histogram {
+		if count == 0 {
+			continue
+		}
+		if _, err := ex.Exec(`
+			INSERT INTO poll_scores (room_id, kind, score, count) VALUES ($1, $2, $3, $4)
+		`, roomID, poll.Kind, i+MinPollScore, count); err != nil {
+			return err
+		}
+	}
+	for _, userID := range poll.RespondentIDs {
+		if _, err := ex.Exec(`
+			INSERT INTO poll_respondents (room_id, kind, user_id) VALUES ($1, $2, $3)
+		`, roomID, poll.Kind, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upsertHealthRating inserts or replaces a user's rating of a dimension
+func upsertHealthRating(ex execer, roomID, userID, dimensionID string, rating HealthRating) error {
+	_, err := ex.Exec(`
+		INSERT INTO health_ratings (room_id, user_id, dimension_id, color, trend) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (room_id, user_id, dimension_id) DO UPDATE SET color = $4, trend = $5
+	`, roomID, userID, dimensionID, rating.Color, rating.Trend)
+	return err
+}
+
+// encodeHealthDimensions encodes health dimensions for their JSONB column,
+// which holds an empty list for rooms and templates without any
+func encodeHealthDimensions(dimensions []HealthDimension) (string, error) {
+	if dimensions == nil {
+		dimensions = []HealthDimension{}
+	}
+	data, err := json.Marshal(dimensions)
+	return string(data), err
+}
+
+// insertTicket inserts a single ticket row along with the votes, reactions
+// and comments it carries
+func insertTicket(ex execer, roomID string, ticket *Ticket) error {
+	return insertTicketWithVotes(ex, roomID, ticket, ticketVotes(roomID, ticket, time.Now()))
+}
+
+// insertTicketWithVotes inserts a single ticket row along with the given
+// votes and the reactions and comments it carries
+func insertTicketWithVotes(ex execer, roomID string, ticket *Ticket, votes []*Vote) error {
+	_, err := ex.Exec(`
+		INSERT INTO tickets (id, room_id, content, author_id, column_id, deduplication_ticket_id, covered, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, ticket.ID, roomID, ticket.Content, ticket.AuthorID, ticket.ColumnID, ticket.DeduplicationTicketID, ticket.Covered, ticket.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	for _, vote := range votes {
+		_, err := ex.Exec(`
+			INSERT INTO votes (room_id, ticket_id, user_id, weight, created_at)
+			VALUES ($1, $2, $3, $4, $5)
+		`, vote.RoomID, vote.TicketID, vote.UserID, vote.Weight, vote.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Reactions keep their order through created_at
+	reactedAt := time.Now()
+	for emoji, userIDs := range ticket.Reactions {
+		for i, userID := range userIDs {
+			_, err := ex.Exec(`
+				INSERT INTO reactions (room_id, ticket_id, user_id, emoji, created_at)
+				VALUES ($1, $2, $3, $4, $5)
+			`, roomID, ticket.ID, userID, emoji, reactedAt.Add(time.Duration(i)*time.Microsecond))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, comment := range ticket.Comments {
+		if err := insertComment(ex, roomID, comment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// insertComment inserts a single comment row
+func insertComment(ex execer, roomID string, comment *Comment) error {
+	_, err := ex.Exec(`
+		INSERT INTO comments (id, room_id, ticket_id, author_id, content, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, comment.ID, roomID, comment.TicketID, comment.AuthorID, comment.Content, comment.CreatedAt, comment.UpdatedAt)
+	return err
+}
+
+// insertActionTicket inserts a single action ticket row
+func insertActionTicket(ex execer, roomID string, action *ActionTicket) error {
+	assigneeIDsJSON, historyJSON, err := encodeActionLists(action)
+	if err != nil {
+		return err
+	}
+	_, err = ex.Exec(`
+		INSERT INTO action_tickets (id, room_id, content, assignee_ids, ticket_id, status, owner_id, due_date, completed_at, status_history, created_at, source_room_id, source_action_id, review_outcome, carried_to)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`, action.ID, roomID, action.Content, assigneeIDsJSON, action.TicketID, action.Status, action.OwnerID, action.DueDate, action.CompletedAt, historyJSON, action.CreatedAt, action.SourceRoomID, action.SourceActionID, action.ReviewOutcome, action.CarriedTo)
+	return err
+}
+
+// encodeActionLists encodes an action's assignees and status history for their JSONB columns
+func encodeActionLists(action *ActionTicket) (assigneeIDsJSON, historyJSON []byte, err error) {
+	assigneeIDsJSON, err = json.Marshal(action.AssigneeIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	history := action.StatusHistory
+	if history == nil {
+		history = []ActionStatusChange{}
+	}
+	historyJSON, err = json.Marshal(history)
+	if err != nil {
+		return nil, nil, err
+	}
+	return assigneeIDsJSON, historyJSON, nil
+}
+
+// actionColumns are the action_tickets table columns read by scanActionTicket, in order
+const actionColumns = `id, content, assignee_ids, ticket_id, status, owner_id, due_date, completed_at, status_history, created_at, source_room_id, source_action_id, review_outcome, carried_to`
+
+// scanActionTicket reads an action ticket selected with actionColumns
+func scanActionTicket(row rowScanner) (*ActionTicket, error) {
+	var at ActionTicket
+	var assigneeIDsJSON, historyJSON []byte
+	var dueDate, completedAt sql.NullTime
+	err := row.Scan(&at.ID, &at.Content, &assigneeIDsJSON, &at.TicketID, &at.Status, &at.OwnerID, &dueDate, &completedAt, &historyJSON, &at.CreatedAt, &at.SourceRoomID, &at.SourceActionID, &at.ReviewOutcome, &at.CarriedTo)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(assigneeIDsJSON, &at.AssigneeIDs); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(historyJSON, &at.StatusHistory); err != nil {
+		return nil, err
+	}
+	if len(at.StatusHistory) == 0 {
+		at.StatusHistory = nil
+	}
+	if dueDate.Valid {
+		at.DueDate = &dueDate.Time
+	}
+	if completedAt.Valid {
+		at.CompletedAt = &completedAt.Time
+	}
+	return &at, nil
 }
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// TeamRole represents a user's role in a team
+type TeamRole string
+
+const (
+	TeamRoleAdmin  TeamRole = "admin"
+	TeamRoleMember TeamRole = "member"
+)
+
+// ErrInvalidTeamRole is returned for roles other than the TeamRole constants
+var ErrInvalidTeamRole = errors.New("role must be admin or member")
+
+// ErrLastTeamAdmin is returned when removing or demoting a team's only admin
+var ErrLastTeamAdmin = errors.New("a team needs at least one admin")
+
+// ValidateTeamRole checks that a role is one of the known team roles
+func ValidateTeamRole(role TeamRole) error {
+	switch role {
+	case TeamRoleAdmin, TeamRoleMember:
+		return nil
+	}
+	return ErrInvalidTeamRole
+}
+
+// Team is a group of people holding retrospectives together. Members of a
+// team are approved as soon as they join one of the team's rooms; admins
+// manage the team's membership.
+type Team struct {
+	ID        string                 `json:"id"`
+	Name      string                 `json:"name"`
+	Members   map[string]*TeamMember `json:"members"`
+	CreatedBy string                 `json:"created_by"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// TeamMember is a user's membership in a team
+type TeamMember struct {
+	User     User      `json:"user"`
+	Role     TeamRole  `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// NewTeam creates a team with its creator as the first admin
+func NewTeam(id, name string, creator User) *Team {
+	now := time.Now()
+	return &Team{
+		ID:   id,
+		Name: name,
+		Members: map[string]*TeamMember{
+			creator.ID: {User: creator, Role: TeamRoleAdmin, JoinedAt: now},
+		},
+		CreatedBy: creator.ID,
+		CreatedAt: now,
+	}
+}
+
+// Clone returns a deep copy of the team
+func (t *Team) Clone() *Team {
+	clone := *t
+	clone.Members = make(map[string]*TeamMember, len(t.Members))
+	for id, member := range t.Members {
+		m := *member
+		clone.Members[id] = &m
+	}
+	return &clone
+}
+
+// IsMember reports whether the user belongs to the team, in any role
+func (t *Team) IsMember(userID string) bool {
+	_, ok := t.Members[userID]
+	return ok
+}
+
+// IsAdmin reports whether the user is one of the team's admins
+func (t *Team) IsAdmin(userID string) bool {
+	member, ok := t.Members[userID]
+	return ok && member.Role == TeamRoleAdmin
+}
+
+// CheckMemberChange reports whether the user can be given the role, or
+// removed from the team when role is empty, without leaving it without an admin
+func (t *Team) CheckMemberChange(userID string, role TeamRole) error {
+	if !t.IsAdmin(userID) || role == TeamRoleAdmin {
+		return nil
+	}
+	for id, member := range t.Members {
+		if id != userID && member.Role == TeamRoleAdmin {
+			return nil
+		}
+	}
+	return ErrLastTeamAdmin
+}
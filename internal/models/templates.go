@@ -0,0 +1,186 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Template describes a retrospective format: its columns with their prompts,
+// the default voting limits and the phases a room goes through. Templates
+// with health dimensions make health check rooms. Built-in templates ship
+// with GoRetro; custom ones are seen by their creator and, when TeamID is
+// set, by the members of that team.
+type Template struct {
+	ID                string            `json:"id"`
+	Name              string            `json:"name"`
+	Description       string            `json:"description"`
+	Columns           []Column          `json:"columns"`
+	VotesPerUser      int               `json:"votes_per_user"`
+	MaxVotesPerTicket int               `json:"max_votes_per_ticket"`
+	Phases            []Phase           `json:"phases"`
+	HealthDimensions  []HealthDimension `json:"health_dimensions,omitempty"`
+	Builtin           bool              `json:"builtin"`
+	CreatedBy         string            `json:"created_by,omitempty"`
+	TeamID            string            `json:"team_id,omitempty"`
+	CreatedAt         time.Time         `json:"created_at"`
+}
+
+// maxTemplateColumns caps how many columns a template can define
+const maxTemplateColumns = 8
+
+// maxTemplateVotes caps the votes per user a template can hand out
+const maxTemplateVotes = 20
+
+// builtinTemplates are the well-known retrospective formats
+var builtinTemplates = []*Template{
+	{
+		ID:          "start-stop-continue",
+		Name:        "Start, Stop, Continue",
+		Description: "What the team should start, stop and keep doing",
+		Columns: []Column{
+			{ID: "start", Title: "Start", Color: "#22c55e", Prompt: "What should we start doing?"},
+			{ID: "stop", Title: "Stop", Color: "#ef4444", Prompt: "What should we stop doing?"},
+			{ID: "continue", Title: "Continue", Color: "#3b82f6", Prompt: "What should we keep doing?"},
+		},
+		VotesPerUser:      3,
+		MaxVotesPerTicket: 1,
+	},
+	{
+		ID:          "mad-sad-glad",
+		Name:        "Mad, Sad, Glad",
+		Description: "How the last iteration made the team feel",
+		Columns: []Column{
+			{ID: "mad", Title: "Mad", Color: "#ef4444", Prompt: "What drove you crazy?"},
+			{ID: "sad", Title: "Sad", Color: "#3b82f6", Prompt: "What disappointed you?"},
+			{ID: "glad", Title: "Glad", Color: "#22c55e", Prompt: "What made you happy?"},
+		},
+		VotesPerUser:      3,
+		MaxVotesPerTicket: 1,
+	},
+	{
+		ID:          "4ls",
+		Name:        "4Ls",
+		Description: "Liked, Learned, Lacked, Longed for",
+		Columns: []Column{
+			{ID: "liked", Title: "Liked", Color: "#22c55e", Prompt: "What did you enjoy?"},
+			{ID: "learned", Title: "Learned", Color: "#3b82f6", Prompt: "What did you learn?"},
+			{ID: "lacked", Title: "Lacked", Color: "#f97316", Prompt: "What was missing?"},
+			{ID: "longed-for", Title: "Longed for", Color: "#a855f7", Prompt: "What do you wish we had?"},
+		},
+		VotesPerUser:      4,
+		MaxVotesPerTicket: 2,
+	},
+	{
+		ID:          "sailboat",
+		Name:        "Sailboat",
+		Description: "What moves the team towards its goal and what holds it back",
+		Columns: []Column{
+			{ID: "wind", Title: "Wind", Color: "#22c55e", Prompt: "What pushes us forward?"},
+			{ID: "anchors", Title: "Anchors", Color: "#ef4444", Prompt: "What holds us back?"},
+			{ID: "rocks", Title: "Rocks", Color: "#f97316", Prompt: "What risks lie ahead?"},
+			{ID: "island", Title: "Island", Color: "#3b82f6", Prompt: "Where do we want to get?"},
+		},
+		VotesPerUser:      3,
+		MaxVotesPerTicket: 1,
+	},
+	{
+		ID:          "squad-health-check",
+		Name:        "Squad Health Check",
+		Description: "Rate how healthy the team is, then discuss what drags it down",
+		Columns: []Column{
+			{ID: "strengths", Title: "Strengths", Color: "#22c55e", Prompt: "What keeps us healthy?"},
+			{ID: "concerns", Title: "Concerns", Color: "#ef4444", Prompt: "What makes a dimension red or worse than before?"},
+		},
+		VotesPerUser:      3,
+		MaxVotesPerTicket: 1,
+		Phases:            []Phase{PhaseHealthCheck, PhaseTicketing, PhaseVoting, PhaseDiscussion, PhaseSummary},
+		HealthDimensions: []HealthDimension{
+			{ID: "easy-to-release", Title: "Easy to release", Description: "Releasing is simple, safe, painless and mostly automated"},
+			{ID: "suitable-process", Title: "Suitable process", Description: "Our way of working fits us perfectly"},
+			{ID: "tech-quality", Title: "Tech quality", Description: "We're proud of the quality of our code; it's clean, easy to read and has great test coverage"},
+			{ID: "value", Title: "Value", Description: "We deliver great stuff and our stakeholders are really happy"},
+			{ID: "speed", Title: "Speed", Description: "We get stuff done really quickly, with no waiting and no delays"},
+			{ID: "mission", Title: "Mission", Description: "We know exactly why we are here and we're really excited about it"},
+			{ID: "fun", Title: "Fun", Description: "We love going to work and have great fun working together"},
+			{ID: "learning", Title: "Learning", Description: "We're learning lots of interesting stuff all the time"},
+			{ID: "support", Title: "Support", Description: "We always get great support and help when we ask for it"},
+			{ID: "pawns-or-players", Title: "Pawns or players", Description: "We are in control of our destiny; we decide what to build and how to build it"},
+		},
+	},
+}
+
+// BuiltinTemplates returns copies of the templates that ship with GoRetro
+func BuiltinTemplates() []*Template {
+	templates := make([]*Template, 0, len(builtinTemplates))
+	for _, t := range builtinTemplates {
+		clone := t.Clone()
+		clone.Builtin = true
+		if len(clone.Phases) == 0 {
+			clone.Phases = DefaultPhases()
+		}
+		templates = append(templates, clone)
+	}
+	return templates
+}
+
+// BuiltinTemplate returns a copy of the built-in template with the given ID
+func BuiltinTemplate(id string) (*Template, bool) {
+	for _, t := range BuiltinTemplates() {
+		if t.ID == id {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// Clone returns a deep copy of the template
+func (t *Template) Clone() *Template {
+	clone := *t
+	clone.Columns = append([]Column{}, t.Columns...)
+	clone.Phases = append([]Phase{}, t.Phases...)
+	clone.HealthDimensions = append([]HealthDimension(nil), t.HealthDimensions...)
+	return &clone
+}
+
+// Validate checks a custom template before it is stored. Missing phases
+// default to the standard sequence.
+func (t *Template) Validate() error {
+	if t.Name == "" {
+		return errors.New("name is required")
+	}
+	if len(t.Columns) == 0 || len(t.Columns) > maxTemplateColumns {
+		return errors.New("a template needs between 1 and 8 columns")
+	}
+	if err := ValidateColumns(t.Columns); err != nil {
+		return err
+	}
+	if t.VotesPerUser < 1 || t.VotesPerUser > maxTemplateVotes {
+		return errors.New("votes per user must be between 1 and 20")
+	}
+	if t.MaxVotesPerTicket < 1 || t.MaxVotesPerTicket > t.VotesPerUser {
+		return errors.New("votes per ticket must be between 1 and the votes per user")
+	}
+	if err := ValidateHealthDimensions(t.HealthDimensions); err != nil {
+		return err
+	}
+	if len(t.Phases) == 0 {
+		t.Phases = DefaultPhases()
+	}
+	return ValidatePhases(t.Phases)
+}
+
+// ApplyTo gives a new room the template's columns, phases and health
+// dimensions, starting it in the first phase. Voting limits are only defaults
+// and are picked by the caller.
+func (t *Template) ApplyTo(room *Room) error {
+	if len(t.Phases) > 0 {
+		if err := room.ConfigurePhases(t.Phases); err != nil {
+			return err
+		}
+	}
+	room.Lock()
+	defer room.Unlock()
+	room.Columns = append([]Column{}, t.Columns...)
+	room.HealthDimensions = append([]HealthDimension(nil), t.HealthDimensions...)
+	return nil
+}
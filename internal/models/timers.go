@@ -0,0 +1,152 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// MaxTimerDuration caps how long a phase timer can run, extensions included
+const MaxTimerDuration = 2 * time.Hour
+
+// ErrNoTimer is returned when changing a timer while none is set
+var ErrNoTimer = errors.New("no timer is set")
+
+// ErrTimerNotRunning is returned when pausing a timer that is already paused
+var ErrTimerNotRunning = errors.New("timer is not running")
+
+// ErrTimerRunning is returned when resuming a timer that is already running
+var ErrTimerRunning = errors.New("timer is already running")
+
+// ErrInvalidTimerDuration is returned for durations that are not positive or exceed MaxTimerDuration
+var ErrInvalidTimerDuration = errors.New("timer duration must be between 1 second and 2 hours")
+
+// Timer timeboxes the phase it was started in. A running timer has an end
+// time; a paused one remembers how much time was left instead.
+type Timer struct {
+	Phase       Phase         `json:"phase"`
+	Duration    time.Duration `json:"duration"`
+	EndsAt      time.Time     `json:"ends_at"`
+	Remaining   time.Duration `json:"remaining"`
+	AutoAdvance bool          `json:"auto_advance"`
+}
+
+func cloneTimer(t *Timer) *Timer {
+	if t == nil {
+		return nil
+	}
+	clone := *t
+	return &clone
+}
+
+// Running reports whether the timer is counting down
+func (t *Timer) Running() bool {
+	return !t.EndsAt.IsZero()
+}
+
+// RemainingAt returns how much time is left at the given moment
+func (t *Timer) RemainingAt(now time.Time) time.Duration {
+	if !t.Running() {
+		return t.Remaining
+	}
+	return max(t.EndsAt.Sub(now), 0)
+}
+
+// StartTimer starts a new timer for the current phase, replacing any existing one
+func (r *Room) StartTimer(duration time.Duration, autoAdvance bool, now time.Time) error {
+	if duration < time.Second || duration > MaxTimerDuration {
+		return ErrInvalidTimerDuration
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Timer = &Timer{
+		Phase:       r.Phase,
+		Duration:    duration,
+		EndsAt:      now.Add(duration),
+		AutoAdvance: autoAdvance,
+	}
+	return nil
+}
+
+// PauseTimer stops the countdown, keeping the time that is left
+func (r *Room) PauseTimer(now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Timer == nil {
+		return ErrNoTimer
+	}
+	if !r.Timer.Running() {
+		return ErrTimerNotRunning
+	}
+	r.Timer.Remaining = r.Timer.RemainingAt(now)
+	r.Timer.EndsAt = time.Time{}
+	return nil
+}
+
+// ResumeTimer continues a paused timer
+func (r *Room) ResumeTimer(now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Timer == nil {
+		return ErrNoTimer
+	}
+	if r.Timer.Running() {
+		return ErrTimerRunning
+	}
+	r.Timer.EndsAt = now.Add(r.Timer.Remaining)
+	r.Timer.Remaining = 0
+	return nil
+}
+
+// ExtendTimer adds time to a running or paused timer
+func (r *Room) ExtendTimer(extra time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Timer == nil {
+		return ErrNoTimer
+	}
+	if extra < time.Second || r.Timer.Duration+extra > MaxTimerDuration {
+		return ErrInvalidTimerDuration
+	}
+	r.Timer.Duration += extra
+	if r.Timer.Running() {
+		r.Timer.EndsAt = r.Timer.EndsAt.Add(extra)
+	} else {
+		r.Timer.Remaining += extra
+	}
+	return nil
+}
+
+// StopTimer removes the room's timer. It reports whether there was one.
+func (r *Room) StopTimer() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stopped := r.Timer != nil
+	r.Timer = nil
+	return stopped
+}
+
+// ExpireTimer removes the timer if it is the running one that was due at
+// endsAt and that moment has passed. It returns the expired timer, or nil if
+// the timer was changed in the meantime.
+func (r *Room) ExpireTimer(endsAt, now time.Time) *Timer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	timer := r.Timer
+	if timer == nil || !timer.Running() || !timer.EndsAt.Equal(endsAt) || now.Before(endsAt) {
+		return nil
+	}
+	r.Timer = nil
+	return timer
+}
+
+// NextPhase returns the phase after the current one in the room's pipeline
+func (r *Room) NextPhase() (Phase, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	i := r.phaseIndex(r.Phase)
+	if i < 0 || i+1 >= len(r.Phases) {
+		return "", false
+	}
+	return r.Phases[i+1], true
+}
@@ -0,0 +1,24 @@
+package models
+
+// Undo holds the changes an undo command made to a room, which are persisted
+// together with the event recording the undo
+type Undo struct {
+	// RestoredTickets are deleted tickets added back
+	RestoredTickets []*RestoredTicket
+	// RestoredActions are deleted action items added back
+	RestoredActions []*ActionTicket
+	// ParentResets are tickets whose merge parent was set back
+	ParentResets []*Ticket
+	// PhaseChanges counts the times the phase was changed back with Room.SetPhase
+	PhaseChanges int
+	// Event records the undo in the room's log and is assigned its ID
+	Event *RoomEvent
+}
+
+// RestoredTicket is a deleted ticket added back by an undo. Votes are the
+// ticket's votes when it was deleted, so the votes it gets back keep the time
+// they were cast.
+type RestoredTicket struct {
+	Ticket *Ticket
+	Votes  []*Vote
+}
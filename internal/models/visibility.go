@@ -0,0 +1,128 @@
+package models
+
+import "encoding/json"
+
+// TicketView returns the ticket as the viewer is allowed to see it. While
+// blind ticket writing hides tickets, other people's tickets are not visible
+// at all and nil is returned. While votes are hidden only the viewer's own
+// votes are kept, and in anonymous rooms the author of the ticket and of each
+// comment is only shown to that author. The ticket itself is returned when
+// nothing has to be hidden. The caller must hold the room's read lock.
+func (r *Room) TicketView(ticket *Ticket, viewerID string) *Ticket {
+	if r.ticketsHidden() && ticket.AuthorID != viewerID {
+		return nil
+	}
+	view := ticket
+	if r.HiddenVotes && !r.VotesRevealed {
+		view = view.WithVotesOf(viewerID)
+	}
+	if r.AnonymousTickets && ticket.AuthorID != viewerID {
+		if view == ticket {
+			view = ticket.Clone()
+		}
+		view.AuthorID = ""
+	}
+	if r.AnonymousTickets && len(ticket.Comments) > 0 {
+		if view == ticket {
+			view = ticket.Clone()
+		}
+		for i, comment := range ticket.Comments {
+			view.Comments[i] = r.CommentView(comment, viewerID)
+		}
+	}
+	return view
+}
+
+// VisibleTicket returns a ticket as the viewer is allowed to see it, or false
+// if it doesn't exist or is hidden from the viewer
+func (r *Room) VisibleTicket(ticketID, viewerID string) (*Ticket, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ticket, ok := r.Tickets[ticketID]
+	if !ok {
+		return nil, false
+	}
+	view := r.TicketView(ticket, viewerID)
+	return view, view != nil
+}
+
+// TicketViews returns all tickets of the room as the viewer is allowed to see
+// them. The caller must hold the room's read lock.
+func (r *Room) TicketViews(viewerID string) map[string]*Ticket {
+	if !r.PersonalizesTickets() {
+		return r.Tickets
+	}
+	tickets := make(map[string]*Ticket, len(r.Tickets))
+	for id, ticket := range r.Tickets {
+		if view := r.TicketView(ticket, viewerID); view != nil {
+			tickets[id] = view
+		}
+	}
+	return tickets
+}
+
+// HiddenTicketCount returns how many tickets blind ticket writing keeps from
+// the viewer. Only moderators are told: ok is false for everyone else and
+// while tickets are not hidden. The caller must hold the room's read lock.
+func (r *Room) HiddenTicketCount(viewerID string) (count int, ok bool) {
+	if !r.ticketsHidden() || !r.isModeratorOrOwner(viewerID) {
+		return 0, false
+	}
+	for _, ticket := range r.Tickets {
+		if ticket.AuthorID != viewerID {
+			count++
+		}
+	}
+	return count, true
+}
+
+// PersonalizesTickets reports whether participants currently see different
+// versions of the room's tickets. The caller must hold the room's read lock.
+func (r *Room) PersonalizesTickets() bool {
+	return (r.HiddenVotes && !r.VotesRevealed) || r.AnonymousTickets || r.ticketsHidden()
+}
+
+// authoredTicketEvents are the events that tell who wrote or changed a
+// ticket or one of its comments
+var authoredTicketEvents = map[EventType]bool{
+	EventTicketAdded:    true,
+	EventTicketEdited:   true,
+	EventTicketDeleted:  true,
+	EventCommentAdded:   true,
+	EventCommentEdited:  true,
+	EventCommentDeleted: true,
+}
+
+// EventView returns a log event as the viewer is allowed to see it, hiding
+// the same identities as TicketView. Events about other people's tickets are
+// not visible while tickets are hidden and nil is returned. The caller must
+// hold the room's read lock.
+func (r *Room) EventView(event *RoomEvent, viewerID string) *RoomEvent {
+	if event.ActorID == viewerID {
+		return event
+	}
+	if r.ticketsHidden() && authoredTicketEvents[event.Type] {
+		return nil
+	}
+
+	votesHidden := r.HiddenVotes && !r.VotesRevealed
+	hideActor := (r.AnonymousTickets && authoredTicketEvents[event.Type]) ||
+		(votesHidden && (event.Type == EventVoteAdded || event.Type == EventVoteRemoved))
+	if !hideActor {
+		return event
+	}
+
+	view := *event
+	view.ActorID = ""
+	if event.Type == EventTicketDeleted {
+		var payload struct {
+			Ticket *Ticket `json:"ticket"`
+		}
+		if err := json.Unmarshal(event.Payload, &payload); err == nil && payload.Ticket != nil {
+			if data, err := json.Marshal(map[string]any{"ticket": r.TicketView(payload.Ticket, viewerID)}); err == nil {
+				view.Payload = data
+			}
+		}
+	}
+	return &view
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// applyVote adds a stored vote to the counts derived from it: the ticket's
+// votes and voter list and the voter's used votes
+func (r *Room) applyVote(vote *Vote) {
+	t, ok := r.Tickets[vote.TicketID]
+	if !ok {
+		return
+	}
+	t.Votes += vote.Weight
+	for i := 0; i < vote.Weight; i++ {
+		t.VoterIDs = append(t.VoterIDs, vote.UserID)
+	}
+	if p, ok := r.Participants[vote.UserID]; ok {
+		p.VotesUsed += vote.Weight
+	}
+}
+
+// ticketVotes returns the votes carried by a ticket's voter list, one per voter
+// in order of their first vote
+func ticketVotes(roomID string, ticket *Ticket, createdAt time.Time) []*Vote {
+	var votes []*Vote
+	byUser := make(map[string]*Vote)
+	for _, userID := range ticket.VoterIDs {
+		if v, ok := byUser[userID]; ok {
+			v.Weight++
+			continue
+		}
+		v := &Vote{RoomID: roomID, TicketID: ticket.ID, UserID: userID, Weight: 1, CreatedAt: createdAt}
+		byUser[userID] = v
+		votes = append(votes, v)
+	}
+	return votes
+}
+
+// restoredVotes returns the votes carried by a restored ticket's voter list.
+// Voters who had voted on the ticket before it was deleted keep the time of
+// that vote; others get createdAt.
+func restoredVotes(roomID string, restored *RestoredTicket, createdAt time.Time) []*Vote {
+	castAt := make(map[string]time.Time, len(restored.Votes))
+	for _, v := range restored.Votes {
+		castAt[v.UserID] = v.CreatedAt
+	}
+	votes := ticketVotes(roomID, restored.Ticket, createdAt)
+	for _, v := range votes {
+		if at, ok := castAt[v.UserID]; ok {
+			v.CreatedAt = at
+		}
+	}
+	return votes
+}
+
+// roomVotes returns the votes carried by the voter lists of all tickets in a room
+func roomVotes(room *Room) []*Vote {
+	tickets := make([]*Ticket, 0, len(room.Tickets))
+	for _, t := range room.Tickets {
+		tickets = append(tickets, t)
+	}
+	sort.Slice(tickets, func(i, j int) bool {
+		return tickets[i].CreatedAt.Before(tickets[j].CreatedAt)
+	})
+
+	var votes []*Vote
+	now := time.Now()
+	for _, t := range tickets {
+		votes = append(votes, ticketVotes(room.ID, t, now)...)
+	}
+	return votes
+}
+
+// clearVotes resets all vote counts derived from the votes table
+func (r *Room) clearVotes() {
+	for _, t := range r.Tickets {
+		t.Votes = 0
+		t.VoterIDs = []string{}
+	}
+	for _, p := range r.Participants {
+		p.VotesUsed = 0
+	}
+	for _, p := range r.PendingParticipants {
+		p.VotesUsed = 0
+	}
+}
@@ -0,0 +1,165 @@
+package websocket
+
+import (
+	"sync"
+
+	"github.com/Armatorix/GoRetro/internal/models"
+)
+
+// actorTask is a unit of work run on a room actor's goroutine
+type actorTask func(a *roomActor)
+
+// roomActor serializes all work on a single room. Tasks are queued and run
+// one at a time on the actor's own goroutine, which is also the only place
+// the actor's copy of the room is read or replaced. The actor retires once
+// its queue is empty and no local clients are left in the room.
+type roomActor struct {
+	hub    *Hub
+	roomID string
+
+	// room is the authoritative in-memory state, owned by the actor goroutine.
+	// nil means it has to be (re)loaded from the store before use.
+	room *models.Room
+
+	mu      sync.Mutex
+	queue   []actorTask
+	stopped bool
+	wakeup  chan struct{}
+}
+
+func newRoomActor(hub *Hub, roomID string) *roomActor {
+	return &roomActor{
+		hub:    hub,
+		roomID: roomID,
+		wakeup: make(chan struct{}, 1),
+	}
+}
+
+// enqueue adds a task to the queue. It returns false if the actor has
+// already retired, in which case the caller must use a new actor.
+func (a *roomActor) enqueue(task actorTask) bool {
+	a.mu.Lock()
+	if a.stopped {
+		a.mu.Unlock()
+		return false
+	}
+	a.queue = append(a.queue, task)
+	a.mu.Unlock()
+
+	a.wake()
+	return true
+}
+
+// wake nudges the actor goroutine to drain its queue and check whether it can retire
+func (a *roomActor) wake() {
+	select {
+	case a.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+// next pops the oldest queued task
+func (a *roomActor) next() (actorTask, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.queue) == 0 {
+		return nil, false
+	}
+	task := a.queue[0]
+	a.queue[0] = nil
+	a.queue = a.queue[1:]
+	return task, true
+}
+
+// run is the actor's main loop
+func (a *roomActor) run() {
+	for range a.wakeup {
+		for {
+			task, ok := a.next()
+			if !ok {
+				break
+			}
+			task(a)
+		}
+		if a.hub.retireActor(a) {
+			return
+		}
+	}
+}
+
+// current returns the actor's room, loading it from the store if needed.
+// It returns nil if the room no longer exists.
+func (a *roomActor) current() *models.Room {
+	if a.room == nil {
+		if room, ok := a.hub.store.Get(a.roomID); ok {
+			a.room = room
+			a.hub.armTimer(room)
+		}
+	}
+	return a.room
+}
+
+// invalidate drops the in-memory state so the next task reloads it from the store
+func (a *roomActor) invalidate() {
+	a.room = nil
+}
+
+// apply runs fn against the actor's room, retrying against a reloaded copy on
+// version conflicts. If fn fails the in-memory state may no longer match the
+// store, so it is dropped and reloaded by the next task.
+func (a *roomActor) apply(fn func(room *models.Room) error) error {
+	room := a.current()
+	if room == nil {
+		return models.ErrRoomNotFound
+	}
+
+	room, err := a.hub.retryOnConflict(room, fn)
+	if err != nil {
+		a.invalidate()
+		return err
+	}
+	a.room = room
+	a.hub.armTimer(room)
+	return nil
+}
+
+// enqueue queues a task on the room's actor, starting one if needed
+func (h *Hub) enqueue(roomID string, task actorTask) {
+	for !h.actorFor(roomID).enqueue(task) {
+		// The actor retired between lookup and enqueue; the next lookup starts a new one
+	}
+}
+
+// actorFor returns the running actor for a room, starting one if needed
+func (h *Hub) actorFor(roomID string) *roomActor {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	a, ok := h.actors[roomID]
+	if !ok {
+		a = newRoomActor(h, roomID)
+		h.actors[roomID] = a
+		go a.run()
+	}
+	return a
+}
+
+// retireActor stops an actor whose queue is empty and whose room has no
+// local clients left. It reports whether the actor was stopped.
+func (h *Hub) retireActor(a *roomActor) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.rooms[a.roomID]) > 0 {
+		return false
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.queue) > 0 {
+		return false
+	}
+	a.stopped = true
+	if h.actors[a.roomID] == a {
+		delete(h.actors, a.roomID)
+	}
+	return true
+}
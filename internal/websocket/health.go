@@ -0,0 +1,57 @@
+package websocket
+
+import (
+	"github.com/Armatorix/GoRetro/internal/models"
+)
+
+// handleRateHealth records a participant's rating of a health dimension.
+// Ratings can be changed for as long as the phase allows rating.
+func (h *Hub) handleRateHealth(client *Client, room *models.Room, payload map[string]any) error {
+	if !room.IsHealthCheck() {
+		h.sendError(client, "This room has no health check")
+		return nil
+	}
+	if !room.Allows(models.ActivityRateHealth) {
+		h.sendError(client, "Health can't be rated in the current phase")
+		return nil
+	}
+
+	dimensionID, _ := payload["dimension_id"].(string)
+	color, _ := payload["color"].(string)
+	trend, _ := payload["trend"].(string)
+	rating := models.HealthRating{Color: models.HealthColor(color), Trend: models.HealthTrend(trend)}
+
+	if err := room.RateHealth(client.ID, dimensionID, rating); err != nil {
+		h.sendError(client, "Invalid rating: "+err.Error())
+		return nil
+	}
+
+	// Persist to database
+	if err := h.store.RateHealth(room, client.ID, dimensionID, rating); err != nil {
+		return h.persistError(client, err, "Failed to save rating")
+	}
+	h.RecordEvent(room.ID, models.EventHealthRated, client.ID, map[string]any{
+		"dimension_id": dimensionID,
+		"color":        rating.Color,
+		"trend":        rating.Trend,
+	})
+
+	h.broadcastHealthCheck(room)
+
+	return nil
+}
+
+// broadcastHealthCheck sends approved participants the health check results
+// along with their own ratings
+func (h *Hub) broadcastHealthCheck(room *models.Room) {
+	h.broadcastToEachParticipant(room, func(viewerID string) (Message, bool) {
+		room.RLock()
+		defer room.RUnlock()
+		return Message{
+			Type: MsgHealthUpdated,
+			Payload: map[string]any{
+				"health_check": room.HealthCheckView(viewerID),
+			},
+		}, true
+	})
+}
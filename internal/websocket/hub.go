@@ -2,6 +2,7 @@ package websocket
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"sync"
@@ -16,18 +17,25 @@ import (
 type Hub struct {
 	// Room ID -> Client ID -> Client
 	rooms          map[string]map[string]*Client
-	store          *models.RoomStore
+	actors         map[string]*roomActor
+	store          models.Store
 	register       chan *Client
 	unregister     chan *Client
 	mu             sync.RWMutex
 	redisPubSub    *RedisPubSub
 	chatCompletion *chatcompletion.Service
+
+	// Room ID -> scheduled expiry of the room's running timer
+	timers   map[string]*timerExpiry
+	timersMu sync.Mutex
 }
 
 // NewHub creates a new Hub
-func NewHub(store *models.RoomStore) *Hub {
+func NewHub(store models.Store) *Hub {
 	return &Hub{
 		rooms:      make(map[string]map[string]*Client),
+		actors:     make(map[string]*roomActor),
+		timers:     make(map[string]*timerExpiry),
 		store:      store,
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
@@ -54,8 +62,15 @@ func (h *Hub) Run() {
 				h.rooms[client.RoomID] = make(map[string]*Client)
 			}
 			h.rooms[client.RoomID][client.ID] = client
+			actor := h.actors[client.RoomID]
 			h.mu.Unlock()
 
+			// The client joined through the HTTP handlers, so the actor's copy
+			// of the room may not know about it yet
+			if actor != nil {
+				actor.enqueue(func(a *roomActor) { a.invalidate() })
+			}
+
 		case client := <-h.unregister:
 			h.mu.Lock()
 			if clients, ok := h.rooms[client.RoomID]; ok {
@@ -66,7 +81,13 @@ func (h *Hub) Run() {
 					}
 				}
 			}
+			actor := h.actors[client.RoomID]
 			h.mu.Unlock()
+
+			// Let the actor retire if this was the room's last client
+			if actor != nil {
+				actor.wake()
+			}
 		}
 	}
 }
@@ -199,7 +220,11 @@ func (h *Hub) SendToClient(roomID, clientID string, msg []byte) {
 	}
 }
 
-// HandleMessage processes incoming WebSocket messages
+// maxConflictRetries is how many times a command is applied before giving up on version conflicts
+const maxConflictRetries = 3
+
+// HandleMessage queues an incoming WebSocket message on the room's actor,
+// which applies the commands of a room one at a time in arrival order
 func (h *Hub) HandleMessage(client *Client, msg []byte) {
 	var message Message
 	if err := json.Unmarshal(msg, &message); err != nil {
@@ -207,73 +232,202 @@ func (h *Hub) HandleMessage(client *Client, msg []byte) {
 		return
 	}
 
-	room, ok := h.store.Get(client.RoomID)
-	if !ok {
-		h.sendError(client, "Room not found")
-		return
-	}
+	h.enqueue(client.RoomID, func(a *roomActor) {
+		// Participants can be approved on another instance; reload before
+		// turning a client away based on a stale copy of the room
+		if room := a.current(); room != nil {
+			if _, ok := room.GetParticipant(client.ID); !ok {
+				a.invalidate()
+			}
+		}
+
+		err := a.apply(func(room *models.Room) error {
+			return h.dispatch(client, room, message)
+		})
+		switch {
+		case errors.Is(err, models.ErrVersionConflict):
+			h.sendConflictError(client)
+		case errors.Is(err, models.ErrRoomNotFound):
+			h.sendError(client, "Room not found")
+		}
+	})
+}
 
+// dispatch applies a single command to the room. It returns
+// models.ErrVersionConflict if the room changed since it was loaded and
+// errStoreWrite if persisting failed; all failures except conflicts are
+// reported to the client directly.
+func (h *Hub) dispatch(client *Client, room *models.Room, message Message) error {
 	// Check if user is approved (not pending) before allowing any actions
 	_, isApproved := room.GetParticipant(client.ID)
 	if !isApproved {
 		h.sendError(client, "You must be approved to perform actions")
-		return
+		return nil
 	}
 
 	switch message.Type {
 	case MsgAddTicket:
-		h.handleAddTicket(client, room, message.Payload)
+		return h.handleAddTicket(client, room, message.Payload)
 	case MsgEditTicket:
-		h.handleEditTicket(client, room, message.Payload)
+		return h.handleEditTicket(client, room, message.Payload)
 	case MsgDeleteTicket:
-		h.handleDeleteTicket(client, room, message.Payload)
+		return h.handleDeleteTicket(client, room, message.Payload)
 	case MsgVote:
-		h.handleVote(client, room, message.Payload)
+		return h.handleVote(client, room, message.Payload)
 	case MsgUnvote:
-		h.handleUnvote(client, room, message.Payload)
+		return h.handleUnvote(client, room, message.Payload)
+	case MsgAddReaction:
+		return h.handleAddReaction(client, room, message.Payload)
+	case MsgRemoveReaction:
+		return h.handleRemoveReaction(client, room, message.Payload)
+	case MsgAddComment:
+		return h.handleAddComment(client, room, message.Payload)
+	case MsgEditComment:
+		return h.handleEditComment(client, room, message.Payload)
+	case MsgDeleteComment:
+		return h.handleDeleteComment(client, room, message.Payload)
 	case MsgAddAction:
-		h.handleAddAction(client, room, message.Payload)
+		return h.handleAddAction(client, room, message.Payload)
+	case MsgUpdateAction:
+		return h.handleUpdateAction(client, room, message.Payload)
+	case MsgReviewAction:
+		return h.handleReviewAction(client, room, message.Payload)
 	case MsgDeleteAction:
-		h.handleDeleteAction(client, room, message.Payload)
+		return h.handleDeleteAction(client, room, message.Payload)
 	case MsgMarkCovered:
-		h.handleMarkCovered(client, room, message.Payload)
+		return h.handleMarkCovered(client, room, message.Payload)
 	case MsgSetPhase:
-		h.handleSetPhase(client, room, message.Payload)
+		return h.handleSetPhase(client, room, message.Payload)
+	case MsgSetPhases:
+		return h.handleSetPhases(client, room, message.Payload)
+	case MsgStartTimer:
+		return h.handleStartTimer(client, room, message.Payload)
+	case MsgPauseTimer:
+		return h.handlePauseTimer(client, room, message.Payload)
+	case MsgExtendTimer:
+		return h.handleExtendTimer(client, room, message.Payload)
+	case MsgStopTimer:
+		return h.handleStopTimer(client, room, message.Payload)
 	case MsgSetRole:
-		h.handleSetRole(client, room, message.Payload)
+		return h.handleSetRole(client, room, message.Payload)
 	case MsgRemoveUser:
-		h.handleRemoveUser(client, room, message.Payload)
+		return h.handleRemoveUser(client, room, message.Payload)
 	case MsgApproveParticipant:
-		h.handleApproveParticipant(client, room, message.Payload)
+		return h.handleApproveParticipant(client, room, message.Payload)
 	case MsgRejectParticipant:
-		h.handleRejectParticipant(client, room, message.Payload)
+		return h.handleRejectParticipant(client, room, message.Payload)
 	case MsgSetAutoApprove:
-		h.handleSetAutoApprove(client, room, message.Payload)
+		return h.handleSetAutoApprove(client, room, message.Payload)
+	case MsgSetMaxVotesPerTicket:
+		return h.handleSetMaxVotesPerTicket(client, room, message.Payload)
+	case MsgSetHiddenVotes:
+		return h.handleSetHiddenVotes(client, room, message.Payload)
+	case MsgRevealVotes:
+		return h.handleRevealVotes(client, room, message.Payload)
+	case MsgSetAnonymousTickets:
+		return h.handleSetAnonymousTickets(client, room, message.Payload)
+	case MsgSetBlindTickets:
+		return h.handleSetBlindTickets(client, room, message.Payload)
+	case MsgRevealTickets:
+		return h.handleRevealTickets(client, room, message.Payload)
+	case MsgSetColumns:
+		return h.handleSetColumns(client, room, message.Payload)
+	case MsgAnswerPoll:
+		return h.handleAnswerPoll(client, room, message.Payload)
+	case MsgClosePoll:
+		return h.handleClosePoll(client, room, message.Payload)
+	case MsgRateHealth:
+		return h.handleRateHealth(client, room, message.Payload)
 	case MsgAutoMergeTickets:
-		h.handleAutoMergeTickets(client, room, message.Payload)
+		return h.handleAutoMergeTickets(client, room, message.Payload)
 	case MsgAutoProposeActions:
-		h.handleAutoProposeActions(client, room, message.Payload)
+		return h.handleAutoProposeActions(client, room, message.Payload)
+	case MsgUndo:
+		return h.handleUndo(client, room, message.Payload)
 	default:
 		h.sendError(client, "Unknown message type")
+		return nil
+	}
+}
+
+// retryOnConflict calls apply with room and, while it fails with a version
+// conflict, again with a freshly loaded copy. apply must be safe to re-run.
+// It returns the copy of the room the last attempt was applied to.
+func (h *Hub) retryOnConflict(room *models.Room, apply func(room *models.Room) error) (*models.Room, error) {
+	for attempt := 1; ; attempt++ {
+		err := apply(room)
+		if !errors.Is(err, models.ErrVersionConflict) || attempt == maxConflictRetries {
+			return room, err
+		}
+		log.Printf("Version conflict in room %s, retrying (attempt %d)", room.ID, attempt)
+
+		fresh, ok := h.store.Get(room.ID)
+		if !ok {
+			return room, models.ErrRoomNotFound
+		}
+		room = fresh
+	}
+}
+
+// errStoreWrite is returned by command handlers after a failed store write
+// has been reported to the client
+var errStoreWrite = errors.New("store write failed")
+
+// persistError reports a failed store write to the client, except for version
+// conflicts which are returned as-is so the command can be retried
+func (h *Hub) persistError(client *Client, err error, message string) error {
+	if errors.Is(err, models.ErrVersionConflict) {
+		return err
+	}
+	log.Printf("Store write failed: %v", err)
+	h.sendError(client, message)
+	return errStoreWrite
+}
+
+// sameTicketID reports whether two optional ticket references point to the same ticket
+func sameTicketID(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// RecordEvent appends a change to the room's event log. The change itself has
+// already been persisted, so failures are logged rather than reported.
+func (h *Hub) RecordEvent(roomID string, eventType models.EventType, actorID string, payload map[string]any) {
+	event, err := models.NewRoomEvent(roomID, eventType, actorID, payload)
+	if err == nil {
+		err = h.store.AppendEvent(event)
+	}
+	if err != nil {
+		log.Printf("Failed to record %s event in room %s: %v", eventType, roomID, err)
 	}
 }
 
-func (h *Hub) handleAddTicket(client *Client, room *models.Room, payload map[string]any) {
-	if room.Phase != models.PhaseTicketing {
+func (h *Hub) handleAddTicket(client *Client, room *models.Room, payload map[string]any) error {
+	if !room.Allows(models.ActivityAddTickets) {
 		h.sendError(client, "Can only add tickets during ticketing phase")
-		return
+		return nil
 	}
 
 	content, ok := payload["content"].(string)
 	if !ok || content == "" {
 		h.sendError(client, "Content is required")
-		return
+		return nil
+	}
+
+	requestedColumnID, _ := payload["column_id"].(string)
+	columnID, ok := room.ResolveColumn(requestedColumnID)
+	if !ok {
+		h.sendError(client, "Column not found")
+		return nil
 	}
 
 	ticket := &models.Ticket{
 		ID:        uuid.New().String(),
 		Content:   content,
 		AuthorID:  client.ID,
+		ColumnID:  columnID,
 		Votes:     0,
 		VoterIDs:  []string{},
 		CreatedAt: time.Now(),
@@ -282,37 +436,48 @@ func (h *Hub) handleAddTicket(client *Client, room *models.Room, payload map[str
 	room.AddTicket(ticket)
 
 	// Persist to database
-	if err := h.store.Update(room); err != nil {
-		h.sendError(client, "Failed to save ticket")
-		return
+	if err := h.store.AddTicket(room, ticket); err != nil {
+		return h.persistError(client, err, "Failed to save ticket")
 	}
+	h.RecordEvent(room.ID, models.EventTicketAdded, client.ID, map[string]any{
+		"ticket_id": ticket.ID,
+		"content":   content,
+		"column_id": columnID,
+	})
 
-	response := Message{
-		Type: MsgTicketAdded,
-		Payload: map[string]any{
-			"ticket": ticket,
-		},
-	}
-	responseBytes, _ := json.Marshal(response)
-	h.BroadcastToApprovedParticipants(room.ID, responseBytes)
+	h.broadcastTicket(room, MsgTicketAdded, ticket)
+
+	return nil
 }
 
-func (h *Hub) handleEditTicket(client *Client, room *models.Room, payload map[string]any) {
+func (h *Hub) handleEditTicket(client *Client, room *models.Room, payload map[string]any) error {
 	ticketID, _ := payload["ticket_id"].(string)
 	content, hasContent := payload["content"].(string)
 
 	ticket, ok := room.GetTicket(ticketID)
 	if !ok {
 		h.sendError(client, "Ticket not found")
-		return
+		return nil
 	}
 
 	// Only author or moderator can edit their ticket
 	if ticket.AuthorID != client.ID && !room.IsModeratorOrOwner(client.ID) {
 		h.sendError(client, "Not authorized to edit this ticket")
-		return
+		return nil
+	}
+
+	columnID, hasColumn := payload["column_id"].(string)
+	if hasColumn {
+		if _, ok := room.GetColumn(columnID); !ok {
+			h.sendError(client, "Column not found")
+			return nil
+		}
 	}
 
+	previousContent := ticket.Content
+	previousColumnID := ticket.ColumnID
+	previousParentID := ticket.DeduplicationTicketID
+
 	room.Lock()
 
 	// Update content if provided
@@ -320,6 +485,11 @@ func (h *Hub) handleEditTicket(client *Client, room *models.Room, payload map[st
 		ticket.Content = content
 	}
 
+	// Move to another column if provided
+	if hasColumn {
+		ticket.ColumnID = columnID
+	}
+
 	// Update deduplication_ticket_id if provided in payload
 	if deduplicationID, exists := payload["deduplication_ticket_id"]; exists {
 		if deduplicationID == nil {
@@ -334,43 +504,87 @@ func (h *Hub) handleEditTicket(client *Client, room *models.Room, payload map[st
 	room.Unlock()
 
 	// Persist to database
-	if err := h.store.Update(room); err != nil {
-		h.sendError(client, "Failed to update ticket")
-		return
+	if err := h.store.UpdateTicket(room, ticket); err != nil {
+		return h.persistError(client, err, "Failed to update ticket")
+	}
+	if hasContent && content != previousContent {
+		h.RecordEvent(room.ID, models.EventTicketEdited, client.ID, map[string]any{
+			"ticket_id":        ticket.ID,
+			"previous_content": previousContent,
+			"content":          content,
+		})
+	}
+	if hasColumn && columnID != previousColumnID {
+		h.RecordEvent(room.ID, models.EventTicketMoved, client.ID, map[string]any{
+			"ticket_id":          ticket.ID,
+			"previous_column_id": previousColumnID,
+			"column_id":          columnID,
+		})
+	}
+	if parentID := ticket.DeduplicationTicketID; !sameTicketID(parentID, previousParentID) {
+		if parentID != nil {
+			h.RecordEvent(room.ID, models.EventTicketMerged, client.ID, map[string]any{
+				"ticket_id":                 ticket.ID,
+				"parent_ticket_id":          *parentID,
+				"previous_parent_ticket_id": previousParentID,
+				"moderator":                 room.IsModeratorOrOwner(client.ID),
+			})
+		} else {
+			h.RecordEvent(room.ID, models.EventTicketUnmerged, client.ID, map[string]any{
+				"ticket_id":                 ticket.ID,
+				"previous_parent_ticket_id": *previousParentID,
+				"moderator":                 room.IsModeratorOrOwner(client.ID),
+			})
+		}
 	}
 
-	response := Message{
-		Type: MsgTicketUpdated,
-		Payload: map[string]any{
-			"ticket": ticket,
-		},
-	}
-	responseBytes, _ := json.Marshal(response)
-	h.BroadcastToApprovedParticipants(room.ID, responseBytes)
+	h.broadcastTicket(room, MsgTicketUpdated, ticket)
+
+	return nil
 }
 
-func (h *Hub) handleDeleteTicket(client *Client, room *models.Room, payload map[string]any) {
+func (h *Hub) handleDeleteTicket(client *Client, room *models.Room, payload map[string]any) error {
 	ticketID, _ := payload["ticket_id"].(string)
 
 	ticket, ok := room.GetTicket(ticketID)
 	if !ok {
 		h.sendError(client, "Ticket not found")
-		return
+		return nil
 	}
 
 	// Only author or moderator can delete
 	if ticket.AuthorID != client.ID && !room.IsModeratorOrOwner(client.ID) {
 		h.sendError(client, "Not authorized to delete this ticket")
-		return
+		return nil
+	}
+
+	// The ticket's votes are kept in its event so an undo can restore them
+	// with the time they were cast
+	var votes []*models.Vote
+	if len(ticket.VoterIDs) > 0 {
+		roomVotes, err := h.store.ListVotes(room.ID)
+		if err != nil {
+			log.Printf("Failed to load votes of room %s: %v", room.ID, err)
+		}
+		for _, vote := range roomVotes {
+			if vote.TicketID == ticketID {
+				votes = append(votes, vote)
+			}
+		}
 	}
 
+	deleted := ticket.Clone()
 	room.RemoveTicket(ticketID)
 
 	// Persist to database
-	if err := h.store.Update(room); err != nil {
-		h.sendError(client, "Failed to delete ticket")
-		return
+	if err := h.store.DeleteTicket(room, ticketID); err != nil {
+		return h.persistError(client, err, "Failed to delete ticket")
 	}
+	h.RecordEvent(room.ID, models.EventTicketDeleted, client.ID, map[string]any{
+		"ticket":    deleted,
+		"votes":     votes,
+		"moderator": room.IsModeratorOrOwner(client.ID),
+	})
 
 	response := Message{
 		Type: MsgTicketDeleted,
@@ -380,102 +594,271 @@ func (h *Hub) handleDeleteTicket(client *Client, room *models.Room, payload map[
 	}
 	responseBytes, _ := json.Marshal(response)
 	h.BroadcastToApprovedParticipants(room.ID, responseBytes)
+
+	if room.TicketsHidden() {
+		h.broadcastToEachParticipant(room, func(viewerID string) (Message, bool) {
+			room.RLock()
+			defer room.RUnlock()
+			return hiddenTicketsMessage(room, viewerID)
+		})
+	}
+
+	return nil
 }
 
-func (h *Hub) handleVote(client *Client, room *models.Room, payload map[string]any) {
-	if room.Phase != models.PhaseVoting {
+func (h *Hub) handleVote(client *Client, room *models.Room, payload map[string]any) error {
+	if !room.Allows(models.ActivityVote) {
 		h.sendError(client, "Can only vote during voting phase")
-		return
+		return nil
 	}
 
 	ticketID, _ := payload["ticket_id"].(string)
 
 	if !room.Vote(client.ID, ticketID) {
 		h.sendError(client, "Could not vote (no votes left or already voted)")
-		return
+		return nil
 	}
 
 	// Persist to database
-	if err := h.store.Update(room); err != nil {
-		h.sendError(client, "Failed to save vote")
-		return
+	if err := h.store.AddVote(room, ticketID, client.ID); err != nil {
+		return h.persistError(client, err, "Failed to save vote")
 	}
+	h.RecordEvent(room.ID, models.EventVoteAdded, client.ID, map[string]any{
+		"ticket_id": ticketID,
+	})
 
 	ticket, _ := room.GetTicket(ticketID)
-	participant, _ := room.GetParticipant(client.ID)
+	h.broadcastVoteUpdate(room, ticket, client.ID)
 
-	response := Message{
-		Type: MsgVoteUpdated,
-		Payload: map[string]any{
-			"ticket_id":  ticketID,
-			"votes":      ticket.Votes,
-			"voter_ids":  ticket.VoterIDs,
-			"user_id":    client.ID,
-			"votes_used": participant.VotesUsed,
-		},
-	}
-	responseBytes, _ := json.Marshal(response)
-	h.BroadcastToApprovedParticipants(room.ID, responseBytes)
+	return nil
 }
 
-func (h *Hub) handleUnvote(client *Client, room *models.Room, payload map[string]any) {
-	if room.Phase != models.PhaseVoting {
+func (h *Hub) handleUnvote(client *Client, room *models.Room, payload map[string]any) error {
+	if !room.Allows(models.ActivityVote) {
 		h.sendError(client, "Can only unvote during voting phase")
-		return
+		return nil
 	}
 
 	ticketID, _ := payload["ticket_id"].(string)
 
 	if !room.Unvote(client.ID, ticketID) {
 		h.sendError(client, "Could not unvote")
-		return
+		return nil
 	}
 
 	// Persist to database
-	if err := h.store.Update(room); err != nil {
-		h.sendError(client, "Failed to save unvote")
-		return
+	if err := h.store.RemoveVote(room, ticketID, client.ID); err != nil {
+		return h.persistError(client, err, "Failed to save unvote")
 	}
+	h.RecordEvent(room.ID, models.EventVoteRemoved, client.ID, map[string]any{
+		"ticket_id": ticketID,
+	})
 
 	ticket, _ := room.GetTicket(ticketID)
-	participant, _ := room.GetParticipant(client.ID)
+	h.broadcastVoteUpdate(room, ticket, client.ID)
 
-	response := Message{
-		Type: MsgVoteUpdated,
-		Payload: map[string]any{
-			"ticket_id":  ticketID,
-			"votes":      ticket.Votes,
-			"voter_ids":  ticket.VoterIDs,
-			"user_id":    client.ID,
-			"votes_used": participant.VotesUsed,
-		},
+	return nil
+}
+
+func (h *Hub) handleAddReaction(client *Client, room *models.Room, payload map[string]any) error {
+	if !room.Allows(models.ActivityReact) {
+		h.sendError(client, "Reactions can't be added in the current phase")
+		return nil
 	}
-	responseBytes, _ := json.Marshal(response)
-	h.BroadcastToApprovedParticipants(room.ID, responseBytes)
+
+	ticketID, _ := payload["ticket_id"].(string)
+	emoji, _ := payload["emoji"].(string)
+	if err := models.ValidateReaction(emoji); err != nil {
+		h.sendError(client, "Invalid reaction")
+		return nil
+	}
+	if _, ok := room.VisibleTicket(ticketID, client.ID); !ok {
+		h.sendError(client, "Ticket not found")
+		return nil
+	}
+
+	if !room.AddReaction(ticketID, client.ID, emoji) {
+		h.sendError(client, "Already reacted with this emoji")
+		return nil
+	}
+
+	// Persist to database
+	if err := h.store.AddReaction(room, ticketID, client.ID, emoji); err != nil {
+		return h.persistError(client, err, "Failed to save reaction")
+	}
+	h.RecordEvent(room.ID, models.EventReactionAdded, client.ID, map[string]any{
+		"ticket_id": ticketID,
+		"emoji":     emoji,
+	})
+
+	h.broadcastReactionUpdate(room, ticketID, client.ID, emoji)
+
+	return nil
 }
 
-func (h *Hub) handleAddAction(client *Client, room *models.Room, payload map[string]any) {
-	if room.Phase != models.PhaseDiscussion {
-		h.sendError(client, "Can only add actions during discussion phase")
-		return
+func (h *Hub) handleRemoveReaction(client *Client, room *models.Room, payload map[string]any) error {
+	if !room.Allows(models.ActivityReact) {
+		h.sendError(client, "Reactions can't be removed in the current phase")
+		return nil
+	}
+
+	ticketID, _ := payload["ticket_id"].(string)
+	emoji, _ := payload["emoji"].(string)
+
+	if !room.RemoveReaction(ticketID, client.ID, emoji) {
+		h.sendError(client, "Reaction not found")
+		return nil
+	}
+
+	// Persist to database
+	if err := h.store.RemoveReaction(room, ticketID, client.ID, emoji); err != nil {
+		return h.persistError(client, err, "Failed to remove reaction")
+	}
+	h.RecordEvent(room.ID, models.EventReactionRemoved, client.ID, map[string]any{
+		"ticket_id": ticketID,
+		"emoji":     emoji,
+	})
+
+	h.broadcastReactionUpdate(room, ticketID, client.ID, emoji)
+
+	return nil
+}
+
+func (h *Hub) handleAddComment(client *Client, room *models.Room, payload map[string]any) error {
+	ticketID, _ := payload["ticket_id"].(string)
+	rawContent, _ := payload["content"].(string)
+	content, err := models.NormalizeComment(rawContent)
+	if err != nil {
+		h.sendError(client, fmt.Sprintf("Invalid comment: %v", err))
+		return nil
+	}
+	if _, ok := room.VisibleTicket(ticketID, client.ID); !ok {
+		h.sendError(client, "Ticket not found")
+		return nil
+	}
+
+	comment := &models.Comment{
+		ID:        uuid.New().String(),
+		TicketID:  ticketID,
+		AuthorID:  client.ID,
+		Content:   content,
+		CreatedAt: time.Now(),
+	}
+	room.AddComment(comment)
+
+	// Persist to database
+	if err := h.store.AddComment(room, comment); err != nil {
+		return h.persistError(client, err, "Failed to save comment")
+	}
+	h.RecordEvent(room.ID, models.EventCommentAdded, client.ID, map[string]any{
+		"ticket_id":  ticketID,
+		"comment_id": comment.ID,
+		"content":    content,
+	})
+
+	h.broadcastComment(room, MsgCommentAdded, comment)
+
+	return nil
+}
+
+func (h *Hub) handleEditComment(client *Client, room *models.Room, payload map[string]any) error {
+	ticketID, _ := payload["ticket_id"].(string)
+	commentID, _ := payload["comment_id"].(string)
+
+	existing, ok := room.GetComment(ticketID, commentID)
+	if _, visible := room.VisibleTicket(ticketID, client.ID); !ok || !visible {
+		h.sendError(client, "Comment not found")
+		return nil
+	}
+
+	// Only the author or a moderator can edit a comment
+	if existing.AuthorID != client.ID && !room.IsModeratorOrOwner(client.ID) {
+		h.sendError(client, "Not authorized to edit this comment")
+		return nil
+	}
+
+	rawContent, _ := payload["content"].(string)
+	content, err := models.NormalizeComment(rawContent)
+	if err != nil {
+		h.sendError(client, fmt.Sprintf("Invalid comment: %v", err))
+		return nil
+	}
+
+	edited, _ := room.EditComment(ticketID, commentID, content, time.Now())
+
+	// Persist to database
+	if err := h.store.UpdateComment(room, edited); err != nil {
+		return h.persistError(client, err, "Failed to update comment")
+	}
+	h.RecordEvent(room.ID, models.EventCommentEdited, client.ID, map[string]any{
+		"ticket_id":        ticketID,
+		"comment_id":       commentID,
+		"previous_content": existing.Content,
+		"content":          content,
+	})
+
+	h.broadcastComment(room, MsgCommentUpdated, edited)
+
+	return nil
+}
+
+func (h *Hub) handleDeleteComment(client *Client, room *models.Room, payload map[string]any) error {
+	ticketID, _ := payload["ticket_id"].(string)
+	commentID, _ := payload["comment_id"].(string)
+
+	existing, ok := room.GetComment(ticketID, commentID)
+	if _, visible := room.VisibleTicket(ticketID, client.ID); !ok || !visible {
+		h.sendError(client, "Comment not found")
+		return nil
+	}
+
+	// Only the author or a moderator can delete a comment
+	if existing.AuthorID != client.ID && !room.IsModeratorOrOwner(client.ID) {
+		h.sendError(client, "Not authorized to delete this comment")
+		return nil
+	}
+
+	room.RemoveComment(ticketID, commentID)
+
+	// Persist to database
+	if err := h.store.DeleteComment(room, ticketID, commentID); err != nil {
+		return h.persistError(client, err, "Failed to delete comment")
+	}
+	h.RecordEvent(room.ID, models.EventCommentDeleted, client.ID, map[string]any{
+		"ticket_id":  ticketID,
+		"comment_id": commentID,
+		"content":    existing.Content,
+	})
+
+	h.broadcastComment(room, MsgCommentDeleted, existing)
+
+	return nil
+}
+
+func (h *Hub) handleAddAction(client *Client, room *models.Room, payload map[string]any) error {
+	if !room.Allows(models.ActivityManageActions) {
+		h.sendError(client, "Actions can't be added in the current phase")
+		return nil
 	}
 
 	if !room.IsModeratorOrOwner(client.ID) {
 		h.sendError(client, "Only moderators can add actions")
-		return
+		return nil
 	}
 
 	content, _ := payload["content"].(string)
 	ticketID, _ := payload["ticket_id"].(string)
+	assigneeIDs := stringList(payload["assignee_ids"])
 
-	// Handle assignee_ids as array
-	var assigneeIDs []string
-	if assigneeIDsRaw, ok := payload["assignee_ids"].([]interface{}); ok {
-		for _, id := range assigneeIDsRaw {
-			if idStr, ok := id.(string); ok {
-				assigneeIDs = append(assigneeIDs, idStr)
-			}
-		}
+	ownerID, _ := payload["owner_id"].(string)
+	if _, ok := room.GetParticipant(ownerID); ownerID != "" && !ok {
+		h.sendError(client, "Action owner must be a participant")
+		return nil
+	}
+	dueDate, err := parseDueDate(payload["due_date"])
+	if err != nil {
+		h.sendError(client, "Invalid due date")
+		return nil
 	}
 
 	action := &models.ActionTicket{
@@ -483,16 +866,21 @@ func (h *Hub) handleAddAction(client *Client, room *models.Room, payload map[str
 		Content:     content,
 		TicketID:    ticketID,
 		AssigneeIDs: assigneeIDs,
+		Status:      models.ActionOpen,
+		OwnerID:     ownerID,
+		DueDate:     dueDate,
 		CreatedAt:   time.Now(),
 	}
 
 	room.AddActionTicket(action)
 
 	// Persist to database
-	if err := h.store.Update(room); err != nil {
-		h.sendError(client, "Failed to save action")
-		return
+	if err := h.store.AddActionTicket(room, action); err != nil {
+		return h.persistError(client, err, "Failed to save action")
 	}
+	h.RecordEvent(room.ID, models.EventActionAdded, client.ID, map[string]any{
+		"action": action,
+	})
 
 	response := Message{
 		Type: MsgActionAdded,
@@ -502,38 +890,240 @@ func (h *Hub) handleAddAction(client *Client, room *models.Room, payload map[str
 	}
 	responseBytes, _ := json.Marshal(response)
 	h.BroadcastToApprovedParticipants(room.ID, responseBytes)
+
+	return nil
 }
 
-func (h *Hub) handleDeleteAction(client *Client, room *models.Room, payload map[string]any) {
-	if room.Phase != models.PhaseDiscussion {
-		h.sendError(client, "Can only delete actions during discussion phase")
-		return
+// handleUpdateAction changes an action item's content, assignees, owner, due
+// date or status. Unlike adding and deleting actions it works in any phase,
+// so actions can be followed up after the retrospective, and besides
+// moderators the action's owner and assignees may update it.
+func (h *Hub) handleUpdateAction(client *Client, room *models.Room, payload map[string]any) error {
+	actionID, _ := payload["action_id"].(string)
+	action, exists := room.GetActionTicket(actionID)
+	if !exists {
+		h.sendError(client, "Action not found")
+		return nil
+	}
+
+	if !action.IsAssigned(client.ID) && !room.IsModeratorOrOwner(client.ID) {
+		h.sendError(client, "Not authorized to update this action")
+		return nil
+	}
+
+	previousStatus := action.Status
+	updated := action.Clone()
+	if content, ok := payload["content"].(string); ok {
+		if content == "" {
+			h.sendError(client, "Content is required")
+			return nil
+		}
+		updated.Content = content
+	}
+	if _, ok := payload["assignee_ids"]; ok {
+		updated.AssigneeIDs = stringList(payload["assignee_ids"])
+	}
+	if ownerID, ok := payload["owner_id"].(string); ok {
+		if _, ok := room.GetParticipant(ownerID); ownerID != "" && !ok {
+			h.sendError(client, "Action owner must be a participant")
+			return nil
+		}
+		updated.OwnerID = ownerID
+	}
+	if raw, ok := payload["due_date"]; ok {
+		dueDate, err := parseDueDate(raw)
+		if err != nil {
+			h.sendError(client, "Invalid due date")
+			return nil
+		}
+		updated.DueDate = dueDate
+	}
+	if raw, ok := payload["status"].(string); ok {
+		status := models.ActionStatus(raw)
+		if err := models.ValidateActionStatus(status); err != nil {
+			h.sendError(client, fmt.Sprintf("Invalid status: %v", err))
+			return nil
+		}
+		updated.SetStatus(status, client.ID, time.Now())
+	}
+
+	room.UpdateActionTicket(updated)
+
+	// Persist to database
+	if err := h.store.UpdateActionTicket(room, updated); err != nil {
+		return h.persistError(client, err, "Failed to update action")
+	}
+	h.RecordEvent(room.ID, models.EventActionUpdated, client.ID, map[string]any{
+		"action":          updated,
+		"previous_status": previousStatus,
+	})
+
+	h.broadcastActionUpdated(room.ID, updated)
+
+	return nil
+}
+
+// handleReviewAction records the outcome of reviewing an action carried over
+// from the previous room, here and on the action it came from
+func (h *Hub) handleReviewAction(client *Client, room *models.Room, payload map[string]any) error {
+	if !room.Allows(models.ActivityReviewActions) {
+		h.sendError(client, "Actions can't be reviewed in the current phase")
+		return nil
+	}
+
+	if !room.IsModeratorOrOwner(client.ID) {
+		h.sendError(client, "Only moderators can review actions")
+		return nil
+	}
+
+	actionID, _ := payload["action_id"].(string)
+	action, exists := room.GetActionTicket(actionID)
+	if !exists || action.SourceActionID == "" {
+		h.sendError(client, "Carried over action not found")
+		return nil
+	}
+	if action.ReviewOutcome != "" {
+		h.sendError(client, "Action was already reviewed")
+		return nil
+	}
+
+	raw, _ := payload["outcome"].(string)
+	outcome := models.ReviewOutcome(raw)
+	if err := models.ValidateReviewOutcome(outcome); err != nil {
+		h.sendError(client, fmt.Sprintf("Invalid outcome: %v", err))
+		return nil
+	}
+
+	updated := action.Clone()
+	updated.Review(outcome, client.ID, time.Now())
+	room.UpdateActionTicket(updated)
+
+	// Persist to database
+	if err := h.store.UpdateActionTicket(room, updated); err != nil {
+		return h.persistError(client, err, "Failed to save review")
+	}
+	h.RecordEvent(room.ID, models.EventActionReviewed, client.ID, map[string]any{
+		"action_id":        updated.ID,
+		"source_room_id":   updated.SourceRoomID,
+		"source_action_id": updated.SourceActionID,
+		"outcome":          outcome,
+	})
+
+	h.broadcastActionUpdated(room.ID, updated)
+	h.writeBackReview(updated.SourceRoomID, updated.SourceActionID, outcome, client.ID)
+
+	return nil
+}
+
+// writeBackReview records a review outcome on the action it was carried over
+// from. It is queued on the source room's actor; nothing happens if the room
+// or action no longer exists.
+func (h *Hub) writeBackReview(roomID, actionID string, outcome models.ReviewOutcome, userID string) {
+	h.enqueue(roomID, func(a *roomActor) {
+		var reviewed *models.ActionTicket
+		err := a.apply(func(room *models.Room) error {
+			reviewed = nil
+			action, ok := room.GetActionTicket(actionID)
+			if !ok {
+				return nil
+			}
+			reviewed = action.Clone()
+			reviewed.Review(outcome, userID, time.Now())
+			room.UpdateActionTicket(reviewed)
+			return h.store.UpdateActionTicket(room, reviewed)
+		})
+		if err != nil {
+			if !errors.Is(err, models.ErrRoomNotFound) {
+				log.Printf("Failed to write back review to room %s: %v", roomID, err)
+			}
+			return
+		}
+		if reviewed == nil {
+			return
+		}
+
+		h.RecordEvent(roomID, models.EventActionReviewed, userID, map[string]any{
+			"action_id": actionID,
+			"outcome":   outcome,
+		})
+		h.broadcastActionUpdated(roomID, reviewed)
+	})
+}
+
+// broadcastActionUpdated sends a changed action item to the room
+func (h *Hub) broadcastActionUpdated(roomID string, action *models.ActionTicket) {
+	response := Message{
+		Type: MsgActionUpdated,
+		Payload: map[string]any{
+			"action": action,
+		},
+	}
+	responseBytes, _ := json.Marshal(response)
+	h.BroadcastToApprovedParticipants(roomID, responseBytes)
+}
+
+// stringList returns the strings in a JSON array payload value
+func stringList(raw any) []string {
+	var values []string
+	if items, ok := raw.([]any); ok {
+		for _, item := range items {
+			if value, ok := item.(string); ok {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// parseDueDate reads a due date given as YYYY-MM-DD. An empty or missing
+// date means no due date.
+func parseDueDate(raw any) (*time.Time, error) {
+	value, _ := raw.(string)
+	if value == "" {
+		return nil, nil
+	}
+	dueDate, err := time.Parse(models.DueDateLayout, value)
+	if err != nil {
+		return nil, err
+	}
+	return &dueDate, nil
+}
+
+func (h *Hub) handleDeleteAction(client *Client, room *models.Room, payload map[string]any) error {
+	if !room.Allows(models.ActivityManageActions) {
+		h.sendError(client, "Actions can't be deleted in the current phase")
+		return nil
 	}
 
 	if !room.IsModeratorOrOwner(client.ID) {
 		h.sendError(client, "Only moderators can delete actions")
-		return
+		return nil
 	}
 
 	actionID, ok := payload["action_id"].(string)
 	if !ok || actionID == "" {
 		h.sendError(client, "Action ID is required")
-		return
+		return nil
 	}
 
 	// Check if action exists
-	if _, exists := room.GetActionTicket(actionID); !exists {
+	action, exists := room.GetActionTicket(actionID)
+	if !exists {
 		h.sendError(client, "Action not found")
-		return
+		return nil
 	}
 
+	deleted := action.Clone()
 	room.RemoveActionTicket(actionID)
 
 	// Persist to database
-	if err := h.store.Update(room); err != nil {
-		h.sendError(client, "Failed to delete action")
-		return
+	if err := h.store.DeleteActionTicket(room, actionID); err != nil {
+		return h.persistError(client, err, "Failed to delete action")
 	}
+	h.RecordEvent(room.ID, models.EventActionDeleted, client.ID, map[string]any{
+		"action":    deleted,
+		"moderator": room.IsModeratorOrOwner(client.ID),
+	})
 
 	response := Message{
 		Type: MsgActionDeleted,
@@ -543,35 +1133,37 @@ func (h *Hub) handleDeleteAction(client *Client, room *models.Room, payload map[
 	}
 	responseBytes, _ := json.Marshal(response)
 	h.BroadcastToApprovedParticipants(room.ID, responseBytes)
+
+	return nil
 }
 
-func (h *Hub) handleMarkCovered(client *Client, room *models.Room, payload map[string]any) {
-	if room.Phase != models.PhaseDiscussion && room.Phase != models.PhaseSummary {
+func (h *Hub) handleMarkCovered(client *Client, room *models.Room, payload map[string]any) error {
+	if !room.Allows(models.ActivityMarkCovered) {
 		h.sendError(client, "Can only mark tickets as covered during discussion or summary phase")
-		return
+		return nil
 	}
 
 	if !room.IsModeratorOrOwner(client.ID) {
 		h.sendError(client, "Only moderators can mark tickets as covered")
-		return
+		return nil
 	}
 
 	ticketID, ok := payload["ticket_id"].(string)
 	if !ok || ticketID == "" {
 		h.sendError(client, "Ticket ID is required")
-		return
+		return nil
 	}
 
 	covered, ok := payload["covered"].(bool)
 	if !ok {
 		h.sendError(client, "Covered status is required")
-		return
+		return nil
 	}
 
 	ticket, exists := room.GetTicket(ticketID)
 	if !exists {
 		h.sendError(client, "Ticket not found")
-		return
+		return nil
 	}
 
 	room.Lock()
@@ -579,74 +1171,149 @@ func (h *Hub) handleMarkCovered(client *Client, room *models.Room, payload map[s
 	room.Unlock()
 
 	// Persist to database
-	if err := h.store.Update(room); err != nil {
-		h.sendError(client, "Failed to update ticket covered status")
-		return
+	if err := h.store.UpdateTicket(room, ticket); err != nil {
+		return h.persistError(client, err, "Failed to update ticket covered status")
 	}
+	h.RecordEvent(room.ID, models.EventTicketCovered, client.ID, map[string]any{
+		"ticket_id": ticketID,
+		"covered":   covered,
+	})
 
-	response := Message{
-		Type: MsgTicketUpdated,
-		Payload: map[string]any{
-			"ticket": ticket,
-		},
-	}
-	responseBytes, _ := json.Marshal(response)
-	h.BroadcastToApprovedParticipants(room.ID, responseBytes)
+	h.broadcastTicket(room, MsgTicketUpdated, ticket)
+
+	return nil
 }
 
-func (h *Hub) handleSetPhase(client *Client, room *models.Room, payload map[string]any) {
+func (h *Hub) handleSetPhase(client *Client, room *models.Room, payload map[string]any) error {
 	if !room.IsModeratorOrOwner(client.ID) {
 		h.sendError(client, "Only moderators can change phase")
-		return
+		return nil
 	}
 
 	phaseStr, _ := payload["phase"].(string)
 	phase := models.Phase(phaseStr)
+	confirmed, _ := payload["confirm"].(bool)
 
-	// Validate phase transition
-	validPhases := []models.Phase{
-		models.PhaseTicketing,
-		models.PhaseMerging,
-		models.PhaseVoting,
-		models.PhaseDiscussion,
-		models.PhaseSummary,
+	switch err := room.CheckTransition(phase, confirmed); {
+	case err == nil:
+	case errors.Is(err, models.ErrPhaseNotConfigured):
+		h.sendError(client, "Invalid phase")
+		return nil
+	case errors.Is(err, models.ErrAlreadyInPhase):
+		h.sendError(client, "Room is already in this phase")
+		return nil
+	case errors.Is(err, models.ErrConfirmationRequired):
+		// The client asks the moderator and resends the command with confirm set
+		response := Message{
+			Type: MsgConfirmPhaseChange,
+			Payload: map[string]any{
+				"phase":         phase,
+				"current_phase": room.Phase,
+			},
+		}
+		responseBytes, _ := json.Marshal(response)
+		client.SendMessage(responseBytes)
+		return nil
+	default:
+		h.sendError(client, fmt.Sprintf("Can't enter %s: %v", phase, err))
+		return nil
 	}
 
-	valid := false
-	for _, p := range validPhases {
-		if phase == p {
-			valid = true
-			break
-		}
+	change := room.SetPhase(phase)
+
+	// Persist to database
+	if err := h.store.ChangePhase(room); err != nil {
+		return h.persistError(client, err, "Failed to save phase change")
 	}
+	h.RecordEvent(room.ID, models.EventPhaseChanged, client.ID, map[string]any{
+		"previous_phase":         change.From,
+		"phase":                  change.To,
+		"previous_phase_seconds": int(change.FromDuration().Seconds()),
+		"moderator":              room.IsModeratorOrOwner(client.ID),
+	})
 
-	if !valid {
-		h.sendError(client, "Invalid phase")
-		return
+	h.broadcastPhase(room, change)
+
+	return nil
+}
+
+func (h *Hub) handleSetPhases(client *Client, room *models.Room, payload map[string]any) error {
+	if !room.IsModeratorOrOwner(client.ID) {
+		h.sendError(client, "Only moderators can change phases")
+		return nil
 	}
 
-	room.SetPhase(phase)
+	rawPhases, ok := payload["phases"].([]any)
+	if !ok {
+		h.sendError(client, "Phases are required")
+		return nil
+	}
+	phases := make([]models.Phase, 0, len(rawPhases))
+	for _, raw := range rawPhases {
+		phase, _ := raw.(string)
+		phases = append(phases, models.Phase(phase))
+	}
+
+	room.RLock()
+	previousPhases := append([]models.Phase{}, room.Phases...)
+	room.RUnlock()
+
+	if err := room.SetPhases(phases); err != nil {
+		h.sendError(client, fmt.Sprintf("Invalid phases: %v", err))
+		return nil
+	}
 
 	// Persist to database
-	if err := h.store.Update(room); err != nil {
-		h.sendError(client, "Failed to save phase change")
-		return
+	if err := h.store.UpdateRoomSettings(room); err != nil {
+		return h.persistError(client, err, "Failed to save phases")
 	}
+	h.RecordEvent(room.ID, models.EventPhasesChanged, client.ID, map[string]any{
+		"previous_phases": previousPhases,
+		"phases":          phases,
+	})
 
+	room.RLock()
+	response := Message{
+		Type: MsgPhasesChanged,
+		Payload: map[string]any{
+			"phases":     room.Phases,
+			"activities": room.AllowedActivities(),
+		},
+	}
+	room.RUnlock()
+	responseBytes, _ := json.Marshal(response)
+	h.BroadcastToApprovedParticipants(room.ID, responseBytes)
+
+	return nil
+}
+
+// broadcastPhase tells everyone in the room which phase it is in now, how
+// long the previous one took and what can be done in the new one
+func (h *Hub) broadcastPhase(room *models.Room, change models.PhaseChange) {
+	room.RLock()
 	response := Message{
 		Type: MsgPhaseChanged,
 		Payload: map[string]any{
-			"phase": phase,
+			"phase":                  change.To,
+			"previous_phase":         change.From,
+			"entered_at":             change.At,
+			"previous_phase_seconds": int(change.FromDuration().Seconds()),
+			"activities":             room.AllowedActivities(),
 		},
 	}
+	room.RUnlock()
 	responseBytes, _ := json.Marshal(response)
 	h.BroadcastToApprovedParticipants(room.ID, responseBytes)
+
+	if change.TicketsRevealed {
+		h.broadcastTicketsRevealed(room)
+	}
 }
 
-func (h *Hub) handleSetRole(client *Client, room *models.Room, payload map[string]any) {
+func (h *Hub) handleSetRole(client *Client, room *models.Room, payload map[string]any) error {
 	if room.OwnerID != client.ID {
 		h.sendError(client, "Only room owner can change roles")
-		return
+		return nil
 	}
 
 	userID, _ := payload["user_id"].(string)
@@ -655,19 +1322,30 @@ func (h *Hub) handleSetRole(client *Client, room *models.Room, payload map[strin
 
 	if role != models.RoleModerator && role != models.RoleParticipant {
 		h.sendError(client, "Invalid role")
-		return
+		return nil
+	}
+
+	previousRole := models.Role("")
+	if p, ok := room.GetParticipant(userID); ok {
+		previousRole = p.Role
 	}
 
 	if !room.SetParticipantRole(userID, role) {
 		h.sendError(client, "User not found")
-		return
+		return nil
 	}
 
+	participant, _ := room.GetParticipant(userID)
+
 	// Persist to database
-	if err := h.store.Update(room); err != nil {
-		h.sendError(client, "Failed to save role change")
-		return
+	if err := h.store.UpsertParticipant(room, participant); err != nil {
+		return h.persistError(client, err, "Failed to save role change")
 	}
+	h.RecordEvent(room.ID, models.EventRoleChanged, client.ID, map[string]any{
+		"user_id":       userID,
+		"previous_role": previousRole,
+		"role":          role,
+	})
 
 	response := Message{
 		Type: MsgRoleChanged,
@@ -678,12 +1356,14 @@ func (h *Hub) handleSetRole(client *Client, room *models.Room, payload map[strin
 	}
 	responseBytes, _ := json.Marshal(response)
 	h.BroadcastToRoom(room.ID, responseBytes)
+
+	return nil
 }
 
-func (h *Hub) handleRemoveUser(client *Client, room *models.Room, payload map[string]any) {
+func (h *Hub) handleRemoveUser(client *Client, room *models.Room, payload map[string]any) error {
 	if room.OwnerID != client.ID && !room.IsModeratorOrOwner(client.ID) {
 		h.sendError(client, "Only owner or moderator can remove users")
-		return
+		return nil
 	}
 
 	userID, _ := payload["user_id"].(string)
@@ -691,16 +1371,18 @@ func (h *Hub) handleRemoveUser(client *Client, room *models.Room, payload map[st
 	// Cannot remove the owner
 	if userID == room.OwnerID {
 		h.sendError(client, "Cannot remove room owner")
-		return
+		return nil
 	}
 
 	room.RemoveParticipant(userID)
 
 	// Persist to database
-	if err := h.store.Update(room); err != nil {
-		h.sendError(client, "Failed to remove user")
-		return
+	if err := h.store.RemoveParticipant(room, userID); err != nil {
+		return h.persistError(client, err, "Failed to remove user")
 	}
+	h.RecordEvent(room.ID, models.EventParticipantRemoved, client.ID, map[string]any{
+		"user_id": userID,
+	})
 
 	response := Message{
 		Type: MsgUserRemoved,
@@ -710,28 +1392,32 @@ func (h *Hub) handleRemoveUser(client *Client, room *models.Room, payload map[st
 	}
 	responseBytes, _ := json.Marshal(response)
 	h.BroadcastToRoom(room.ID, responseBytes)
+
+	return nil
 }
 
-func (h *Hub) handleApproveParticipant(client *Client, room *models.Room, payload map[string]any) {
+func (h *Hub) handleApproveParticipant(client *Client, room *models.Room, payload map[string]any) error {
 	if !room.IsModeratorOrOwner(client.ID) {
 		h.sendError(client, "Only moderator or owner can approve participants")
-		return
+		return nil
 	}
 
 	userID, _ := payload["user_id"].(string)
 
 	if !room.ApproveParticipant(userID) {
 		h.sendError(client, "Participant not found in pending list")
-		return
+		return nil
 	}
 
+	participant, _ := room.GetParticipant(userID)
+
 	// Persist to database
-	if err := h.store.Update(room); err != nil {
-		h.sendError(client, "Failed to approve participant")
-		return
+	if err := h.store.UpsertParticipant(room, participant); err != nil {
+		return h.persistError(client, err, "Failed to approve participant")
 	}
-
-	participant, _ := room.GetParticipant(userID)
+	h.RecordEvent(room.ID, models.EventParticipantApproved, client.ID, map[string]any{
+		"user_id": userID,
+	})
 
 	response := Message{
 		Type: MsgParticipantApproved,
@@ -748,41 +1434,36 @@ func (h *Hub) handleApproveParticipant(client *Client, room *models.Room, payloa
 		room.RLock()
 		defer room.RUnlock()
 		stateMsg := Message{
-			Type: MsgRoomState,
-			Payload: map[string]any{
-				"id":                   room.ID,
-				"name":                 room.Name,
-				"phase":                room.Phase,
-				"votes_per_user":       room.VotesPerUser,
-				"participants":         room.Participants,
-				"pending_participants": room.PendingParticipants,
-				"tickets":              room.Tickets,
-				"action_tickets":       room.ActionTickets,
-			},
+			Type:    MsgRoomState,
+			Payload: roomStatePayload(room, userID),
 		}
 		bytes, _ := json.Marshal(stateMsg)
 		return bytes
 	}())
+
+	return nil
 }
 
-func (h *Hub) handleRejectParticipant(client *Client, room *models.Room, payload map[string]any) {
+func (h *Hub) handleRejectParticipant(client *Client, room *models.Room, payload map[string]any) error {
 	if !room.IsModeratorOrOwner(client.ID) {
 		h.sendError(client, "Only moderator or owner can reject participants")
-		return
+		return nil
 	}
 
 	userID, _ := payload["user_id"].(string)
 
 	if !room.RejectParticipant(userID) {
 		h.sendError(client, "Participant not found in pending list")
-		return
+		return nil
 	}
 
 	// Persist to database
-	if err := h.store.Update(room); err != nil {
-		h.sendError(client, "Failed to reject participant")
-		return
+	if err := h.store.RemoveParticipant(room, userID); err != nil {
+		return h.persistError(client, err, "Failed to reject participant")
 	}
+	h.RecordEvent(room.ID, models.EventParticipantRejected, client.ID, map[string]any{
+		"user_id": userID,
+	})
 
 	response := Message{
 		Type: MsgParticipantRejected,
@@ -792,27 +1473,31 @@ func (h *Hub) handleRejectParticipant(client *Client, room *models.Room, payload
 	}
 	responseBytes, _ := json.Marshal(response)
 	h.BroadcastToRoom(room.ID, responseBytes)
+
+	return nil
 }
 
-func (h *Hub) handleSetAutoApprove(client *Client, room *models.Room, payload map[string]any) {
+func (h *Hub) handleSetAutoApprove(client *Client, room *models.Room, payload map[string]any) error {
 	if !room.IsModeratorOrOwner(client.ID) {
 		h.sendError(client, "Only moderator or owner can change auto-approve setting")
-		return
+		return nil
 	}
 
 	autoApprove, ok := payload["auto_approve"].(bool)
 	if !ok {
 		h.sendError(client, "Invalid auto_approve value")
-		return
+		return nil
 	}
 
 	room.SetAutoApprove(autoApprove)
 
 	// Persist to database
-	if err := h.store.Update(room); err != nil {
-		h.sendError(client, "Failed to update auto-approve setting")
-		return
+	if err := h.store.UpdateRoomSettings(room); err != nil {
+		return h.persistError(client, err, "Failed to update auto-approve setting")
 	}
+	h.RecordEvent(room.ID, models.EventAutoApproveChanged, client.ID, map[string]any{
+		"auto_approve": autoApprove,
+	})
 
 	response := Message{
 		Type: MsgAutoApproveChanged,
@@ -822,6 +1507,279 @@ func (h *Hub) handleSetAutoApprove(client *Client, room *models.Room, payload ma
 	}
 	responseBytes, _ := json.Marshal(response)
 	h.BroadcastToRoom(room.ID, responseBytes)
+
+	return nil
+}
+
+func (h *Hub) handleSetHiddenVotes(client *Client, room *models.Room, payload map[string]any) error {
+	if !room.IsModeratorOrOwner(client.ID) {
+		h.sendError(client, "Only moderator or owner can change hidden voting")
+		return nil
+	}
+
+	hidden, ok := payload["hidden_votes"].(bool)
+	if !ok {
+		h.sendError(client, "Invalid hidden_votes value")
+		return nil
+	}
+
+	room.SetHiddenVotes(hidden)
+
+	// Persist to database
+	if err := h.store.UpdateRoomSettings(room); err != nil {
+		return h.persistError(client, err, "Failed to update hidden voting")
+	}
+	h.RecordEvent(room.ID, models.EventHiddenVotesChanged, client.ID, map[string]any{
+		"hidden_votes": hidden,
+	})
+
+	response := Message{
+		Type: MsgHiddenVotesChanged,
+		Payload: map[string]any{
+			"hidden_votes": hidden,
+		},
+	}
+	responseBytes, _ := json.Marshal(response)
+	h.BroadcastToRoom(room.ID, responseBytes)
+
+	// Votes that were visible may now be hidden and vice versa
+	h.sendRoomStateToParticipants(room)
+
+	return nil
+}
+
+func (h *Hub) handleRevealVotes(client *Client, room *models.Room, payload map[string]any) error {
+	if !room.IsModeratorOrOwner(client.ID) {
+		h.sendError(client, "Only moderator or owner can reveal votes")
+		return nil
+	}
+
+	if !room.VotesHidden() {
+		h.sendError(client, "Votes are not hidden")
+		return nil
+	}
+
+	room.RevealVotes()
+
+	// Persist to database
+	if err := h.store.UpdateRoomSettings(room); err != nil {
+		return h.persistError(client, err, "Failed to reveal votes")
+	}
+	h.RecordEvent(room.ID, models.EventVotesRevealed, client.ID, map[string]any{})
+
+	h.broadcastToEachParticipant(room, func(viewerID string) (Message, bool) {
+		room.RLock()
+		defer room.RUnlock()
+		return Message{
+			Type: MsgVotesRevealed,
+			Payload: map[string]any{
+				"tickets": room.TicketViews(viewerID),
+			},
+		}, true
+	})
+
+	return nil
+}
+
+func (h *Hub) handleSetBlindTickets(client *Client, room *models.Room, payload map[string]any) error {
+	if !room.IsModeratorOrOwner(client.ID) {
+		h.sendError(client, "Only moderator or owner can change blind ticket writing")
+		return nil
+	}
+
+	blind, ok := payload["blind_tickets"].(bool)
+	if !ok {
+		h.sendError(client, "Invalid blind_tickets value")
+		return nil
+	}
+
+	room.SetBlindTickets(blind)
+
+	// Persist to database
+	if err := h.store.UpdateRoomSettings(room); err != nil {
+		return h.persistError(client, err, "Failed to update blind ticket writing")
+	}
+	h.RecordEvent(room.ID, models.EventBlindTicketsChanged, client.ID, map[string]any{
+		"blind_tickets": blind,
+	})
+
+	response := Message{
+		Type: MsgBlindTicketsChanged,
+		Payload: map[string]any{
+			"blind_tickets": blind,
+		},
+	}
+	responseBytes, _ := json.Marshal(response)
+	h.BroadcastToRoom(room.ID, responseBytes)
+
+	// Tickets that were visible may now be hidden and vice versa
+	h.sendRoomStateToParticipants(room)
+
+	return nil
+}
+
+func (h *Hub) handleRevealTickets(client *Client, room *models.Room, payload map[string]any) error {
+	if !room.IsModeratorOrOwner(client.ID) {
+		h.sendError(client, "Only moderator or owner can reveal tickets")
+		return nil
+	}
+
+	if !room.TicketsHidden() {
+		h.sendError(client, "Tickets are not hidden")
+		return nil
+	}
+
+	room.RevealTickets()
+
+	// Persist to database
+	if err := h.store.UpdateRoomSettings(room); err != nil {
+		return h.persistError(client, err, "Failed to reveal tickets")
+	}
+	h.RecordEvent(room.ID, models.EventTicketsRevealed, client.ID, map[string]any{})
+
+	h.broadcastTicketsRevealed(room)
+
+	return nil
+}
+
+func (h *Hub) handleSetAnonymousTickets(client *Client, room *models.Room, payload map[string]any) error {
+	if !room.IsModeratorOrOwner(client.ID) {
+		h.sendError(client, "Only moderator or owner can change anonymous tickets")
+		return nil
+	}
+
+	anonymous, ok := payload["anonymous_tickets"].(bool)
+	if !ok {
+		h.sendError(client, "Invalid anonymous_tickets value")
+		return nil
+	}
+
+	if !room.SetAnonymousTickets(anonymous) {
+		h.sendError(client, "Anonymity can't be turned off once tickets have been written")
+		return nil
+	}
+
+	// Persist to database
+	if err := h.store.UpdateRoomSettings(room); err != nil {
+		return h.persistError(client, err, "Failed to update anonymous tickets")
+	}
+	h.RecordEvent(room.ID, models.EventAnonymousTicketsChanged, client.ID, map[string]any{
+		"anonymous_tickets": anonymous,
+	})
+
+	response := Message{
+		Type: MsgAnonymousTicketsChanged,
+		Payload: map[string]any{
+			"anonymous_tickets": anonymous,
+		},
+	}
+	responseBytes, _ := json.Marshal(response)
+	h.BroadcastToRoom(room.ID, responseBytes)
+
+	// Authors of existing tickets are hidden from now on
+	h.sendRoomStateToParticipants(room)
+
+	return nil
+}
+
+func (h *Hub) handleSetColumns(client *Client, room *models.Room, payload map[string]any) error {
+	if !room.IsModeratorOrOwner(client.ID) {
+		h.sendError(client, "Only moderator or owner can change columns")
+		return nil
+	}
+
+	rawColumns, ok := payload["columns"].([]any)
+	if !ok {
+		h.sendError(client, "Columns are required")
+		return nil
+	}
+
+	columns := make([]models.Column, 0, len(rawColumns))
+	for _, raw := range rawColumns {
+		fields, _ := raw.(map[string]any)
+		column := models.Column{}
+		column.ID, _ = fields["id"].(string)
+		column.Title, _ = fields["title"].(string)
+		column.Color, _ = fields["color"].(string)
+		column.Prompt, _ = fields["prompt"].(string)
+		if column.ID == "" {
+			column.ID = uuid.New().String()
+		}
+		columns = append(columns, column)
+	}
+
+	if err := room.SetColumns(columns); err != nil {
+		h.sendError(client, fmt.Sprintf("Invalid columns: %v", err))
+		return nil
+	}
+
+	// Persist to database
+	if err := h.store.SetColumns(room); err != nil {
+		return h.persistError(client, err, "Failed to update columns")
+	}
+	h.RecordEvent(room.ID, models.EventColumnsChanged, client.ID, map[string]any{
+		"columns": columns,
+	})
+
+	response := Message{
+		Type: MsgColumnsChanged,
+		Payload: map[string]any{
+			"columns": columns,
+		},
+	}
+	responseBytes, _ := json.Marshal(response)
+	h.BroadcastToApprovedParticipants(room.ID, responseBytes)
+
+	return nil
+}
+
+func (h *Hub) handleSetMaxVotesPerTicket(client *Client, room *models.Room, payload map[string]any) error {
+	if !room.IsModeratorOrOwner(client.ID) {
+		h.sendError(client, "Only moderator or owner can change votes per ticket")
+		return nil
+	}
+
+	maxVotes, ok := payload["max_votes_per_ticket"].(float64)
+	if !ok || maxVotes < 1 || int(maxVotes) > room.VotesPerUser {
+		h.sendError(client, fmt.Sprintf("Votes per ticket must be between 1 and %d", room.VotesPerUser))
+		return nil
+	}
+
+	room.Lock()
+	room.MaxVotesPerTicket = int(maxVotes)
+	room.Unlock()
+
+	// Persist to database
+	if err := h.store.UpdateRoomSettings(room); err != nil {
+		return h.persistError(client, err, "Failed to update votes per ticket")
+	}
+	h.RecordEvent(room.ID, models.EventMaxVotesPerTicketChanged, client.ID, map[string]any{
+		"max_votes_per_ticket": int(maxVotes),
+	})
+
+	response := Message{
+		Type: MsgMaxVotesPerTicketChanged,
+		Payload: map[string]any{
+			"max_votes_per_ticket": int(maxVotes),
+		},
+	}
+	responseBytes, _ := json.Marshal(response)
+	h.BroadcastToRoom(room.ID, responseBytes)
+
+	return nil
+}
+
+// sendConflictError tells the client its command could not be applied because the room kept changing
+func (h *Hub) sendConflictError(client *Client) {
+	response := Message{
+		Type: MsgError,
+		Payload: map[string]any{
+			"message": "The room was changed by someone else, please try again",
+			"code":    "conflict",
+		},
+	}
+	responseBytes, _ := json.Marshal(response)
+	client.SendMessage(responseBytes)
 }
 
 func (h *Hub) sendError(client *Client, message string) {
@@ -835,24 +1793,51 @@ func (h *Hub) sendError(client *Client, message string) {
 	client.SendMessage(responseBytes)
 }
 
+// roomStatePayload builds the full room state sent to an approved participant.
+// The caller must hold the room's read lock.
+func roomStatePayload(room *models.Room, viewerID string) map[string]any {
+	now := time.Now()
+	hiddenTickets, _ := room.HiddenTicketCount(viewerID)
+	tickets := room.TicketViews(viewerID)
+	return map[string]any{
+		"id":                   room.ID,
+		"name":                 room.Name,
+		"phase":                room.Phase,
+		"phases":               room.Phases,
+		"phase_started_at":     room.PhaseStartedAt(),
+		"phase_history":        room.PhaseHistory,
+		"activities":           room.AllowedActivities(),
+		"timer":                timerPayload(room.Timer, now),
+		"server_time":          now,
+		"votes_per_user":       room.VotesPerUser,
+		"max_votes_per_ticket": room.MaxVotesPerTicket,
+		"hidden_votes":         room.HiddenVotes,
+		"votes_revealed":       room.VotesRevealed,
+		"anonymous_tickets":    room.AnonymousTickets,
+		"blind_tickets":        room.BlindTickets,
+		"tickets_revealed":     room.TicketsRevealed,
+		"hidden_tickets":       hiddenTickets,
+		"auto_approve":         room.AutoApprove,
+		"columns":              room.Columns,
+		"participants":         room.Participants,
+		"pending_participants": room.PendingParticipants,
+		"tickets":              tickets,
+		"reaction_counts":      models.ReactionCounts(tickets),
+		"tickets_by_column":    room.TicketIDsByColumn(),
+		"action_tickets":       room.ActionTickets,
+		"polls":                room.PollViews(viewerID),
+		"health_check":         room.HealthCheckView(viewerID),
+	}
+}
+
 // SendRoomState sends the current room state to a client
 func (h *Hub) SendRoomState(client *Client, room *models.Room) {
 	room.RLock()
 	defer room.RUnlock()
 
 	response := Message{
-		Type: MsgRoomState,
-		Payload: map[string]any{
-			"id":                   room.ID,
-			"name":                 room.Name,
-			"phase":                room.Phase,
-			"votes_per_user":       room.VotesPerUser,
-			"auto_approve":         room.AutoApprove,
-			"participants":         room.Participants,
-			"pending_participants": room.PendingParticipants,
-			"tickets":              room.Tickets,
-			"action_tickets":       room.ActionTickets,
-		},
+		Type:    MsgRoomState,
+		Payload: roomStatePayload(room, client.ID),
 	}
 	responseBytes, _ := json.Marshal(response)
 	client.SendMessage(responseBytes)
@@ -869,7 +1854,11 @@ func (h *Hub) SendPendingRoomState(client *Client, room *models.Room) {
 			"id":                   room.ID,
 			"name":                 room.Name,
 			"phase":                room.Phase,
+			"phases":               room.Phases,
 			"votes_per_user":       room.VotesPerUser,
+			"max_votes_per_ticket": room.MaxVotesPerTicket,
+			"hidden_votes":         room.HiddenVotes,
+			"anonymous_tickets":    room.AnonymousTickets,
 			"participants":         make(map[string]*models.Participant),
 			"pending_participants": make(map[string]*models.Participant),
 			"tickets":              make(map[string]*models.Ticket),
@@ -916,23 +1905,22 @@ func (h *Hub) NotifyParticipantPending(room *models.Room, participant *models.Pa
 	h.BroadcastToRoom(room.ID, responseBytes)
 }
 
-func (h *Hub) handleAutoMergeTickets(client *Client, room *models.Room, payload map[string]any) {
+func (h *Hub) handleAutoMergeTickets(client *Client, room *models.Room, payload map[string]any) error {
 	// Only moderators/owners can trigger auto-merge
 	if !room.IsModeratorOrOwner(client.ID) {
 		h.sendError(client, "Only moderators can trigger auto-merge")
-		return
+		return nil
 	}
 
-	// Only available in DISCUSSION phase
-	if room.Phase != models.PhaseMerging {
-		h.sendError(client, "Auto-merge is only available during discussion phase")
-		return
+	if !room.Allows(models.ActivityAutoMerge) {
+		h.sendError(client, "Auto-merge is only available during merging phase")
+		return nil
 	}
 
 	// Check if chat completion service is configured
 	if h.chatCompletion == nil || !h.chatCompletion.IsConfigured() {
 		h.sendError(client, "Chat completion service not configured")
-		return
+		return nil
 	}
 
 	// Send progress message
@@ -945,76 +1933,105 @@ func (h *Hub) handleAutoMergeTickets(client *Client, room *models.Room, payload
 	progressBytes, _ := json.Marshal(progressMsg)
 	h.SendToClient(room.ID, client.ID, progressBytes)
 
-	// Get all tickets
+	// Get a snapshot of all tickets and columns
 	room.RLock()
 	tickets := make(map[string]*models.Ticket)
 	for id, ticket := range room.Tickets {
-		tickets[id] = ticket
+		tickets[id] = ticket.Clone()
 	}
+	columns := append([]models.Column{}, room.Columns...)
 	room.RUnlock()
 
-	// Call AI service to get merge suggestions
-	mergeResponse, err := h.chatCompletion.SuggestMerges(tickets)
-	if err != nil {
-		log.Printf("Auto-merge failed: %v", err)
-		h.sendError(client, fmt.Sprintf("Auto-merge failed: %v", err))
-		return
-	}
-
-	// Apply the suggested merges
-	mergesApplied := 0
-	for _, group := range mergeResponse.MergeGroups {
-		// Validate that parent ticket exists
-		parentTicket, ok := room.GetTicket(group.ParentTicketID)
-		if !ok {
-			log.Printf("Parent ticket %s not found, skipping group", group.ParentTicketID)
-			continue
+	// Call AI service off the room's actor so the room keeps processing
+	// commands, then apply the suggestions back on the actor
+	go func() {
+		mergeResponse, err := h.chatCompletion.SuggestMerges(tickets, columns)
+		if err != nil {
+			log.Printf("Auto-merge failed: %v", err)
+			h.sendError(client, fmt.Sprintf("Auto-merge failed: %v", err))
+			return
 		}
+		h.enqueue(client.RoomID, func(a *roomActor) {
+			h.applyMergeSuggestions(a, client, mergeResponse)
+		})
+	}()
 
-		// Skip if parent is already a child
-		if parentTicket.DeduplicationTicketID != nil {
-			log.Printf("Parent ticket %s is already a child, skipping group", group.ParentTicketID)
-			continue
-		}
+	return nil
+}
 
-		// Apply merges for this group
-		for _, childID := range group.ChildTicketIDs {
-			childTicket, ok := room.GetTicket(childID)
+// applyMergeSuggestions merges the tickets suggested by the AI service. It runs on the room's actor.
+func (h *Hub) applyMergeSuggestions(a *roomActor, client *Client, mergeResponse *chatcompletion.AutoMergeResponse) {
+	// Already merged children are skipped, so the merges can be re-applied
+	// to a fresh copy of the room after a conflict
+	mergesApplied := 0
+	err := a.apply(func(room *models.Room) error {
+		for _, group := range mergeResponse.MergeGroups {
+			// Validate that parent ticket exists
+			parentTicket, ok := room.GetTicket(group.ParentTicketID)
 			if !ok {
-				log.Printf("Child ticket %s not found, skipping", childID)
+				log.Printf("Parent ticket %s not found, skipping group", group.ParentTicketID)
 				continue
 			}
 
-			// Skip if already merged
-			if childTicket.DeduplicationTicketID != nil {
+			// Skip if parent is already a child
+			if parentTicket.DeduplicationTicketID != nil {
+				log.Printf("Parent ticket %s is already a child, skipping group", group.ParentTicketID)
 				continue
 			}
 
-			// Skip if trying to merge with itself
-			if childID == group.ParentTicketID {
-				continue
-			}
+			// Apply merges for this group
+			for _, childID := range group.ChildTicketIDs {
+				childTicket, ok := room.GetTicket(childID)
+				if !ok {
+					log.Printf("Child ticket %s not found, skipping", childID)
+					continue
+				}
 
-			// Merge the child into the parent by setting deduplication_ticket_id
-			room.Lock()
-			childTicket.DeduplicationTicketID = &group.ParentTicketID
-			room.Unlock()
-			mergesApplied++
+				// Skip if already merged
+				if childTicket.DeduplicationTicketID != nil {
+					continue
+				}
 
-			// Broadcast the ticket update
-			response := Message{
-				Type: MsgTicketUpdated,
-				Payload: map[string]any{
-					"ticket": childTicket,
-				},
+				// Skip if trying to merge with itself
+				if childID == group.ParentTicketID {
+					continue
+				}
+
+				// Tickets are only merged within a column
+				if childTicket.ColumnID != parentTicket.ColumnID {
+					log.Printf("Child ticket %s is in another column than %s, skipping", childID, group.ParentTicketID)
+					continue
+				}
+
+				// Merge the child into the parent by setting deduplication_ticket_id
+				room.Lock()
+				childTicket.DeduplicationTicketID = &group.ParentTicketID
+				room.Unlock()
+
+				// Persist to database
+				if err := h.store.UpdateTicket(room, childTicket); err != nil {
+					return err
+				}
+				mergesApplied++
+				h.RecordEvent(room.ID, models.EventTicketMerged, client.ID, map[string]any{
+					"ticket_id":                 childID,
+					"parent_ticket_id":          group.ParentTicketID,
+					"previous_parent_ticket_id": nil,
+					"auto":                      true,
+					"moderator":                 room.IsModeratorOrOwner(client.ID),
+				})
+
+				// Broadcast the ticket update
+				h.broadcastTicket(room, MsgTicketUpdated, childTicket)
 			}
-			responseBytes, _ := json.Marshal(response)
-			h.BroadcastToApprovedParticipants(room.ID, responseBytes)
 		}
+		return nil
+	})
+	if errors.Is(err, models.ErrVersionConflict) {
+		h.sendConflictError(client)
+		return
 	}
-
-	// Persist changes to database
-	if err := h.store.Update(room); err != nil {
+	if err != nil {
 		log.Printf("Failed to save auto-merge changes: %v", err)
 		h.sendError(client, "Failed to save changes")
 		return
@@ -1029,26 +2046,25 @@ func (h *Hub) handleAutoMergeTickets(client *Client, room *models.Room, payload
 		},
 	}
 	completeBytes, _ := json.Marshal(completeMsg)
-	h.SendToClient(room.ID, client.ID, completeBytes)
+	h.SendToClient(a.roomID, client.ID, completeBytes)
 }
 
-func (h *Hub) handleAutoProposeActions(client *Client, room *models.Room, payload map[string]any) {
+func (h *Hub) handleAutoProposeActions(client *Client, room *models.Room, payload map[string]any) error {
 	// Only moderators/owners can trigger auto-propose
 	if !room.IsModeratorOrOwner(client.ID) {
 		h.sendError(client, "Only moderators can trigger auto-propose actions")
-		return
+		return nil
 	}
 
-	// Only available in DISCUSSION phase
-	if room.Phase != models.PhaseDiscussion {
-		h.sendError(client, "Auto-propose actions is only available during summary phase")
-		return
+	if !room.Allows(models.ActivityManageActions) {
+		h.sendError(client, "Auto-propose actions is not available in the current phase")
+		return nil
 	}
 
 	// Check if chat completion service is configured
 	if h.chatCompletion == nil || !h.chatCompletion.IsConfigured() {
 		h.sendError(client, "Chat completion service not configured")
-		return
+		return nil
 	}
 
 	// Get parameters from payload
@@ -1077,49 +2093,78 @@ func (h *Hub) handleAutoProposeActions(client *Client, room *models.Room, payloa
 	progressBytes, _ := json.Marshal(progressMsg)
 	h.SendToClient(room.ID, client.ID, progressBytes)
 
-	// Get all tickets
+	// Get a snapshot of all tickets and columns
 	room.RLock()
 	tickets := make(map[string]*models.Ticket)
 	for id, ticket := range room.Tickets {
-		tickets[id] = ticket
+		tickets[id] = ticket.Clone()
 	}
+	columns := append([]models.Column{}, room.Columns...)
 	room.RUnlock()
 
-	// Call AI service to get action suggestions
-	actionResponse, err := h.chatCompletion.ProposeActions(tickets, teamContext, language, sarcastic)
-	if err != nil {
-		log.Printf("Auto-propose actions failed: %v", err)
-		h.sendError(client, fmt.Sprintf("Auto-propose actions failed: %v", err))
-		return
-	}
+	// Call AI service off the room's actor so the room keeps processing
+	// commands, then create the actions back on the actor
+	go func() {
+		actionResponse, err := h.chatCompletion.ProposeActions(tickets, columns, teamContext, language, sarcastic)
+		if err != nil {
+			log.Printf("Auto-propose actions failed: %v", err)
+			h.sendError(client, fmt.Sprintf("Auto-propose actions failed: %v", err))
+			return
+		}
+		h.enqueue(client.RoomID, func(a *roomActor) {
+			h.applyActionSuggestions(a, client, actionResponse)
+		})
+	}()
+
+	return nil
+}
 
-	// Create the suggested actions with robot icon prefix
+// applyActionSuggestions creates the actions proposed by the AI service. It runs on the room's actor.
+func (h *Hub) applyActionSuggestions(a *roomActor, client *Client, actionResponse *chatcompletion.AutoProposeActionsResponse) {
+	// Create the suggested actions with robot icon prefix. Each suggestion is
+	// persisted once; after a conflict the remaining ones are saved against a
+	// fresh copy of the room.
 	actionsCreated := 0
-	for _, suggestion := range actionResponse.Actions {
-		action := &models.ActionTicket{
-			ID:          uuid.New().String(),
-			Content:     "🤖 " + suggestion.Content,
-			TicketID:    suggestion.TicketID,
-			AssigneeIDs: []string{},
-			CreatedAt:   time.Now(),
-		}
+	err := a.apply(func(room *models.Room) error {
+		for ; actionsCreated < len(actionResponse.Actions); actionsCreated++ {
+			suggestion := actionResponse.Actions[actionsCreated]
+			action := &models.ActionTicket{
+				ID:          uuid.New().String(),
+				Content:     "🤖 " + suggestion.Content,
+				TicketID:    suggestion.TicketID,
+				AssigneeIDs: []string{},
+				Status:      models.ActionOpen,
+				CreatedAt:   time.Now(),
+			}
 
-		room.AddActionTicket(action)
-		actionsCreated++
+			room.AddActionTicket(action)
 
-		// Broadcast the new action
-		response := Message{
-			Type: MsgActionAdded,
-			Payload: map[string]any{
+			// Persist to database
+			if err := h.store.AddActionTicket(room, action); err != nil {
+				return err
+			}
+			h.RecordEvent(room.ID, models.EventActionAdded, client.ID, map[string]any{
 				"action": action,
-			},
+				"auto":   true,
+			})
+
+			// Broadcast the new action
+			response := Message{
+				Type: MsgActionAdded,
+				Payload: map[string]any{
+					"action": action,
+				},
+			}
+			responseBytes, _ := json.Marshal(response)
+			h.BroadcastToApprovedParticipants(room.ID, responseBytes)
 		}
-		responseBytes, _ := json.Marshal(response)
-		h.BroadcastToApprovedParticipants(room.ID, responseBytes)
+		return nil
+	})
+	if errors.Is(err, models.ErrVersionConflict) {
+		h.sendConflictError(client)
+		return
 	}
-
-	// Persist changes to database
-	if err := h.store.Update(room); err != nil {
+	if err != nil {
 		log.Printf("Failed to save auto-proposed actions: %v", err)
 		h.sendError(client, "Failed to save actions")
 		return
@@ -1133,5 +2178,5 @@ func (h *Hub) handleAutoProposeActions(client *Client, room *models.Room, payloa
 		},
 	}
 	completeBytes, _ := json.Marshal(completeMsg)
-	h.SendToClient(room.ID, client.ID, completeBytes)
+	h.SendToClient(a.roomID, client.ID, completeBytes)
 }
@@ -0,0 +1,795 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Armatorix/GoRetro/internal/chatcompletion"
+	"github.com/Armatorix/GoRetro/internal/models"
+)
+
+// newTestHub starts a hub backed by a memory store holding one room with the
+// given approved participants, the first of whom owns the room
+func newTestHub(t *testing.T, userIDs ...string) (*Hub, models.Store, *models.Room) {
+	t.Helper()
+	store := models.NewMemoryStore()
+	room := models.NewRoom("room-1", "Test Room", userIDs[0], 5)
+	for i, id := range userIDs {
+		role := models.RoleParticipant
+		if i == 0 {
+			role = models.RoleOwner
+		}
+		room.AddParticipant(models.User{ID: id, Name: id}, role, models.StatusApproved)
+	}
+	if err := store.Create(room); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+
+	hub := NewHub(store)
+	go hub.Run()
+	return hub, store, room
+}
+
+// register adds a client to the hub and waits until the hub's loop has picked it up
+func register(t *testing.T, hub *Hub, client *Client) {
+	t.Helper()
+	hub.Register(client)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		hub.mu.RLock()
+		_, ok := hub.rooms[client.RoomID][client.ID]
+		hub.mu.RUnlock()
+		if ok {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out registering %s", client.ID)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// connect creates a client for the user in the room and registers it with the hub
+func connect(t *testing.T, hub *Hub, userID, roomID string) *Client {
+	t.Helper()
+	client := NewClient(userID, roomID, nil)
+	register(t, hub, client)
+	return client
+}
+
+// send passes a message from the client to the hub
+func send(hub *Hub, client *Client, msgType MessageType, payload map[string]any) {
+	msg, _ := json.Marshal(Message{Type: msgType, Payload: payload})
+	hub.HandleMessage(client, msg)
+}
+
+// receive waits for the next message of the given type sent to a client
+func receive(t *testing.T, client *Client, msgType MessageType) Message {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case raw := <-client.Send:
+			var msg Message
+			if err := json.Unmarshal(raw, &msg); err != nil {
+				t.Fatalf("Invalid message: %v", err)
+			}
+			if msg.Type == msgType {
+				return msg
+			}
+		case <-timeout:
+			t.Fatalf("Timed out waiting for %s", msgType)
+		}
+	}
+}
+
+func TestHub_SerializesRoomCommands(t *testing.T) {
+	hub, store, room := newTestHub(t, "user1", "user2")
+
+	clients := []*Client{
+		connect(t, hub, "user1", room.ID),
+		connect(t, hub, "user2", room.ID),
+	}
+
+	const perClient = 20
+	var wg sync.WaitGroup
+	for _, client := range clients {
+		wg.Add(1)
+		go func(client *Client) {
+			defer wg.Done()
+			for i := 0; i < perClient; i++ {
+				send(hub, client, MsgAddTicket, map[string]any{"content": fmt.Sprintf("%s ticket %d", client.ID, i)})
+			}
+		}(client)
+	}
+	wg.Wait()
+
+	// Every client sees every ticket, in the same order
+	var orders [][]string
+	for _, client := range clients {
+		var order []string
+		for i := 0; i < perClient*len(clients); i++ {
+			msg := receive(t, client, MsgTicketAdded)
+			ticket := msg.Payload["ticket"].(map[string]any)
+			order = append(order, ticket["id"].(string))
+		}
+		orders = append(orders, order)
+	}
+	for i := range orders[0] {
+		if orders[0][i] != orders[1][i] {
+			t.Fatalf("Clients received tickets in different order at position %d", i)
+		}
+	}
+
+	stored, _ := store.Get(room.ID)
+	if len(stored.Tickets) != perClient*len(clients) {
+		t.Errorf("Expected %d stored tickets, got %d", perClient*len(clients), len(stored.Tickets))
+	}
+	if stored.Version != int64(perClient*len(clients)) {
+		t.Errorf("Expected version %d, got %d", perClient*len(clients), stored.Version)
+	}
+}
+
+func TestHub_ActorRetiresWithLastClient(t *testing.T) {
+	hub, _, room := newTestHub(t, "user1")
+
+	client := connect(t, hub, "user1", room.ID)
+
+	send(hub, client, MsgAddTicket, map[string]any{"content": "Ticket"})
+	receive(t, client, MsgTicketAdded)
+
+	hub.Unregister(client)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		hub.mu.RLock()
+		_, running := hub.actors[room.ID]
+		hub.mu.RUnlock()
+		if !running {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected room actor to stop after the last client left")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestHub_UndoRevertsModeratorActions(t *testing.T) {
+	hub, store, room := newTestHub(t, "owner")
+
+	client := connect(t, hub, "owner", room.ID)
+
+	send(hub, client, MsgAddTicket, map[string]any{"content": "Ticket"})
+	ticket := receive(t, client, MsgTicketAdded).Payload["ticket"].(map[string]any)
+	ticketID := ticket["id"].(string)
+
+	send(hub, client, MsgDeleteTicket, map[string]any{"ticket_id": ticketID})
+	receive(t, client, MsgTicketDeleted)
+	send(hub, client, MsgSetPhase, map[string]any{"phase": string(models.PhaseMerging)})
+	receive(t, client, MsgPhaseChanged)
+
+	// Undo both actions, newest first
+	send(hub, client, MsgUndo, map[string]any{"count": 2})
+	if phase := receive(t, client, MsgPhaseChanged).Payload["phase"]; phase != string(models.PhaseTicketing) {
+		t.Errorf("Expected phase to be restored to TICKETING, got %v", phase)
+	}
+	restored := receive(t, client, MsgTicketAdded).Payload["ticket"].(map[string]any)
+	if restored["id"] != ticketID {
+		t.Errorf("Expected ticket %s to be restored, got %v", ticketID, restored["id"])
+	}
+
+	stored, _ := store.Get(room.ID)
+	if stored.Phase != models.PhaseTicketing {
+		t.Errorf("Expected stored phase TICKETING, got %s", stored.Phase)
+	}
+	if _, ok := stored.GetTicket(ticketID); !ok {
+		t.Error("Expected restored ticket to be stored")
+	}
+
+	// Undone actions are not undone twice
+	send(hub, client, MsgUndo, map[string]any{"count": 1})
+	if msg := receive(t, client, MsgError); msg.Payload["message"] != "Nothing to undo" {
+		t.Errorf("Expected nothing left to undo, got %v", msg.Payload["message"])
+	}
+}
+
+func TestHub_UndoDropsRespentVotes(t *testing.T) {
+	hub, store, room := newTestHub(t, "owner", "user2")
+
+	room.VotesPerUser = 1
+	room.SetPhase(models.PhaseVoting)
+	for _, id := range []string{"ticket-1", "ticket-2"} {
+		room.AddTicket(&models.Ticket{ID: id, Content: "Ticket", AuthorID: "owner", VoterIDs: []string{}, CreatedAt: time.Now()})
+	}
+	if err := store.Update(room); err != nil {
+		t.Fatalf("Failed to update room: %v", err)
+	}
+
+	owner := connect(t, hub, "owner", room.ID)
+	voter := connect(t, hub, "user2", room.ID)
+
+	send(hub, voter, MsgVote, map[string]any{"ticket_id": "ticket-1"})
+	receive(t, voter, MsgVoteUpdated)
+
+	// Deleting the ticket gives the vote back, and it is spent elsewhere
+	send(hub, owner, MsgDeleteTicket, map[string]any{"ticket_id": "ticket-1"})
+	receive(t, voter, MsgTicketDeleted)
+	send(hub, voter, MsgVote, map[string]any{"ticket_id": "ticket-2"})
+	receive(t, voter, MsgVoteUpdated)
+
+	send(hub, owner, MsgUndo, nil)
+	restored := receive(t, owner, MsgTicketAdded).Payload["ticket"].(map[string]any)
+	if restored["votes"] != float64(0) {
+		t.Errorf("Expected the re-spent vote to be dropped, got %v", restored["votes"])
+	}
+
+	stored, _ := store.Get(room.ID)
+	if participant, _ := stored.GetParticipant("user2"); participant.VotesUsed != 1 {
+		t.Errorf("Expected the voter to stay within their votes, got %d", participant.VotesUsed)
+	}
+	if ticket, _ := stored.GetTicket("ticket-1"); ticket.Votes != 0 {
+		t.Errorf("Expected the restored ticket to be stored without the vote, got %d", ticket.Votes)
+	}
+}
+
+func TestHub_UndoUsesRoleAtTheTime(t *testing.T) {
+	hub, store, room := newTestHub(t, "owner", "user2")
+
+	owner := connect(t, hub, "owner", room.ID)
+	moderator := connect(t, hub, "user2", room.ID)
+
+	send(hub, owner, MsgSetRole, map[string]any{"user_id": "user2", "role": string(models.RoleModerator)})
+	receive(t, owner, MsgRoleChanged)
+	send(hub, moderator, MsgSetPhase, map[string]any{"phase": string(models.PhaseMerging)})
+	receive(t, owner, MsgPhaseChanged)
+
+	// The phase change stays undoable after its author is demoted
+	send(hub, owner, MsgSetRole, map[string]any{"user_id": "user2", "role": string(models.RoleParticipant)})
+	receive(t, owner, MsgRoleChanged)
+	send(hub, owner, MsgUndo, nil)
+	if phase := receive(t, owner, MsgPhaseChanged).Payload["phase"]; phase != string(models.PhaseTicketing) {
+		t.Errorf("Expected phase to be restored to TICKETING, got %v", phase)
+	}
+
+	stored, _ := store.Get(room.ID)
+	if stored.Phase != models.PhaseTicketing {
+		t.Errorf("Expected stored phase TICKETING, got %s", stored.Phase)
+	}
+}
+
+func TestHub_HiddenVotesUntilReveal(t *testing.T) {
+	hub, store, room := newTestHub(t, "owner", "user2")
+
+	room.HiddenVotes = true
+	room.SetPhase(models.PhaseVoting)
+	room.AddTicket(&models.Ticket{ID: "ticket-1", Content: "Ticket", AuthorID: "owner", VoterIDs: []string{}, CreatedAt: time.Now()})
+	if err := store.Update(room); err != nil {
+		t.Fatalf("Failed to update room: %v", err)
+	}
+
+	owner := connect(t, hub, "owner", room.ID)
+	voter := connect(t, hub, "user2", room.ID)
+
+	send(hub, voter, MsgVote, map[string]any{"ticket_id": "ticket-1"})
+	if votes := receive(t, voter, MsgVoteUpdated).Payload["votes"]; votes != float64(1) {
+		t.Errorf("Expected voter to see their own vote, got %v", votes)
+	}
+
+	// The owner only ever hears about their own vote
+	send(hub, owner, MsgVote, map[string]any{"ticket_id": "ticket-1"})
+	update := receive(t, owner, MsgVoteUpdated)
+	if update.Payload["user_id"] != "owner" || update.Payload["votes"] != float64(1) {
+		t.Errorf("Expected owner to only see their own vote, got %v", update.Payload)
+	}
+
+	// Reconnecting clients get the same filtered view
+	stored, _ := store.Get(room.ID)
+	hub.SendRoomState(owner, stored)
+	tickets := receive(t, owner, MsgRoomState).Payload["tickets"].(map[string]any)
+	ticket := tickets["ticket-1"].(map[string]any)
+	if ticket["votes"] != float64(1) || len(ticket["voter_ids"].([]any)) != 1 {
+		t.Errorf("Expected room state to only carry the owner's vote, got %v", ticket)
+	}
+
+	send(hub, owner, MsgRevealVotes, nil)
+	for _, client := range []*Client{owner, voter} {
+		tickets := receive(t, client, MsgVotesRevealed).Payload["tickets"].(map[string]any)
+		if votes := tickets["ticket-1"].(map[string]any)["votes"]; votes != float64(2) {
+			t.Errorf("Expected %s to see all votes after reveal, got %v", client.ID, votes)
+		}
+	}
+
+	stored, _ = store.Get(room.ID)
+	if !stored.VotesRevealed {
+		t.Error("Expected revealed votes to be stored")
+	}
+}
+
+func TestHub_AnonymousTickets(t *testing.T) {
+	hub, store, room := newTestHub(t, "owner", "user2")
+
+	room.AnonymousTickets = true
+	if err := store.Update(room); err != nil {
+		t.Fatalf("Failed to update room: %v", err)
+	}
+
+	owner := connect(t, hub, "owner", room.ID)
+	author := connect(t, hub, "user2", room.ID)
+
+	send(hub, author, MsgAddTicket, map[string]any{"content": "Ticket"})
+
+	own := receive(t, author, MsgTicketAdded).Payload["ticket"].(map[string]any)
+	if own["author_id"] != "user2" {
+		t.Errorf("Expected the author to see their own ticket as theirs, got %v", own["author_id"])
+	}
+	other := receive(t, owner, MsgTicketAdded).Payload["ticket"].(map[string]any)
+	if other["author_id"] != "" {
+		t.Errorf("Expected the author to be hidden from others, got %v", other["author_id"])
+	}
+
+	// The author can still delete their ticket
+	send(hub, author, MsgDeleteTicket, map[string]any{"ticket_id": own["id"]})
+	receive(t, owner, MsgTicketDeleted)
+}
+
+func TestHub_BlindTickets(t *testing.T) {
+	hub, store, room := newTestHub(t, "owner", "user2", "user3")
+
+	room.BlindTickets = true
+	if err := store.Update(room); err != nil {
+		t.Fatalf("Failed to update room: %v", err)
+	}
+
+	owner := connect(t, hub, "owner", room.ID)
+	author := connect(t, hub, "user2", room.ID)
+	other := connect(t, hub, "user3", room.ID)
+
+	send(hub, author, MsgAddTicket, map[string]any{"content": "Ticket"})
+	receive(t, author, MsgTicketAdded)
+	if count := receive(t, owner, MsgHiddenTickets).Payload["count"]; count != 1.0 {
+		t.Errorf("Expected the moderator to be told one ticket is hidden, got %v", count)
+	}
+
+	// The other participant's next message is the reveal, not the ticket
+	send(hub, owner, MsgRevealTickets, nil)
+	raw := <-other.Send
+	var msg Message
+	json.Unmarshal(raw, &msg)
+	if msg.Type != MsgTicketsRevealed {
+		t.Fatalf("Expected the first message to be the reveal, got %s", msg.Type)
+	}
+	if tickets, _ := msg.Payload["tickets"].(map[string]any); len(tickets) != 1 {
+		t.Errorf("Expected the revealed ticket, got %v", msg.Payload["tickets"])
+	}
+
+	// Later tickets are broadcast as usual
+	send(hub, author, MsgAddTicket, map[string]any{"content": "Another"})
+	receive(t, other, MsgTicketAdded)
+
+	stored, _ := store.Get(room.ID)
+	if !stored.TicketsRevealed {
+		t.Error("Expected the reveal to be stored")
+	}
+}
+
+func TestHub_Reactions(t *testing.T) {
+	hub, store, room := newTestHub(t, "owner", "user2")
+
+	owner := connect(t, hub, "owner", room.ID)
+	member := connect(t, hub, "user2", room.ID)
+
+	send(hub, owner, MsgAddTicket, map[string]any{"content": "Ticket"})
+	ticketID := receive(t, member, MsgTicketAdded).Payload["ticket"].(map[string]any)["id"]
+
+	send(hub, member, MsgAddReaction, map[string]any{"ticket_id": ticketID, "emoji": "not an emoji"})
+	receive(t, member, MsgError)
+
+	send(hub, member, MsgAddReaction, map[string]any{"ticket_id": ticketID, "emoji": "🎉"})
+	updated := receive(t, owner, MsgReactionUpdated)
+	if counts, _ := updated.Payload["counts"].(map[string]any); counts["🎉"] != 1.0 || updated.Payload["user_id"] != "user2" {
+		t.Errorf("Expected one 🎉 reaction by user2, got %v", updated.Payload)
+	}
+
+	stored, _ := store.Get(room.ID)
+	hub.SendRoomState(owner, stored)
+	counts, _ := receive(t, owner, MsgRoomState).Payload["reaction_counts"].(map[string]any)
+	if ticketCounts, _ := counts[ticketID.(string)].(map[string]any); ticketCounts["🎉"] != 1.0 {
+		t.Errorf("Expected the reaction in the room state, got %v", counts)
+	}
+
+	send(hub, member, MsgRemoveReaction, map[string]any{"ticket_id": ticketID, "emoji": "🎉"})
+	if counts, _ := receive(t, owner, MsgReactionUpdated).Payload["counts"].(map[string]any); len(counts) != 0 {
+		t.Errorf("Expected no reactions left, got %v", counts)
+	}
+}
+
+func TestHub_Comments(t *testing.T) {
+	hub, store, room := newTestHub(t, "owner", "user2", "user3")
+
+	owner := connect(t, hub, "owner", room.ID)
+	author := connect(t, hub, "user2", room.ID)
+	other := connect(t, hub, "user3", room.ID)
+
+	send(hub, owner, MsgAddTicket, map[string]any{"content": "Ticket"})
+	ticketID := receive(t, author, MsgTicketAdded).Payload["ticket"].(map[string]any)["id"]
+	receive(t, other, MsgTicketAdded)
+
+	send(hub, author, MsgAddComment, map[string]any{"ticket_id": ticketID, "content": "   "})
+	receive(t, author, MsgError)
+
+	send(hub, author, MsgAddComment, map[string]any{"ticket_id": ticketID, "content": " Good point "})
+	added := receive(t, other, MsgCommentAdded).Payload["comment"].(map[string]any)
+	if added["content"] != "Good point" || added["author_id"] != "user2" {
+		t.Errorf("Expected the trimmed comment by user2, got %v", added)
+	}
+	commentID := added["id"]
+
+	// Only the author and moderators can change a comment
+	send(hub, other, MsgEditComment, map[string]any{"ticket_id": ticketID, "comment_id": commentID, "content": "Hijacked"})
+	receive(t, other, MsgError)
+
+	send(hub, author, MsgEditComment, map[string]any{"ticket_id": ticketID, "comment_id": commentID, "content": "Better point"})
+	edited := receive(t, other, MsgCommentUpdated).Payload["comment"].(map[string]any)
+	if edited["content"] != "Better point" || edited["updated_at"] == nil {
+		t.Errorf("Expected the edited comment, got %v", edited)
+	}
+
+	stored, _ := store.Get(room.ID)
+	ticket, _ := stored.GetTicket(ticketID.(string))
+	if len(ticket.Comments) != 1 || ticket.Comments[0].Content != "Better point" {
+		t.Errorf("Expected the edited comment to be stored, got %v", ticket.Comments)
+	}
+
+	send(hub, owner, MsgDeleteComment, map[string]any{"ticket_id": ticketID, "comment_id": commentID})
+	if deleted := receive(t, author, MsgCommentDeleted).Payload; deleted["comment_id"] != commentID {
+		t.Errorf("Expected the comment to be deleted, got %v", deleted)
+	}
+	stored, _ = store.Get(room.ID)
+	ticket, _ = stored.GetTicket(ticketID.(string))
+	if len(ticket.Comments) != 0 {
+		t.Errorf("Expected no comments left, got %v", ticket.Comments)
+	}
+}
+
+func TestHub_UpdateAction(t *testing.T) {
+	hub, store, room := newTestHub(t, "owner", "user2", "user3")
+
+	owner := connect(t, hub, "owner", room.ID)
+	assignee := connect(t, hub, "user2", room.ID)
+	other := connect(t, hub, "user3", room.ID)
+
+	room.ActionTickets["action-1"] = &models.ActionTicket{ID: "action-1", Content: "Fix it", Status: models.ActionOpen, AssigneeIDs: []string{"user2"}, CreatedAt: time.Now()}
+	if err := store.Update(room); err != nil {
+		t.Fatalf("Failed to update room: %v", err)
+	}
+
+	send(hub, other, MsgUpdateAction, map[string]any{"action_id": "action-1", "status": "done"})
+	receive(t, other, MsgError)
+
+	send(hub, assignee, MsgUpdateAction, map[string]any{"action_id": "action-1", "status": "finished"})
+	receive(t, assignee, MsgError)
+
+	// Assignees can update actions outside the discussion phase
+	send(hub, assignee, MsgUpdateAction, map[string]any{"action_id": "action-1", "status": "done", "owner_id": "user2", "due_date": "2026-11-02"})
+	action := receive(t, owner, MsgActionUpdated).Payload["action"].(map[string]any)
+	if action["status"] != "done" || action["owner_id"] != "user2" || action["completed_at"] == nil {
+		t.Errorf("Expected the action to be done and owned by user2, got %v", action)
+	}
+
+	send(hub, owner, MsgUpdateAction, map[string]any{"action_id": "action-1", "owner_id": "stranger"})
+	receive(t, owner, MsgError)
+
+	stored, _ := store.Get(room.ID)
+	updated, _ := stored.GetActionTicket("action-1")
+	if updated.Status != models.ActionDone || updated.DueDate == nil || updated.DueDate.Format(models.DueDateLayout) != "2026-11-02" {
+		t.Errorf("Expected the update to be stored, got %+v", updated)
+	}
+	if len(updated.StatusHistory) != 1 || updated.StatusHistory[0].ChangedBy != "user2" {
+		t.Errorf("Expected the status change to be recorded, got %+v", updated.StatusHistory)
+	}
+}
+
+func TestHub_ReviewCarriedActions(t *testing.T) {
+	hub, store, previous := newTestHub(t, "owner", "user2")
+
+	previous.ActionTickets["action-1"] = &models.ActionTicket{ID: "action-1", Content: "Fix it", Status: models.ActionOpen, CreatedAt: time.Now()}
+	if err := store.Update(previous); err != nil {
+		t.Fatalf("Failed to update room: %v", err)
+	}
+
+	room := models.NewRoom("room-2", "Next Room", "owner", 5)
+	room.CarryOverActions(previous, func() string { return "carried-1" })
+	room.AddParticipant(models.User{ID: "owner", Name: "owner"}, models.RoleOwner, models.StatusApproved)
+	room.AddParticipant(models.User{ID: "user2", Name: "user2"}, models.RoleParticipant, models.StatusApproved)
+	if err := store.Create(room); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+
+	owner := connect(t, hub, "owner", room.ID)
+	member := connect(t, hub, "user2", room.ID)
+	watcher := connect(t, hub, "user2", previous.ID)
+
+	send(hub, member, MsgReviewAction, map[string]any{"action_id": "carried-1", "outcome": "done"})
+	receive(t, member, MsgError)
+
+	send(hub, owner, MsgReviewAction, map[string]any{"action_id": "carried-1", "outcome": "done"})
+	if action := receive(t, member, MsgActionUpdated).Payload["action"].(map[string]any); action["review_outcome"] != "done" || action["status"] != "done" {
+		t.Errorf("Expected the carried over action to be done, got %v", action)
+	}
+
+	// The outcome is written back to the previous room
+	if action := receive(t, watcher, MsgActionUpdated).Payload["action"].(map[string]any); action["id"] != "action-1" || action["status"] != "done" {
+		t.Errorf("Expected the original action to be done, got %v", action)
+	}
+	stored, _ := store.Get(previous.ID)
+	if original, _ := stored.GetActionTicket("action-1"); original.Status != models.ActionDone || original.ReviewOutcome != models.ReviewDone {
+		t.Errorf("Expected the outcome to be stored on the original action, got %+v", original)
+	}
+
+	send(hub, owner, MsgReviewAction, map[string]any{"action_id": "carried-1", "outcome": "dropped"})
+	receive(t, owner, MsgError)
+}
+
+func TestHub_TicketColumns(t *testing.T) {
+	hub, _, room := newTestHub(t, "owner")
+
+	client := connect(t, hub, "owner", room.ID)
+
+	send(hub, client, MsgAddTicket, map[string]any{"content": "Ticket", "column_id": "missing"})
+	if msg := receive(t, client, MsgError); msg.Payload["message"] != "Column not found" {
+		t.Errorf("Expected unknown column to be rejected, got %v", msg.Payload["message"])
+	}
+
+	send(hub, client, MsgAddTicket, map[string]any{"content": "Ticket", "column_id": "ideas"})
+	ticket := receive(t, client, MsgTicketAdded).Payload["ticket"].(map[string]any)
+	if ticket["column_id"] != "ideas" {
+		t.Errorf("Expected ticket in column ideas, got %v", ticket["column_id"])
+	}
+
+	send(hub, client, MsgEditTicket, map[string]any{"ticket_id": ticket["id"], "column_id": "to-improve"})
+	moved := receive(t, client, MsgTicketUpdated).Payload["ticket"].(map[string]any)
+	if moved["column_id"] != "to-improve" {
+		t.Errorf("Expected ticket moved to to-improve, got %v", moved["column_id"])
+	}
+
+	// A column that still holds tickets can't be removed
+	send(hub, client, MsgSetColumns, map[string]any{"columns": []any{
+		map[string]any{"id": "went-well", "title": "Went well", "color": "#22c55e"},
+	}})
+	receive(t, client, MsgError)
+
+	send(hub, client, MsgSetColumns, map[string]any{"columns": []any{
+		map[string]any{"id": "to-improve", "title": "Improve", "color": "#ef4444"},
+		map[string]any{"title": "Kudos", "color": "#eab308"},
+	}})
+	columns := receive(t, client, MsgColumnsChanged).Payload["columns"].([]any)
+	if len(columns) != 2 || columns[1].(map[string]any)["id"] == "" {
+		t.Errorf("Expected two columns with IDs, got %v", columns)
+	}
+}
+
+func TestHub_AutoMergeKeepsColumns(t *testing.T) {
+	hub, store, room := newTestHub(t, "owner")
+
+	room.SetPhase(models.PhaseMerging)
+	for id, columnID := range map[string]string{"ticket-1": "ideas", "ticket-2": "ideas", "ticket-3": "to-improve"} {
+		room.AddTicket(&models.Ticket{ID: id, Content: "Ticket", AuthorID: "owner", ColumnID: columnID, VoterIDs: []string{}, CreatedAt: time.Now()})
+	}
+	if err := store.Update(room); err != nil {
+		t.Fatalf("Failed to update room: %v", err)
+	}
+
+	client := connect(t, hub, "owner", room.ID)
+
+	hub.enqueue(room.ID, func(a *roomActor) {
+		hub.applyMergeSuggestions(a, client, &chatcompletion.AutoMergeResponse{
+			MergeGroups: []chatcompletion.MergeGroup{
+				{ParentTicketID: "ticket-1", ChildTicketIDs: []string{"ticket-2", "ticket-3"}},
+			},
+		})
+	})
+	if applied := receive(t, client, MsgAutoMergeComplete).Payload["merges_applied"]; applied != float64(1) {
+		t.Errorf("Expected only the ticket in the same column to be merged, got %v merges", applied)
+	}
+
+	stored, _ := store.Get(room.ID)
+	if ticket, _ := stored.GetTicket("ticket-2"); ticket.DeduplicationTicketID == nil {
+		t.Error("Expected ticket-2 to be merged into ticket-1")
+	}
+	if ticket, _ := stored.GetTicket("ticket-3"); ticket.DeduplicationTicketID != nil {
+		t.Errorf("Expected ticket-3 in another column to stay unmerged, got parent %s", *ticket.DeduplicationTicketID)
+	}
+}
+
+func TestHub_PhasePipeline(t *testing.T) {
+	hub, store, room := newTestHub(t, "owner")
+
+	client := connect(t, hub, "owner", room.ID)
+
+	send(hub, client, MsgSetPhases, map[string]any{"phases": []any{"TICKETING", "VOTING", "SUMMARY", "CHECKOUT"}})
+	if phases := receive(t, client, MsgPhasesChanged).Payload["phases"].([]any); len(phases) != 4 {
+		t.Errorf("Expected four phases, got %v", phases)
+	}
+
+	// Skipped phases can't be entered
+	send(hub, client, MsgSetPhase, map[string]any{"phase": string(models.PhaseDiscussion)})
+	if msg := receive(t, client, MsgError); msg.Payload["message"] != "Invalid phase" {
+		t.Errorf("Expected skipped phase to be rejected, got %v", msg.Payload["message"])
+	}
+
+	// Without a discussion phase, actions are added during the summary
+	send(hub, client, MsgSetPhase, map[string]any{"phase": string(models.PhaseSummary)})
+	activities := receive(t, client, MsgPhaseChanged).Payload["activities"].([]any)
+	if len(activities) != 3 || activities[0] != string(models.ActivityManageActions) {
+		t.Errorf("Expected actions, covering tickets and reactions to be allowed, got %v", activities)
+	}
+	send(hub, client, MsgAddAction, map[string]any{"content": "Do it"})
+	receive(t, client, MsgActionAdded)
+
+	// The current phase can't be dropped
+	send(hub, client, MsgSetPhases, map[string]any{"phases": []any{"TICKETING", "VOTING"}})
+	receive(t, client, MsgError)
+
+	stored, _ := store.Get(room.ID)
+	if stored.HasPhase(models.PhaseMerging) || !stored.HasPhase(models.PhaseCheckout) {
+		t.Errorf("Expected stored phases to be updated, got %v", stored.Phases)
+	}
+}
+
+func TestHub_PhaseTransitions(t *testing.T) {
+	hub, store, room := newTestHub(t, "owner")
+
+	client := connect(t, hub, "owner", room.ID)
+
+	// Voting needs something to vote on
+	send(hub, client, MsgSetPhase, map[string]any{"phase": string(models.PhaseVoting)})
+	receive(t, client, MsgError)
+
+	send(hub, client, MsgAddTicket, map[string]any{"content": "Ticket"})
+	receive(t, client, MsgTicketAdded)
+	send(hub, client, MsgSetPhase, map[string]any{"phase": string(models.PhaseVoting)})
+	changed := receive(t, client, MsgPhaseChanged)
+	if changed.Payload["previous_phase"] != string(models.PhaseTicketing) || changed.Payload["entered_at"] == nil {
+		t.Errorf("Expected the previous phase and timing, got %v", changed.Payload)
+	}
+
+	// Going back has to be confirmed
+	send(hub, client, MsgSetPhase, map[string]any{"phase": string(models.PhaseTicketing)})
+	if phase := receive(t, client, MsgConfirmPhaseChange).Payload["phase"]; phase != string(models.PhaseTicketing) {
+		t.Errorf("Expected confirmation to be asked for TICKETING, got %v", phase)
+	}
+	send(hub, client, MsgSetPhase, map[string]any{"phase": string(models.PhaseTicketing), "confirm": true})
+	if phase := receive(t, client, MsgPhaseChanged).Payload["phase"]; phase != string(models.PhaseTicketing) {
+		t.Errorf("Expected the room to go back to TICKETING, got %v", phase)
+	}
+
+	stored, _ := store.Get(room.ID)
+	if len(stored.PhaseHistory) != 3 || stored.PhaseHistory[1].Phase != models.PhaseVoting {
+		t.Errorf("Expected the phase history to be stored, got %+v", stored.PhaseHistory)
+	}
+}
+
+func TestHub_Timer(t *testing.T) {
+	hub, store, room := newTestHub(t, "owner", "member")
+
+	owner := connect(t, hub, "owner", room.ID)
+	member := connect(t, hub, "member", room.ID)
+
+	send(hub, member, MsgStartTimer, map[string]any{"seconds": 60.0})
+	receive(t, member, MsgError)
+
+	send(hub, owner, MsgStartTimer, map[string]any{"seconds": 300.0})
+	updated := receive(t, member, MsgTimerUpdated)
+	timer, _ := updated.Payload["timer"].(map[string]any)
+	if updated.Payload["action"] != "started" || updated.Payload["server_time"] == nil || timer["ends_at"] == nil || timer["running"] != true {
+		t.Fatalf("Expected a running timer with the server time, got %v", updated.Payload)
+	}
+
+	send(hub, owner, MsgPauseTimer, nil)
+	timer, _ = receive(t, member, MsgTimerUpdated).Payload["timer"].(map[string]any)
+	if timer["running"] != false || timer["ends_at"] != nil {
+		t.Errorf("Expected a paused timer, got %v", timer)
+	}
+	send(hub, owner, MsgExtendTimer, map[string]any{"seconds": 60.0})
+	timer, _ = receive(t, member, MsgTimerUpdated).Payload["timer"].(map[string]any)
+	if timer["duration_seconds"] != 360.0 {
+		t.Errorf("Expected the timer to be extended to 6 minutes, got %v", timer)
+	}
+	send(hub, owner, MsgStopTimer, nil)
+	if timer := receive(t, member, MsgTimerUpdated).Payload["timer"]; timer != nil {
+		t.Errorf("Expected the timer to be removed, got %v", timer)
+	}
+
+	// An expiring timer with auto-advance moves the room on
+	send(hub, owner, MsgStartTimer, map[string]any{"seconds": 1.0, "auto_advance": true})
+	receive(t, member, MsgTimerUpdated)
+	if action := receive(t, member, MsgTimerUpdated).Payload["action"]; action != "expired" {
+		t.Errorf("Expected the timer to expire, got %v", action)
+	}
+	if phase := receive(t, member, MsgPhaseChanged).Payload["phase"]; phase != string(models.PhaseMerging) {
+		t.Errorf("Expected the room to move to MERGING, got %v", phase)
+	}
+
+	stored, _ := store.Get(room.ID)
+	if stored.Phase != models.PhaseMerging || stored.Timer != nil {
+		t.Errorf("Expected the stored room to be in MERGING without a timer, got %s %+v", stored.Phase, stored.Timer)
+	}
+}
+
+func TestHub_Polls(t *testing.T) {
+	hub, _, room := newTestHub(t, "owner", "user2")
+
+	owner := connect(t, hub, "owner", room.ID)
+	member := connect(t, hub, "user2", room.ID)
+
+	// Without an icebreaker, the mood is checked while writing tickets
+	send(hub, member, MsgAnswerPoll, map[string]any{"kind": "roti", "score": 4})
+	receive(t, member, MsgError)
+	send(hub, member, MsgAnswerPoll, map[string]any{"kind": "mood", "score": 2.5})
+	receive(t, member, MsgError)
+
+	send(hub, member, MsgAnswerPoll, map[string]any{"kind": "mood", "score": 4})
+	if poll := receive(t, member, MsgPollUpdated).Payload["poll"].(map[string]any); poll["responses"] != float64(1) || poll["answered"] != true || poll["histogram"] != nil {
+		t.Errorf("Expected the answer to be counted without results, got %v", poll)
+	}
+	if poll := receive(t, owner, MsgPollUpdated).Payload["poll"].(map[string]any); poll["answered"] != false {
+		t.Errorf("Expected the owner not to have answered, got %v", poll)
+	}
+
+	send(hub, member, MsgClosePoll, map[string]any{"kind": "mood"})
+	receive(t, member, MsgError)
+
+	send(hub, owner, MsgClosePoll, map[string]any{"kind": "mood"})
+	poll := receive(t, member, MsgPollUpdated).Payload["poll"].(map[string]any)
+	if poll["closed"] != true || poll["average"] != float64(4) {
+		t.Errorf("Expected the results once closed, got %v", poll)
+	}
+	if histogram := poll["histogram"].([]any); histogram[3] != float64(1) {
+		t.Errorf("Expected the answer in the histogram, got %v", histogram)
+	}
+
+	send(hub, owner, MsgAnswerPoll, map[string]any{"kind": "mood", "score": 3})
+	if msg := receive(t, owner, MsgError); msg.Payload["message"] != "The poll is closed" {
+		t.Errorf("Expected closed polls to reject answers, got %v", msg.Payload["message"])
+	}
+}
+
+func TestHub_HealthCheck(t *testing.T) {
+	hub, store, room := newTestHub(t, "owner", "user2")
+
+	owner := connect(t, hub, "owner", room.ID)
+	member := connect(t, hub, "user2", room.ID)
+
+	template, _ := models.BuiltinTemplate("squad-health-check")
+	if err := template.ApplyTo(room); err != nil {
+		t.Fatalf("Failed to apply template: %v", err)
+	}
+	if err := store.Update(room); err != nil {
+		t.Fatalf("Failed to update room: %v", err)
+	}
+
+	send(hub, member, MsgRateHealth, map[string]any{"dimension_id": "fun", "color": "purple", "trend": "up"})
+	receive(t, member, MsgError)
+
+	send(hub, member, MsgRateHealth, map[string]any{"dimension_id": "fun", "color": "green", "trend": "up"})
+	check := receive(t, owner, MsgHealthUpdated).Payload["health_check"].(map[string]any)
+	if check["respondents"] != float64(1) || len(check["ratings"].(map[string]any)) != 0 {
+		t.Errorf("Expected the owner to see the result but no rating of their own, got %v", check)
+	}
+	check = receive(t, member, MsgHealthUpdated).Payload["health_check"].(map[string]any)
+	if rating := check["ratings"].(map[string]any)["fun"].(map[string]any); rating["color"] != "green" {
+		t.Errorf("Expected the member to see their rating, got %v", rating)
+	}
+
+	// Ratings are closed once the health check phase is over
+	send(hub, owner, MsgSetPhase, map[string]any{"phase": "TICKETING"})
+	receive(t, owner, MsgPhaseChanged)
+	send(hub, member, MsgRateHealth, map[string]any{"dimension_id": "fun", "color": "red", "trend": "down"})
+	if msg := receive(t, member, MsgError); msg.Payload["message"] != "Health can't be rated in the current phase" {
+		t.Errorf("Expected ratings to be closed, got %v", msg.Payload["message"])
+	}
+}
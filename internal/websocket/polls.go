@@ -0,0 +1,102 @@
+package websocket
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/Armatorix/GoRetro/internal/models"
+)
+
+// handleAnswerPoll records a participant's anonymous mood or ROTI score.
+// Only the number of answers is shared until a moderator closes the poll.
+func (h *Hub) handleAnswerPoll(client *Client, room *models.Room, payload map[string]any) error {
+	kindStr, _ := payload["kind"].(string)
+	kind := models.PollKind(kindStr)
+	if err := models.ValidatePollKind(kind); err != nil {
+		h.sendError(client, "Invalid poll")
+		return nil
+	}
+	if !room.Allows(kind.Activity()) {
+		h.sendError(client, "This poll can't be answered in the current phase")
+		return nil
+	}
+
+	value, ok := payload["score"].(float64)
+	if !ok || value != math.Trunc(value) {
+		h.sendError(client, "Invalid score")
+		return nil
+	}
+	score := int(value)
+
+	if err := room.AnswerPoll(kind, client.ID, score); err != nil {
+		switch {
+		case errors.Is(err, models.ErrPollClosed):
+			h.sendError(client, "The poll is closed")
+		case errors.Is(err, models.ErrAlreadyAnswered):
+			h.sendError(client, "You already answered this poll")
+		default:
+			h.sendError(client, "Invalid score")
+		}
+		return nil
+	}
+
+	// Persist to database
+	if err := h.store.AnswerPoll(room, kind, client.ID, score); err != nil {
+		return h.persistError(client, err, "Failed to save answer")
+	}
+	h.RecordEvent(room.ID, models.EventPollAnswered, client.ID, map[string]any{
+		"kind": kind,
+	})
+
+	h.broadcastPoll(room, kind)
+
+	return nil
+}
+
+// handleClosePoll stops a poll from taking answers and shares its results
+func (h *Hub) handleClosePoll(client *Client, room *models.Room, payload map[string]any) error {
+	if !room.IsModeratorOrOwner(client.ID) {
+		h.sendError(client, "Only moderators can close polls")
+		return nil
+	}
+
+	kindStr, _ := payload["kind"].(string)
+	kind := models.PollKind(kindStr)
+	if err := models.ValidatePollKind(kind); err != nil {
+		h.sendError(client, "Invalid poll")
+		return nil
+	}
+
+	if !room.ClosePoll(kind, time.Now()) {
+		h.sendError(client, "The poll is already closed")
+		return nil
+	}
+
+	// Persist to database
+	if err := h.store.ClosePoll(room, kind); err != nil {
+		return h.persistError(client, err, "Failed to close poll")
+	}
+	h.RecordEvent(room.ID, models.EventPollClosed, client.ID, map[string]any{
+		"kind":      kind,
+		"histogram": room.GetPoll(kind).Histogram,
+	})
+
+	h.broadcastPoll(room, kind)
+
+	return nil
+}
+
+// broadcastPoll sends approved participants the poll as they may see it:
+// whether they answered it themselves differs from one to another
+func (h *Hub) broadcastPoll(room *models.Room, kind models.PollKind) {
+	poll := room.GetPoll(kind)
+	h.broadcastToEachParticipant(room, func(viewerID string) (Message, bool) {
+		return Message{
+			Type: MsgPollUpdated,
+			Payload: map[string]any{
+				"poll": poll.View(viewerID),
+			},
+		}, true
+	})
+}
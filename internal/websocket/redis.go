@@ -23,6 +23,7 @@ type RedisMessage struct {
 	ExceptClientID   string `json:"except_client_id,omitempty"`
 	SpecificClientID string `json:"specific_client_id,omitempty"`
 	ApprovedOnly     bool   `json:"approved_only"`
+	TimerChanged     bool   `json:"timer_changed,omitempty"`
 }
 
 // NewRedisPubSub creates a new Redis pub/sub manager
@@ -68,7 +69,11 @@ func (r *RedisPubSub) handleRedisMessage(msg *redis.Message) {
 	}
 
 	// Broadcast to local clients based on the message type
-	if redisMsg.SpecificClientID != "" {
+	if redisMsg.TimerChanged {
+		// Arm the room's timer here too, so it still expires if the
+		// instance that started it goes away
+		r.hub.reloadTimer(redisMsg.RoomID)
+	} else if redisMsg.SpecificClientID != "" {
 		// Send to specific client
 		r.hub.sendToClientLocal(redisMsg.RoomID, redisMsg.SpecificClientID, redisMsg.Message)
 	} else if redisMsg.ExceptClientID != "" {
@@ -122,6 +127,15 @@ func (r *RedisPubSub) PublishToClient(roomID, clientID string, msg []byte) error
 	return r.publish(roomID, redisMsg)
 }
 
+// PublishTimerChanged tells other instances that a room's timer changed
+func (r *RedisPubSub) PublishTimerChanged(roomID string) error {
+	redisMsg := RedisMessage{
+		RoomID:       roomID,
+		TimerChanged: true,
+	}
+	return r.publish(roomID, redisMsg)
+}
+
 // publish sends a message to Redis
 func (r *RedisPubSub) publish(roomID string, redisMsg RedisMessage) error {
 	payload, err := json.Marshal(redisMsg)
@@ -0,0 +1,264 @@
+package websocket
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Armatorix/GoRetro/internal/models"
+)
+
+// defaultTimerExtension is how much time extend_timer adds when no seconds are given
+const defaultTimerExtension = time.Minute
+
+// timerExpiry is the expiry scheduled for a room's running timer
+type timerExpiry struct {
+	endsAt time.Time
+	timer  *time.Timer
+}
+
+func (h *Hub) handleStartTimer(client *Client, room *models.Room, payload map[string]any) error {
+	if !room.IsModeratorOrOwner(client.ID) {
+		h.sendError(client, "Only moderators can control the timer")
+		return nil
+	}
+
+	// Without a duration a paused timer is resumed
+	now := time.Now()
+	action := "started"
+	if seconds, ok := payload["seconds"].(float64); ok {
+		autoAdvance, _ := payload["auto_advance"].(bool)
+		if err := room.StartTimer(time.Duration(seconds*float64(time.Second)), autoAdvance, now); err != nil {
+			h.sendError(client, fmt.Sprintf("Invalid timer: %v", err))
+			return nil
+		}
+	} else {
+		action = "resumed"
+		if err := room.ResumeTimer(now); err != nil {
+			h.sendError(client, fmt.Sprintf("Can't resume timer: %v", err))
+			return nil
+		}
+	}
+
+	return h.saveTimer(client, room, action)
+}
+
+func (h *Hub) handlePauseTimer(client *Client, room *models.Room, payload map[string]any) error {
+	if !room.IsModeratorOrOwner(client.ID) {
+		h.sendError(client, "Only moderators can control the timer")
+		return nil
+	}
+
+	if err := room.PauseTimer(time.Now()); err != nil {
+		h.sendError(client, fmt.Sprintf("Can't pause timer: %v", err))
+		return nil
+	}
+
+	return h.saveTimer(client, room, "paused")
+}
+
+func (h *Hub) handleExtendTimer(client *Client, room *models.Room, payload map[string]any) error {
+	if !room.IsModeratorOrOwner(client.ID) {
+		h.sendError(client, "Only moderators can control the timer")
+		return nil
+	}
+
+	extra := defaultTimerExtension
+	if seconds, ok := payload["seconds"].(float64); ok {
+		extra = time.Duration(seconds * float64(time.Second))
+	}
+	if err := room.ExtendTimer(extra); err != nil {
+		h.sendError(client, fmt.Sprintf("Can't extend timer: %v", err))
+		return nil
+	}
+
+	return h.saveTimer(client, room, "extended")
+}
+
+func (h *Hub) handleStopTimer(client *Client, room *models.Room, payload map[string]any) error {
+	if !room.IsModeratorOrOwner(client.ID) {
+		h.sendError(client, "Only moderators can control the timer")
+		return nil
+	}
+
+	if !room.StopTimer() {
+		h.sendError(client, "No timer is set")
+		return nil
+	}
+
+	return h.saveTimer(client, room, "stopped")
+}
+
+// saveTimer persists a timer change made by a client and tells the room about it
+func (h *Hub) saveTimer(client *Client, room *models.Room, action string) error {
+	if err := h.store.UpdateRoomSettings(room); err != nil {
+		return h.persistError(client, err, "Failed to save timer")
+	}
+	h.RecordEvent(room.ID, models.EventTimerChanged, client.ID, timerEventPayload(room, action))
+
+	h.broadcastTimer(room, action)
+
+	return nil
+}
+
+// timerEventPayload describes a timer change for the event log
+func timerEventPayload(room *models.Room, action string) map[string]any {
+	room.RLock()
+	defer room.RUnlock()
+	payload := map[string]any{"action": action}
+	if room.Timer != nil {
+		payload["phase"] = room.Timer.Phase
+		payload["duration_seconds"] = int(room.Timer.Duration.Seconds())
+	}
+	return payload
+}
+
+// timerPayload describes a timer for clients, or is nil when no timer is set.
+// Clients count down to ends_at, correcting for clock skew with the
+// server_time sent alongside.
+func timerPayload(timer *models.Timer, now time.Time) map[string]any {
+	if timer == nil {
+		return nil
+	}
+	payload := map[string]any{
+		"phase":            timer.Phase,
+		"duration_seconds": timer.Duration.Seconds(),
+		"remaining_ms":     timer.RemainingAt(now).Milliseconds(),
+		"running":          timer.Running(),
+		"auto_advance":     timer.AutoAdvance,
+	}
+	if timer.Running() {
+		payload["ends_at"] = timer.EndsAt
+	}
+	return payload
+}
+
+// broadcastTimer tells everyone in the room about the room's timer and lets
+// other instances re-arm its expiry
+func (h *Hub) broadcastTimer(room *models.Room, action string) {
+	now := time.Now()
+	room.RLock()
+	response := Message{
+		Type: MsgTimerUpdated,
+		Payload: map[string]any{
+			"action":      action,
+			"timer":       timerPayload(room.Timer, now),
+			"server_time": now,
+		},
+	}
+	room.RUnlock()
+	responseBytes, _ := json.Marshal(response)
+	h.BroadcastToApprovedParticipants(room.ID, responseBytes)
+
+	if h.redisPubSub != nil {
+		if err := h.redisPubSub.PublishTimerChanged(room.ID); err != nil {
+			log.Printf("Failed to publish to Redis: %v", err)
+		}
+	}
+}
+
+// armTimer schedules the expiry of the room's running timer, replacing the
+// one scheduled before. Every instance that loads the room arms its timer;
+// the room version makes sure only one of them applies the expiry.
+func (h *Hub) armTimer(room *models.Room) {
+	var endsAt time.Time
+	room.RLock()
+	if room.Timer != nil && room.Timer.Running() {
+		endsAt = room.Timer.EndsAt
+	}
+	room.RUnlock()
+
+	h.timersMu.Lock()
+	defer h.timersMu.Unlock()
+	if scheduled, ok := h.timers[room.ID]; ok {
+		if scheduled.endsAt.Equal(endsAt) {
+			return
+		}
+		scheduled.timer.Stop()
+		delete(h.timers, room.ID)
+	}
+	if endsAt.IsZero() {
+		return
+	}
+
+	roomID := room.ID
+	expiry := &timerExpiry{endsAt: endsAt}
+	expiry.timer = time.AfterFunc(time.Until(endsAt), func() {
+		// Forget the expiry first so that the room's timer is re-armed if it
+		// turns out to still be running
+		h.timersMu.Lock()
+		if h.timers[roomID] == expiry {
+			delete(h.timers, roomID)
+		}
+		h.timersMu.Unlock()
+
+		h.enqueue(roomID, func(a *roomActor) {
+			h.expireTimer(a, endsAt)
+		})
+	})
+	h.timers[roomID] = expiry
+}
+
+// reloadTimer re-arms a room's timer after it was changed on another instance
+func (h *Hub) reloadTimer(roomID string) {
+	h.enqueue(roomID, func(a *roomActor) {
+		a.invalidate()
+		a.current()
+	})
+}
+
+// expireTimer ends the room's timer that was due at endsAt and, if it was
+// started with auto-advance, moves the room to the next phase. It runs on the
+// room's actor and does nothing if the timer was changed in the meantime.
+func (h *Hub) expireTimer(a *roomActor, endsAt time.Time) {
+	var expired *models.Timer
+	var change *models.PhaseChange
+	err := a.apply(func(room *models.Room) error {
+		change = nil
+		expired = room.ExpireTimer(endsAt, time.Now())
+		if expired == nil {
+			return nil
+		}
+
+		if expired.AutoAdvance && expired.Phase == room.Phase {
+			if next, ok := room.NextPhase(); ok && room.CheckTransition(next, false) == nil {
+				c := room.SetPhase(next)
+				change = &c
+			}
+		}
+
+		if change != nil {
+			return h.store.ChangePhase(room)
+		}
+		return h.store.UpdateRoomSettings(room)
+	})
+	if err != nil {
+		if !errors.Is(err, models.ErrRoomNotFound) {
+			log.Printf("Failed to expire timer in room %s: %v", a.roomID, err)
+		}
+		return
+	}
+	if expired == nil {
+		return
+	}
+
+	room := a.current()
+	h.RecordEvent(room.ID, models.EventTimerChanged, "", map[string]any{
+		"action":           "expired",
+		"phase":            expired.Phase,
+		"duration_seconds": int(expired.Duration.Seconds()),
+	})
+	h.broadcastTimer(room, "expired")
+
+	if change != nil {
+		h.RecordEvent(room.ID, models.EventPhaseChanged, "", map[string]any{
+			"previous_phase":         change.From,
+			"phase":                  change.To,
+			"previous_phase_seconds": int(change.FromDuration().Seconds()),
+			"auto_advanced":          true,
+		})
+		h.broadcastPhase(room, *change)
+	}
+}
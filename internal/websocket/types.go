@@ -11,45 +11,86 @@ type MessageType string
 
 const (
 	// Client to server messages
-	MsgAddTicket          MessageType = "add_ticket"
-	MsgEditTicket         MessageType = "edit_ticket"
-	MsgDeleteTicket       MessageType = "delete_ticket"
-	MsgVote               MessageType = "vote"
-	MsgUnvote             MessageType = "unvote"
-	MsgAddAction          MessageType = "add_action"
-	MsgDeleteAction       MessageType = "delete_action"
-	MsgMarkCovered        MessageType = "mark_covered"
-	MsgSetPhase           MessageType = "set_phase"
-	MsgSetRole            MessageType = "set_role"
-	MsgRemoveUser         MessageType = "remove_user"
-	MsgApproveParticipant MessageType = "approve_participant"
-	MsgRejectParticipant  MessageType = "reject_participant"
-	MsgSetAutoApprove     MessageType = "set_auto_approve"
-	MsgAutoMergeTickets   MessageType = "auto_merge_tickets"
-	MsgAutoProposeActions MessageType = "auto_propose_actions"
+	MsgAddTicket            MessageType = "add_ticket"
+	MsgEditTicket           MessageType = "edit_ticket"
+	MsgDeleteTicket         MessageType = "delete_ticket"
+	MsgVote                 MessageType = "vote"
+	MsgUnvote               MessageType = "unvote"
+	MsgAddReaction          MessageType = "add_reaction"
+	MsgRemoveReaction       MessageType = "remove_reaction"
+	MsgAddComment           MessageType = "add_comment"
+	MsgEditComment          MessageType = "edit_comment"
+	MsgDeleteComment        MessageType = "delete_comment"
+	MsgAddAction            MessageType = "add_action"
+	MsgUpdateAction         MessageType = "update_action"
+	MsgReviewAction         MessageType = "review_action"
+	MsgDeleteAction         MessageType = "delete_action"
+	MsgMarkCovered          MessageType = "mark_covered"
+	MsgSetPhase             MessageType = "set_phase"
+	MsgSetPhases            MessageType = "set_phases"
+	MsgStartTimer           MessageType = "start_timer"
+	MsgPauseTimer           MessageType = "pause_timer"
+	MsgExtendTimer          MessageType = "extend_timer"
+	MsgStopTimer            MessageType = "stop_timer"
+	MsgSetRole              MessageType = "set_role"
+	MsgRemoveUser           MessageType = "remove_user"
+	MsgApproveParticipant   MessageType = "approve_participant"
+	MsgRejectParticipant    MessageType = "reject_participant"
+	MsgSetAutoApprove       MessageType = "set_auto_approve"
+	MsgAutoMergeTickets     MessageType = "auto_merge_tickets"
+	MsgAutoProposeActions   MessageType = "auto_propose_actions"
+	MsgUndo                 MessageType = "undo"
+	MsgSetMaxVotesPerTicket MessageType = "set_max_votes_per_ticket"
+	MsgSetHiddenVotes       MessageType = "set_hidden_votes"
+	MsgRevealVotes          MessageType = "reveal_votes"
+	MsgSetAnonymousTickets  MessageType = "set_anonymous_tickets"
+	MsgSetBlindTickets      MessageType = "set_blind_tickets"
+	MsgRevealTickets        MessageType = "reveal_tickets"
+	MsgSetColumns           MessageType = "set_columns"
+	MsgAnswerPoll           MessageType = "answer_poll"
+	MsgClosePoll            MessageType = "close_poll"
+	MsgRateHealth           MessageType = "rate_health"
 
 	// Server to client messages
-	MsgRoomState           MessageType = "room_state"
-	MsgUserJoined          MessageType = "user_joined"
-	MsgUserLeft            MessageType = "user_left"
-	MsgTicketAdded         MessageType = "ticket_added"
-	MsgTicketUpdated       MessageType = "ticket_updated"
-	MsgTicketDeleted       MessageType = "ticket_deleted"
-	MsgVoteUpdated         MessageType = "vote_updated"
-	MsgActionAdded         MessageType = "action_added"
-	MsgActionDeleted       MessageType = "action_deleted"
-	MsgPhaseChanged        MessageType = "phase_changed"
-	MsgRoleChanged         MessageType = "role_changed"
-	MsgUserRemoved         MessageType = "user_removed"
-	MsgParticipantPending  MessageType = "participant_pending"
-	MsgParticipantApproved MessageType = "participant_approved"
-	MsgParticipantRejected MessageType = "participant_rejected"
-	MsgAutoApproveChanged  MessageType = "auto_approve_changed"
-	MsgAutoMergeProgress   MessageType = "auto_merge_progress"
-	MsgAutoMergeComplete   MessageType = "auto_merge_complete"
-	MsgAutoProposeProgress MessageType = "auto_propose_progress"
-	MsgAutoProposeComplete MessageType = "auto_propose_complete"
-	MsgError               MessageType = "error"
+	MsgRoomState                MessageType = "room_state"
+	MsgUserJoined               MessageType = "user_joined"
+	MsgUserLeft                 MessageType = "user_left"
+	MsgTicketAdded              MessageType = "ticket_added"
+	MsgTicketUpdated            MessageType = "ticket_updated"
+	MsgTicketDeleted            MessageType = "ticket_deleted"
+	MsgVoteUpdated              MessageType = "vote_updated"
+	MsgReactionUpdated          MessageType = "reaction_updated"
+	MsgCommentAdded             MessageType = "comment_added"
+	MsgCommentUpdated           MessageType = "comment_updated"
+	MsgCommentDeleted           MessageType = "comment_deleted"
+	MsgActionAdded              MessageType = "action_added"
+	MsgActionUpdated            MessageType = "action_updated"
+	MsgActionDeleted            MessageType = "action_deleted"
+	MsgPhaseChanged             MessageType = "phase_changed"
+	MsgPhasesChanged            MessageType = "phases_changed"
+	MsgConfirmPhaseChange       MessageType = "confirm_phase_change"
+	MsgTimerUpdated             MessageType = "timer_updated"
+	MsgRoleChanged              MessageType = "role_changed"
+	MsgUserRemoved              MessageType = "user_removed"
+	MsgParticipantPending       MessageType = "participant_pending"
+	MsgParticipantApproved      MessageType = "participant_approved"
+	MsgParticipantRejected      MessageType = "participant_rejected"
+	MsgAutoApproveChanged       MessageType = "auto_approve_changed"
+	MsgMaxVotesPerTicketChanged MessageType = "max_votes_per_ticket_changed"
+	MsgHiddenVotesChanged       MessageType = "hidden_votes_changed"
+	MsgVotesRevealed            MessageType = "votes_revealed"
+	MsgAnonymousTicketsChanged  MessageType = "anonymous_tickets_changed"
+	MsgBlindTicketsChanged      MessageType = "blind_tickets_changed"
+	MsgTicketsRevealed          MessageType = "tickets_revealed"
+	MsgHiddenTickets            MessageType = "hidden_tickets"
+	MsgColumnsChanged           MessageType = "columns_changed"
+	MsgPollUpdated              MessageType = "poll_updated"
+	MsgHealthUpdated            MessageType = "health_updated"
+	MsgAutoMergeProgress        MessageType = "auto_merge_progress"
+	MsgAutoMergeComplete        MessageType = "auto_merge_complete"
+	MsgAutoProposeProgress      MessageType = "auto_propose_progress"
+	MsgAutoProposeComplete      MessageType = "auto_propose_complete"
+	MsgError                    MessageType = "error"
 )
 
 // Message represents a WebSocket message
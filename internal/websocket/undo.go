@@ -0,0 +1,246 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/Armatorix/GoRetro/internal/models"
+)
+
+// maxUndoCount caps how many actions a single undo command can revert
+const maxUndoCount = 20
+
+// eventPageSize is how many events are read from the store at a time when looking for actions to undo
+const eventPageSize = 100
+
+// undoableEvents are the moderator actions that can be reverted with undo
+var undoableEvents = map[models.EventType]bool{
+	models.EventTicketDeleted:  true,
+	models.EventActionDeleted:  true,
+	models.EventTicketMerged:   true,
+	models.EventTicketUnmerged: true,
+	models.EventPhaseChanged:   true,
+}
+
+func (h *Hub) handleUndo(client *Client, room *models.Room, payload map[string]any) error {
+	if !room.IsModeratorOrOwner(client.ID) {
+		h.sendError(client, "Only moderators can undo actions")
+		return nil
+	}
+
+	count := 1
+	if c, ok := payload["count"].(float64); ok {
+		count = int(c)
+	}
+	if count < 1 || count > maxUndoCount {
+		h.sendError(client, fmt.Sprintf("Count must be between 1 and %d", maxUndoCount))
+		return nil
+	}
+
+	events, err := h.undoCandidates(room, count)
+	if err != nil {
+		log.Printf("Failed to load events of room %s: %v", room.ID, err)
+		h.sendError(client, "Failed to load room history")
+		return nil
+	}
+	if len(events) == 0 {
+		h.sendError(client, "Nothing to undo")
+		return nil
+	}
+
+	// Decode every event before touching the room, so a bad payload can't
+	// leave the undo half applied
+	reverts := make([]undoPayload, len(events))
+	for i, event := range events {
+		if err := json.Unmarshal(event.Payload, &reverts[i]); err != nil {
+			log.Printf("Failed to decode %s event %d: %v", event.Type, event.ID, err)
+			h.sendError(client, "Failed to undo action")
+			return nil
+		}
+	}
+
+	// All reverts are applied to the room first and persisted together with
+	// the undo event, so the undo either happens as a whole, marker included,
+	// or not at all. Reverting an event that is already reverted in the room
+	// is a no-op, so the whole undo can be re-applied to a fresh copy of the
+	// room after a conflict.
+	undo := &models.Undo{}
+	undone := make([]int64, 0, len(events))
+	var broadcasts []func()
+	for i, event := range events {
+		if broadcast := h.revertEvent(room, event, reverts[i], undo); broadcast != nil {
+			broadcasts = append(broadcasts, broadcast)
+		}
+		undone = append(undone, event.ID)
+	}
+
+	undo.Event, err = models.NewRoomEvent(room.ID, models.EventUndo, client.ID, map[string]any{
+		"event_ids": undone,
+	})
+	if err == nil {
+		err = h.store.Undo(room, undo)
+	}
+	if err != nil {
+		return h.persistError(client, err, "Failed to undo action")
+	}
+
+	for _, broadcast := range broadcasts {
+		broadcast()
+	}
+
+	return nil
+}
+
+// undoCandidates returns up to count of the most recent undoable moderator
+// actions that have not been undone yet, newest first. Only undoable events
+// and undo markers are read, newest first, until enough candidates are found.
+// A marker is always newer than the events it undid, so they are known to be
+// undone by the time they are reached.
+func (h *Hub) undoCandidates(room *models.Room, count int) ([]*models.RoomEvent, error) {
+	types := []models.EventType{models.EventUndo}
+	for eventType := range undoableEvents {
+		types = append(types, eventType)
+	}
+
+	undone := make(map[int64]bool)
+	var candidates []*models.RoomEvent
+	var beforeID int64
+	for {
+		page, err := h.store.ListRecentEvents(room.ID, types, beforeID, eventPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range page {
+			if event.Type == models.EventUndo {
+				var p struct {
+					EventIDs []int64 `json:"event_ids"`
+				}
+				if err := json.Unmarshal(event.Payload, &p); err != nil {
+					return nil, err
+				}
+				for _, id := range p.EventIDs {
+					undone[id] = true
+				}
+				continue
+			}
+			if !undone[event.ID] && byModerator(room, event) {
+				candidates = append(candidates, event)
+				if len(candidates) == count {
+					return candidates, nil
+				}
+			}
+		}
+		if len(page) < eventPageSize {
+			return candidates, nil
+		}
+		beforeID = page[len(page)-1].ID
+	}
+}
+
+// byModerator reports whether the actor was a moderator or the owner when the
+// event happened. Events recorded before the role was stored in the payload
+// fall back to the actor's current role.
+func byModerator(room *models.Room, event *models.RoomEvent) bool {
+	var p struct {
+		Moderator *bool `json:"moderator"`
+	}
+	if err := json.Unmarshal(event.Payload, &p); err != nil || p.Moderator == nil {
+		return room.IsModeratorOrOwner(event.ActorID)
+	}
+	return *p.Moderator
+}
+
+// undoPayload holds the fields of undoable events needed to revert them
+type undoPayload struct {
+	Ticket                 *models.Ticket       `json:"ticket"`
+	Action                 *models.ActionTicket `json:"action"`
+	TicketID               string               `json:"ticket_id"`
+	PreviousParentTicketID *string              `json:"previous_parent_ticket_id"`
+	PreviousPhase          models.Phase         `json:"previous_phase"`
+	// Votes are the deleted ticket's votes, with the time they were cast
+	Votes []*models.Vote `json:"votes"`
+}
+
+// revertEvent restores the room to how it was before the event and adds the
+// change to undo, to be persisted with the rest of the undo. It returns the
+// broadcast announcing the change, or nil when there was nothing to revert:
+// events whose target no longer exists are skipped.
+func (h *Hub) revertEvent(room *models.Room, event *models.RoomEvent, p undoPayload, undo *models.Undo) func() {
+	switch event.Type {
+	case models.EventTicketDeleted:
+		if p.Ticket == nil {
+			return nil
+		}
+		if _, exists := room.GetTicket(p.Ticket.ID); exists {
+			return nil
+		}
+		if dropped := room.RestoreTicket(p.Ticket); dropped > 0 {
+			log.Printf("Dropped %d votes on ticket %s whose voters spent them since, undoing event %d", dropped, p.Ticket.ID, event.ID)
+		}
+		undo.RestoredTickets = append(undo.RestoredTickets, &models.RestoredTicket{Ticket: p.Ticket, Votes: p.Votes})
+		return func() {
+			h.broadcastTicket(room, MsgTicketAdded, p.Ticket)
+		}
+
+	case models.EventActionDeleted:
+		if p.Action == nil {
+			return nil
+		}
+		if _, exists := room.GetActionTicket(p.Action.ID); exists {
+			return nil
+		}
+		room.AddActionTicket(p.Action)
+		undo.RestoredActions = append(undo.RestoredActions, p.Action)
+		return func() {
+			response := Message{
+				Type: MsgActionAdded,
+				Payload: map[string]any{
+					"action": p.Action,
+				},
+			}
+			responseBytes, _ := json.Marshal(response)
+			h.BroadcastToApprovedParticipants(room.ID, responseBytes)
+		}
+
+	case models.EventTicketMerged, models.EventTicketUnmerged:
+		ticket, ok := room.GetTicket(p.TicketID)
+		if !ok {
+			log.Printf("Ticket %s no longer exists, skipping undo of event %d", p.TicketID, event.ID)
+			return nil
+		}
+		if parentID := p.PreviousParentTicketID; parentID != nil {
+			if _, ok := room.GetTicket(*parentID); !ok {
+				log.Printf("Ticket %s no longer exists, skipping undo of event %d", *parentID, event.ID)
+				return nil
+			}
+		}
+		if sameTicketID(ticket.DeduplicationTicketID, p.PreviousParentTicketID) {
+			return nil
+		}
+		room.Lock()
+		ticket.DeduplicationTicketID = p.PreviousParentTicketID
+		room.Unlock()
+		undo.ParentResets = append(undo.ParentResets, ticket)
+		return func() {
+			h.broadcastTicket(room, MsgTicketUpdated, ticket)
+		}
+
+	case models.EventPhaseChanged:
+		if p.PreviousPhase == "" || room.Phase == p.PreviousPhase {
+			return nil
+		}
+		if !room.HasPhase(p.PreviousPhase) {
+			log.Printf("Phase %s was removed from room %s, skipping undo of event %d", p.PreviousPhase, room.ID, event.ID)
+			return nil
+		}
+		change := room.SetPhase(p.PreviousPhase)
+		undo.PhaseChanges++
+		return func() {
+			h.broadcastPhase(room, change)
+		}
+
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,190 @@
+package websocket
+
+import (
+	"encoding/json"
+
+	"github.com/Armatorix/GoRetro/internal/models"
+)
+
+// broadcastToEachParticipant sends every approved participant of the room
+// their own version of a message. Viewers for whom build returns false are
+// sent nothing.
+func (h *Hub) broadcastToEachParticipant(room *models.Room, build func(viewerID string) (Message, bool)) {
+	room.RLock()
+	viewerIDs := make([]string, 0, len(room.Participants))
+	for id := range room.Participants {
+		viewerIDs = append(viewerIDs, id)
+	}
+	room.RUnlock()
+
+	for _, viewerID := range viewerIDs {
+		message, ok := build(viewerID)
+		if !ok {
+			continue
+		}
+		responseBytes, _ := json.Marshal(message)
+		h.SendToClient(room.ID, viewerID, responseBytes)
+	}
+}
+
+// broadcastTicket sends a ticket to approved participants, each seeing only
+// what models.Room.TicketView lets them see. Moderators who can't see the
+// ticket are told how many tickets are hidden instead.
+func (h *Hub) broadcastTicket(room *models.Room, msgType MessageType, ticket *models.Ticket) {
+	room.RLock()
+	personalized := room.PersonalizesTickets()
+	room.RUnlock()
+
+	if !personalized {
+		response := Message{
+			Type: msgType,
+			Payload: map[string]any{
+				"ticket": ticket,
+			},
+		}
+		responseBytes, _ := json.Marshal(response)
+		h.BroadcastToApprovedParticipants(room.ID, responseBytes)
+		return
+	}
+
+	h.broadcastToEachParticipant(room, func(viewerID string) (Message, bool) {
+		room.RLock()
+		defer room.RUnlock()
+		view := room.TicketView(ticket, viewerID)
+		if view == nil {
+			return hiddenTicketsMessage(room, viewerID)
+		}
+		return Message{
+			Type: msgType,
+			Payload: map[string]any{
+				"ticket": view,
+			},
+		}, true
+	})
+}
+
+// hiddenTicketsMessage tells a moderator how many tickets blind ticket
+// writing hides from them. Nothing is sent to other participants. The caller
+// must hold the room's read lock.
+func hiddenTicketsMessage(room *models.Room, viewerID string) (Message, bool) {
+	count, ok := room.HiddenTicketCount(viewerID)
+	if !ok {
+		return Message{}, false
+	}
+	return Message{
+		Type: MsgHiddenTickets,
+		Payload: map[string]any{
+			"count": count,
+		},
+	}, true
+}
+
+// broadcastTicketsRevealed sends every participant all tickets once blind
+// tickets are revealed
+func (h *Hub) broadcastTicketsRevealed(room *models.Room) {
+	h.broadcastToEachParticipant(room, func(viewerID string) (Message, bool) {
+		room.RLock()
+		defer room.RUnlock()
+		return Message{
+			Type: MsgTicketsRevealed,
+			Payload: map[string]any{
+				"tickets": room.TicketViews(viewerID),
+			},
+		}, true
+	})
+}
+
+// broadcastVoteUpdate tells participants that a user's votes on a ticket
+// changed. While votes are hidden only the voter is told.
+func (h *Hub) broadcastVoteUpdate(room *models.Room, ticket *models.Ticket, userID string) {
+	participant, _ := room.GetParticipant(userID)
+
+	visible := ticket
+	if room.VotesHidden() {
+		visible = ticket.WithVotesOf(userID)
+	}
+
+	response := Message{
+		Type: MsgVoteUpdated,
+		Payload: map[string]any{
+			"ticket_id":   ticket.ID,
+			"votes":       visible.Votes,
+			"voter_ids":   visible.VoterIDs,
+			"vote_counts": visible.VoteCounts(),
+			"user_id":     userID,
+			"votes_used":  participant.VotesUsed,
+		},
+	}
+	responseBytes, _ := json.Marshal(response)
+
+	if visible != ticket {
+		h.SendToClient(room.ID, userID, responseBytes)
+		return
+	}
+	h.BroadcastToApprovedParticipants(room.ID, responseBytes)
+}
+
+// broadcastReactionUpdate tells participants who can see a ticket that a
+// user's reaction to it changed
+func (h *Hub) broadcastReactionUpdate(room *models.Room, ticketID, userID, emoji string) {
+	ticket, ok := room.GetTicket(ticketID)
+	if !ok {
+		return
+	}
+
+	hidden := room.TicketsHidden()
+	room.RLock()
+	response := Message{
+		Type: MsgReactionUpdated,
+		Payload: map[string]any{
+			"ticket_id": ticketID,
+			"user_id":   userID,
+			"emoji":     emoji,
+			"reactions": ticket.Reactions,
+			"counts":    ticket.ReactionCounts(),
+		},
+	}
+	room.RUnlock()
+	responseBytes, _ := json.Marshal(response)
+
+	// Blind tickets can only be seen, and so reacted to, by their author
+	if hidden {
+		h.SendToClient(room.ID, userID, responseBytes)
+		return
+	}
+	h.BroadcastToApprovedParticipants(room.ID, responseBytes)
+}
+
+// broadcastComment tells participants who can see a ticket that one of its
+// comments was added, edited or deleted. Each sees the comment as
+// models.Room.CommentView lets them see it; deletions only carry its ID.
+func (h *Hub) broadcastComment(room *models.Room, msgType MessageType, comment *models.Comment) {
+	h.broadcastToEachParticipant(room, func(viewerID string) (Message, bool) {
+		room.RLock()
+		defer room.RUnlock()
+		ticket, ok := room.Tickets[comment.TicketID]
+		if !ok || room.TicketView(ticket, viewerID) == nil {
+			return Message{}, false
+		}
+		payload := map[string]any{
+			"ticket_id":  comment.TicketID,
+			"comment_id": comment.ID,
+		}
+		if msgType != MsgCommentDeleted {
+			payload["comment"] = room.CommentView(comment, viewerID)
+		}
+		return Message{Type: msgType, Payload: payload}, true
+	})
+}
+
+// sendRoomStateToParticipants re-sends every approved participant their view of the room
+func (h *Hub) sendRoomStateToParticipants(room *models.Room) {
+	h.broadcastToEachParticipant(room, func(viewerID string) (Message, bool) {
+		room.RLock()
+		defer room.RUnlock()
+		return Message{
+			Type:    MsgRoomState,
+			Payload: roomStatePayload(room, viewerID),
+		}, true
+	})
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"database/sql"
 	"embed"
+	"fmt"
 	"html/template"
 	"io"
 	"io/fs"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/Armatorix/GoRetro/internal/chatcompletion"
 	"github.com/Armatorix/GoRetro/internal/handlers"
@@ -37,7 +40,8 @@ func (t *TemplateRenderer) Render(w io.Writer, name string, data any, c echo.Con
 	return t.templates.ExecuteTemplate(w, name, data)
 }
 
-func main() {
+// openDB connects to the PostgreSQL database configured by DATABASE_URL
+func openDB() *sql.DB {
 	// Get database URL from environment
 	dbURL := os.Getenv("DATABASE_URL")
 	if dbURL == "" {
@@ -49,7 +53,6 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
-	defer db.Close()
 
 	// Ping database to verify connection
 	if err := db.Ping(); err != nil {
@@ -58,15 +61,102 @@ func main() {
 
 	log.Println("Connected to database successfully")
 
-	// Initialize store and hub
-	store := models.NewRoomStore(db)
+	return db
+}
+
+// newStore creates the room store selected by STORE_BACKEND ("postgres" by default, or "memory")
+func newStore() models.Store {
+	backend := os.Getenv("STORE_BACKEND")
+	if backend == "memory" {
+		log.Println("Using in-memory store - data will not survive a restart")
+		return models.NewMemoryStore()
+	}
+	if backend != "" && backend != "postgres" {
+		log.Fatalf("Unknown STORE_BACKEND %q (expected \"postgres\" or \"memory\")", backend)
+	}
 
-	// Initialize database schema
-	if err := store.InitSchema(); err != nil {
+	store := models.NewRoomStore(openDB())
+
+	// Apply pending schema migrations unless disabled
+	if os.Getenv("AUTO_MIGRATE") == "false" {
+		log.Println("AUTO_MIGRATE=false, skipping schema migrations")
+		return store
+	}
+	applied, err := store.MigrateUp()
+	if err != nil {
 		log.Fatalf("Failed to initialize database schema: %v", err)
 	}
 
-	log.Println("Database schema initialized")
+	log.Printf("Database schema initialized (%d migrations applied)", len(applied))
+
+	return store
+}
+
+// runMigrate implements the "migrate up|down [steps]|status" subcommand
+func runMigrate(args []string) {
+	usage := "usage: goretro migrate up|down [steps]|status"
+	if len(args) == 0 {
+		log.Fatal(usage)
+	}
+
+	store := models.NewRoomStore(openDB())
+
+	switch args[0] {
+	case "up":
+		applied, err := store.MigrateUp()
+		for _, m := range applied {
+			fmt.Printf("applied %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("no pending migrations")
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				log.Fatalf("Invalid number of steps %q", args[1])
+			}
+			steps = n
+		}
+		reverted, err := store.MigrateDown(steps)
+		for _, m := range reverted {
+			fmt.Printf("reverted %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		if len(reverted) == 0 {
+			fmt.Println("no applied migrations")
+		}
+	case "status":
+		statuses, err := store.MigrationStatus()
+		if err != nil {
+			log.Fatalf("Failed to read migration status: %v", err)
+		}
+		for _, st := range statuses {
+			applied := "pending"
+			if st.AppliedAt != nil {
+				applied = "applied " + st.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d_%-40s %s\n", st.Version, st.Name, applied)
+		}
+	default:
+		log.Fatal(usage)
+	}
+}
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(os.Args[2:])
+		return
+	}
+
+	// Initialize store and hub
+	store := newStore()
 
 	hub := websocket.NewHub(store)
 	go hub.Run()
@@ -138,9 +228,26 @@ func main() {
 	e.GET("/rooms", h.ListRooms)
 	e.GET("/rooms/:id", h.GetRoom)
 	e.DELETE("/rooms/:id", h.DeleteRoom)
+	// Team routes
+	e.POST("/teams", h.CreateTeam)
+	e.GET("/teams", h.ListTeams)
+	e.GET("/teams/:id", h.GetTeam)
+	e.DELETE("/teams/:id", h.DeleteTeam)
+	e.GET("/teams/:id/rooms", h.ListTeamRooms)
+	e.PUT("/teams/:id/members/:user_id", h.PutTeamMember)
+	e.DELETE("/teams/:id/members/:user_id", h.RemoveTeamMember)
 
 	// API routes
 	e.GET("/api/rooms/:id", h.GetRoomAPI)
+	e.GET("/api/rooms/:id/events", h.GetRoomEvents)
+	e.GET("/api/rooms/:id/export", h.ExportRoom)
+	e.GET("/api/analytics", h.GetAnalytics)
+	e.GET("/api/health-checks", h.GetHealthHistory)
+	e.GET("/api/templates", h.ListTemplates)
+	e.POST("/api/templates", h.CreateTemplate)
+	e.GET("/api/templates/:id", h.GetTemplate)
+	e.PUT("/api/templates/:id", h.UpdateTemplate)
+	e.DELETE("/api/templates/:id", h.DeleteTemplate)
 
 	// Auth routes
 	e.GET("/logout", h.Logout)
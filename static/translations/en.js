@@ -18,7 +18,17 @@ window.translations.en = {
             title: "Create New Retrospective",
             roomNameLabel: "Room Name",
             roomNamePlaceholder: "Retrospective",
+            templateLabel: "Format",
+            defaultTemplate: "Went well, To improve, Ideas",
             votesLabel: "Votes per User",
+            maxVotesPerTicketLabel: "Max Votes per Ticket",
+            hiddenVotesLabel: "Hidden voting",
+            anonymousTicketsLabel: "Anonymous tickets",
+            blindTicketsLabel: "Blind ticket writing",
+            previousRoomLabel: "Review open actions from",
+            noPreviousRoom: "No previous retrospective",
+            teamLabel: "Team",
+            noTeam: "No team",
             createButton: "Create Room"
         },
         myRooms: {
@@ -71,14 +81,63 @@ window.translations.en = {
             pleaseWait: "Please wait while your access is being reviewed."
         },
         phases: {
+            icebreaker: "Icebreaker",
+            review: "Review",
             ticketing: "Ticketing",
             merging: "Merging",
             voting: "Voting",
             discussion: "Discussion",
-            summary: "Summary"
+            summary: "Summary",
+            checkout: "Check-out",
+            health_check: "Health check"
         },
+        phaseChange: {
+            confirmBack: "Go back to {phase}? Everyone will return to that phase.",
+            took: "{phase} took {minutes} min"
+        },
+        timer: {
+            start: "⏱ Start",
+            pause: "⏸ Pause",
+            resume: "▶ Resume",
+            extend: "+1 min",
+            stop: "■ Stop",
+            autoAdvance: "Next phase when done",
+            expired: "Time's up!"
+        },
+        health: {
+            title: "Health check",
+            respondents: "{count} rated",
+            noRatings: "No ratings yet",
+            result: "Health {score}% · trend {trend}",
+            colors: {
+                green: "Green: we're in good shape",
+                yellow: "Yellow: some problems, but not a disaster",
+                red: "Red: this really sucks and needs improving"
+            },
+            trends: {
+                up: "Getting better",
+                stable: "Staying the same",
+                down: "Getting worse"
+            }
+        },
+        polls: {
+            mood: "Mood check-in",
+            moodHint: "How are you feeling? 1 is awful, 5 is great. Answers are anonymous.",
+            roti: "Return on time invested",
+            rotiHint: "Was this retrospective worth your time? 1 is a waste, 5 is excellent. Answers are anonymous.",
+            responses: "{count} answered",
+            answered: "Thanks, your answer was counted.",
+            close: "Close poll",
+            confirmClose: "Close the poll and show the results to everyone?",
+            average: "Average: {average}"
+        },
+        undo: "↶ Undo",
+        revealVotes: "👁 Reveal Votes",
+        revealTickets: "👁 Reveal Tickets",
+        export: "⬇ Export",
         votes: {
-            info: "Votes used: {used} / {total}"
+            info: "Votes used: {used} / {total}",
+            hidden: "Votes are hidden until a moderator reveals them"
         },
         tickets: {
             title: "Tickets",
@@ -87,10 +146,16 @@ window.translations.en = {
             cancel: "Cancel",
             submit: "Submit",
             noTickets: "No tickets yet. Be the first to add one!",
+            hidden: "Tickets are only shown to their authors until a moderator reveals them",
+            hiddenCount: "{count} tickets from others are hidden until revealed",
+            otherColumn: "Other",
+            moveToColumn: "Move to column",
             votes: "{count} votes",
+            yourVotes: "Your votes: {count}",
             coveredBadge: "✓ Covered",
             markCovered: "Mark as covered/discussed",
             markNotCovered: "Mark as not covered",
+            addReaction: "Add reaction",
             unmergeAll: "Unmerge all",
             separateFromParent: "Separate from parent",
             deleteConfirmTitle: "Delete Ticket",
@@ -101,6 +166,17 @@ window.translations.en = {
             autoMergeConfirm: "Use AI to automatically group similar tickets? This will analyze ticket content and suggest merges.",
             autoMergeComplete: "Auto-merge completed! {count} tickets were grouped."
         },
+        comments: {
+            count: "{count} comments",
+            placeholder: "Add a comment...",
+            add: "Comment",
+            edit: "Edit",
+            edited: "edited",
+            delete: "Delete",
+            editPrompt: "Edit comment:",
+            deleteConfirm: "Are you sure you want to delete this comment?",
+            anonymous: "Anonymous"
+        },
         actions: {
             title: "Action Items",
             addButton: "+ Add Action",
@@ -109,6 +185,22 @@ window.translations.en = {
             selectAll: "Select All",
             deselectAll: "Deselect All",
             assignedTo: "Assigned to: {names}",
+            ownerLabel: "Owner:",
+            dueDateLabel: "Due date:",
+            noOwner: "No owner",
+            overdue: "Overdue",
+            carriedOver: "Carried over from the previous retrospective",
+            review: {
+                done: "Done",
+                relevant: "Still relevant",
+                dropped: "Dropped"
+            },
+            status: {
+                open: "Open",
+                in_progress: "In progress",
+                done: "Done",
+                dropped: "Dropped"
+            },
             noActions: "No action items yet.",
             cancel: "Cancel",
             add: "Add Action",
@@ -142,7 +234,13 @@ window.translations.en = {
             rejectConfirm: "Are you sure you want to reject this participant?",
             noParticipants: "No participants",
             autoApproveLabel: "Auto-approve participants",
-            autoApproveHelp: "New participants join automatically"
+            autoApproveHelp: "New participants join automatically",
+            hiddenVotesLabel: "Hidden voting",
+            hiddenVotesHelp: "Participants only see their own votes until revealed",
+            anonymousTicketsLabel: "Anonymous tickets",
+            anonymousTicketsHelp: "Ticket authors are only shown to themselves",
+            blindTicketsLabel: "Blind ticket writing",
+            blindTicketsHelp: "Tickets are only shown to their authors until revealed or ticketing ends"
         },
         messages: {
             cannotPerformDisconnected: "Cannot perform action: disconnected from server",
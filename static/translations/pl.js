@@ -18,7 +18,17 @@ window.translations.pl = {
             title: "Utwórz Nową Retrospektywę",
             roomNameLabel: "Nazwa Pokoju",
             roomNamePlaceholder: "Retrospektywa",
+            templateLabel: "Format",
+            defaultTemplate: "Co poszło dobrze, Do poprawy, Pomysły",
             votesLabel: "Głosy na Użytkownika",
+            maxVotesPerTicketLabel: "Maks. Głosów na Notatkę",
+            hiddenVotesLabel: "Ukryte głosowanie",
+            anonymousTicketsLabel: "Anonimowe notatki",
+            blindTicketsLabel: "Pisanie notatek w ukryciu",
+            previousRoomLabel: "Przejrzyj otwarte zadania z",
+            noPreviousRoom: "Brak poprzedniej retrospektywy",
+            teamLabel: "Zespół",
+            noTeam: "Bez zespołu",
             createButton: "Utwórz Pokój"
         },
         myRooms: {
@@ -71,14 +81,63 @@ window.translations.pl = {
             pleaseWait: "Proszę czekać, Twój dostęp jest sprawdzany."
         },
         phases: {
+            icebreaker: "Rozgrzewka",
+            review: "Przegląd",
             ticketing: "Notatki",
             merging: "Łączenie",
             voting: "Głosowanie",
             discussion: "Dyskusja",
-            summary: "Podsumowanie"
+            summary: "Podsumowanie",
+            checkout: "Zamknięcie",
+            health_check: "Kondycja zespołu"
         },
+        phaseChange: {
+            confirmBack: "Wrócić do etapu {phase}? Wszyscy wrócą do tego etapu.",
+            took: "{phase} trwało {minutes} min"
+        },
+        timer: {
+            start: "⏱ Start",
+            pause: "⏸ Pauza",
+            resume: "▶ Wznów",
+            extend: "+1 min",
+            stop: "■ Zatrzymaj",
+            autoAdvance: "Następny etap po czasie",
+            expired: "Czas minął!"
+        },
+        health: {
+            title: "Kondycja zespołu",
+            respondents: "Ocen: {count}",
+            noRatings: "Brak ocen",
+            result: "Kondycja {score}% · trend {trend}",
+            colors: {
+                green: "Zielony: jest dobrze",
+                yellow: "Żółty: są problemy, ale nie katastrofa",
+                red: "Czerwony: jest źle, trzeba to poprawić"
+            },
+            trends: {
+                up: "Poprawia się",
+                stable: "Bez zmian",
+                down: "Pogarsza się"
+            }
+        },
+        polls: {
+            mood: "Nastrój na start",
+            moodHint: "Jak się czujesz? 1 to fatalnie, 5 to świetnie. Odpowiedzi są anonimowe.",
+            roti: "Zwrot z zainwestowanego czasu",
+            rotiHint: "Czy ta retrospektywa była warta Twojego czasu? 1 to strata czasu, 5 to doskonale. Odpowiedzi są anonimowe.",
+            responses: "Odpowiedzi: {count}",
+            answered: "Dziękujemy, Twoja odpowiedź została zapisana.",
+            close: "Zamknij ankietę",
+            confirmClose: "Zamknąć ankietę i pokazać wyniki wszystkim?",
+            average: "Średnia: {average}"
+        },
+        undo: "↶ Cofnij",
+        revealVotes: "👁 Odkryj Głosy",
+        revealTickets: "👁 Odkryj Notatki",
+        export: "⬇ Eksportuj",
         votes: {
-            info: "Wykorzystane głosy: {used} / {total}"
+            info: "Wykorzystane głosy: {used} / {total}",
+            hidden: "Głosy są ukryte, dopóki moderator ich nie odkryje"
         },
         tickets: {
             title: "Notatki",
@@ -87,10 +146,16 @@ window.translations.pl = {
             cancel: "Anuluj",
             submit: "Wyślij",
             noTickets: "Brak notatek. Bądź pierwszy, który doda!",
+            hidden: "Notatki są widoczne tylko dla autorów, dopóki moderator ich nie odkryje",
+            hiddenCount: "Ukryte notatki innych osób: {count}",
+            otherColumn: "Inne",
+            moveToColumn: "Przenieś do kolumny",
             votes: "{count} głosów",
+            yourVotes: "Twoje głosy: {count}",
             coveredBadge: "Omówione",
             markCovered: "Oznacz jako omówione",
             markNotCovered: "Oznacz jako nieomówione",
+            addReaction: "Dodaj reakcję",
             unmergeAll: "Rozdziel wszystkie",
             separateFromParent: "Oddziel od głównej",
             deleteConfirmTitle: "Usuń Notatkę",
@@ -101,6 +166,17 @@ window.translations.pl = {
             autoMergeConfirm: "Użyć AI do automatycznego grupowania podobnych notatek? System przeanalizuje treść i zasugeruje połączenia.",
             autoMergeComplete: "Auto-łączenie zakończone! {count} notatek zostało zgrupowanych."
         },
+        comments: {
+            count: "Komentarze: {count}",
+            placeholder: "Dodaj komentarz...",
+            add: "Skomentuj",
+            edit: "Edytuj",
+            edited: "edytowano",
+            delete: "Usuń",
+            editPrompt: "Edytuj komentarz:",
+            deleteConfirm: "Czy na pewno chcesz usunąć ten komentarz?",
+            anonymous: "Anonim"
+        },
         actions: {
             title: "Zadania do Wykonania",
             addButton: "+ Dodaj Zadanie",
@@ -109,6 +185,22 @@ window.translations.pl = {
             selectAll: "Zaznacz Wszystkie",
             deselectAll: "Odznacz Wszystkie",
             assignedTo: "Przypisane do: {names}",
+            ownerLabel: "Właściciel:",
+            dueDateLabel: "Termin:",
+            noOwner: "Brak właściciela",
+            overdue: "Po terminie",
+            carriedOver: "Przeniesione z poprzedniej retrospektywy",
+            review: {
+                done: "Zrobione",
+                relevant: "Nadal aktualne",
+                dropped: "Porzucone"
+            },
+            status: {
+                open: "Otwarte",
+                in_progress: "W toku",
+                done: "Zrobione",
+                dropped: "Porzucone"
+            },
             noActions: "Brak zadań do wykonania.",
             cancel: "Anuluj",
             add: "Dodaj Zadanie",
@@ -142,7 +234,13 @@ window.translations.pl = {
             rejectConfirm: "Czy na pewno chcesz odrzucić tego uczestnika?",
             noParticipants: "Brak uczestników",
             autoApproveLabel: "Automatyczne zatwierdzanie uczestników",
-            autoApproveHelp: "Nowi uczestnicy dołączają automatycznie"
+            autoApproveHelp: "Nowi uczestnicy dołączają automatycznie",
+            hiddenVotesLabel: "Ukryte głosowanie",
+            hiddenVotesHelp: "Uczestnicy widzą tylko własne głosy do czasu odkrycia",
+            anonymousTicketsLabel: "Anonimowe notatki",
+            anonymousTicketsHelp: "Autorzy notatek są widoczni tylko dla siebie",
+            blindTicketsLabel: "Pisanie notatek w ukryciu",
+            blindTicketsHelp: "Notatki są widoczne tylko dla autorów do czasu odkrycia lub końca etapu notatek"
         },
         messages: {
             cannotPerformDisconnected: "Nie można wykonać akcji: brak połączenia z serwerem",